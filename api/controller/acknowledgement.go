@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+)
+
+// AcknowledgeTriggerState acknowledges current bad state of trigger metric or of the whole trigger if metric is empty
+// on behalf of current user and notifies trigger subscribers about it
+func AcknowledgeTriggerState(dataBase moira.Database, triggerID string, metric string, userLogin string, timestamp int64) (*dto.Acknowledgement, *api.ErrorResponse) {
+	trigger, err := dataBase.GetTrigger(triggerID)
+	if err != nil {
+		if err == database.ErrNil {
+			return nil, api.ErrorNotFound("Trigger not found")
+		}
+		return nil, api.ErrorInternalServer(err)
+	}
+	lastCheck, err := dataBase.GetTriggerLastCheck(triggerID)
+	if err != nil {
+		if err == database.ErrNil {
+			return nil, api.ErrorNotFound("Trigger has not been checked yet")
+		}
+		return nil, api.ErrorInternalServer(err)
+	}
+
+	event := moira.NotificationEvent{
+		IsTriggerEvent: metric == "",
+		TriggerID:      triggerID,
+		Timestamp:      timestamp,
+		Metric:         trigger.Name,
+		State:          lastCheck.State,
+	}
+	if metric != "" {
+		metricState, ok := lastCheck.Metrics[metric]
+		if !ok {
+			return nil, api.ErrorNotFound(fmt.Sprintf("Metric '%s' not found in trigger check", metric))
+		}
+		event.Metric = metric
+		event.State = metricState.State
+		event.Value = metricState.Value
+	}
+	if event.State == "OK" {
+		return nil, api.ErrorInvalidRequest(fmt.Errorf("state %s can not be acknowledged", event.State))
+	}
+
+	acknowledgement := moira.Acknowledgement{
+		User:      userLogin,
+		State:     event.State,
+		Timestamp: timestamp,
+	}
+	if err := dataBase.SetTriggerCheckAcknowledgement(triggerID, metric, &acknowledgement); err != nil {
+		if err == database.ErrNil {
+			return nil, api.ErrorInvalidRequest(fmt.Errorf("state %s has changed while being acknowledged", event.State))
+		}
+		return nil, api.ErrorInternalServer(err)
+	}
+
+	message := fmt.Sprintf("Acknowledged by %s", userLogin)
+	event.OldState = event.State
+	event.Message = &message
+	if err := dataBase.PushNotificationEvent(&event, true); err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	return &dto.Acknowledgement{
+		Acknowledgement: acknowledgement,
+		TriggerID:       triggerID,
+		Metric:          metric,
+	}, nil
+}
+
+// RemoveTriggerStateAcknowledgement removes acknowledgement of trigger metric or of the whole trigger if metric is empty
+func RemoveTriggerStateAcknowledgement(dataBase moira.Database, triggerID string, metric string) *api.ErrorResponse {
+	if err := dataBase.SetTriggerCheckAcknowledgement(triggerID, metric, nil); err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/mock/moira-alert"
+)
+
+func TestAcknowledgeTriggerState(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	triggerID := "trigger1"
+	var timestamp int64 = 1546387200
+	value := 42.0
+	trigger := moira.Trigger{ID: triggerID, Name: "Super Trigger"}
+	lastCheck := moira.CheckData{
+		State: "ERROR",
+		Metrics: map[string]moira.MetricState{
+			"metric.bad": {State: "WARN", Value: &value},
+			"metric.ok":  {State: "OK"},
+		},
+	}
+	message := "Acknowledged by user"
+
+	Convey("Acknowledge trigger state", t, func() {
+		acknowledgement := moira.Acknowledgement{User: "user", State: "ERROR", Timestamp: timestamp}
+		dataBase.EXPECT().GetTrigger(triggerID).Return(trigger, nil)
+		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(lastCheck, nil)
+		dataBase.EXPECT().SetTriggerCheckAcknowledgement(triggerID, "", &acknowledgement).Return(nil)
+		dataBase.EXPECT().PushNotificationEvent(&moira.NotificationEvent{
+			IsTriggerEvent: true,
+			TriggerID:      triggerID,
+			Timestamp:      timestamp,
+			Metric:         "Super Trigger",
+			State:          "ERROR",
+			OldState:       "ERROR",
+			Message:        &message,
+		}, true).Return(nil)
+		actual, err := AcknowledgeTriggerState(dataBase, triggerID, "", "user", timestamp)
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, &dto.Acknowledgement{Acknowledgement: acknowledgement, TriggerID: triggerID})
+	})
+
+	Convey("Acknowledge metric state", t, func() {
+		acknowledgement := moira.Acknowledgement{User: "user", State: "WARN", Timestamp: timestamp}
+		dataBase.EXPECT().GetTrigger(triggerID).Return(trigger, nil)
+		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(lastCheck, nil)
+		dataBase.EXPECT().SetTriggerCheckAcknowledgement(triggerID, "metric.bad", &acknowledgement).Return(nil)
+		dataBase.EXPECT().PushNotificationEvent(&moira.NotificationEvent{
+			TriggerID: triggerID,
+			Timestamp: timestamp,
+			Metric:    "metric.bad",
+			State:     "WARN",
+			OldState:  "WARN",
+			Value:     &value,
+			Message:   &message,
+		}, true).Return(nil)
+		actual, err := AcknowledgeTriggerState(dataBase, triggerID, "metric.bad", "user", timestamp)
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, &dto.Acknowledgement{Acknowledgement: acknowledgement, TriggerID: triggerID, Metric: "metric.bad"})
+	})
+
+	Convey("OK state can not be acknowledged", t, func() {
+		dataBase.EXPECT().GetTrigger(triggerID).Return(trigger, nil)
+		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(lastCheck, nil)
+		actual, err := AcknowledgeTriggerState(dataBase, triggerID, "metric.ok", "user", timestamp)
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("state OK can not be acknowledged")))
+		So(actual, ShouldBeNil)
+	})
+
+	Convey("Unknown metric", t, func() {
+		dataBase.EXPECT().GetTrigger(triggerID).Return(trigger, nil)
+		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(lastCheck, nil)
+		actual, err := AcknowledgeTriggerState(dataBase, triggerID, "metric.unknown", "user", timestamp)
+		So(err, ShouldResemble, api.ErrorNotFound("Metric 'metric.unknown' not found in trigger check"))
+		So(actual, ShouldBeNil)
+	})
+
+	Convey("Trigger is not checked", t, func() {
+		dataBase.EXPECT().GetTrigger(triggerID).Return(trigger, nil)
+		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, database.ErrNil)
+		actual, err := AcknowledgeTriggerState(dataBase, triggerID, "", "user", timestamp)
+		So(err, ShouldResemble, api.ErrorNotFound("Trigger has not been checked yet"))
+		So(actual, ShouldBeNil)
+	})
+
+	Convey("State has changed while being acknowledged", t, func() {
+		dataBase.EXPECT().GetTrigger(triggerID).Return(trigger, nil)
+		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(lastCheck, nil)
+		dataBase.EXPECT().SetTriggerCheckAcknowledgement(triggerID, "", gomock.Any()).Return(database.ErrNil)
+		actual, err := AcknowledgeTriggerState(dataBase, triggerID, "", "user", timestamp)
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("state ERROR has changed while being acknowledged")))
+		So(actual, ShouldBeNil)
+	})
+
+	Convey("Error set acknowledgement", t, func() {
+		expected := fmt.Errorf("Oooops! Error set")
+		dataBase.EXPECT().GetTrigger(triggerID).Return(trigger, nil)
+		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(lastCheck, nil)
+		dataBase.EXPECT().SetTriggerCheckAcknowledgement(triggerID, "", gomock.Any()).Return(expected)
+		actual, err := AcknowledgeTriggerState(dataBase, triggerID, "", "user", timestamp)
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+		So(actual, ShouldBeNil)
+	})
+}
+
+func TestRemoveTriggerStateAcknowledgement(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	triggerID := "trigger1"
+
+	Convey("Success", t, func() {
+		dataBase.EXPECT().SetTriggerCheckAcknowledgement(triggerID, "metric", nil).Return(nil)
+		err := RemoveTriggerStateAcknowledgement(dataBase, triggerID, "metric")
+		So(err, ShouldBeNil)
+	})
+
+	Convey("Error", t, func() {
+		expected := fmt.Errorf("Oooops! Error remove")
+		dataBase.EXPECT().SetTriggerCheckAcknowledgement(triggerID, "metric", nil).Return(expected)
+		err := RemoveTriggerStateAcknowledgement(dataBase, triggerID, "metric")
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+	})
+}
@@ -45,6 +45,11 @@ func CreateContact(dataBase moira.Database, contact *dto.Contact, userLogin stri
 			return api.ErrorInvalidRequest(fmt.Errorf("contact with this ID already exists"))
 		}
 	}
+	if contactData.Type == moira.RotationContactType {
+		if _, err := CheckUserPermissionsForRotation(dataBase, contactData.Value, userLogin); err != nil {
+			return err
+		}
+	}
 
 	if err := dataBase.SaveContact(&contactData); err != nil {
 		return api.ErrorInternalServer(err)
@@ -58,6 +63,11 @@ func CreateContact(dataBase moira.Database, contact *dto.Contact, userLogin stri
 func UpdateContact(dataBase moira.Database, contactDTO dto.Contact, contactData moira.ContactData) (dto.Contact, *api.ErrorResponse) {
 	contactData.Type = contactDTO.Type
 	contactData.Value = contactDTO.Value
+	if contactData.Type == moira.RotationContactType {
+		if _, err := CheckUserPermissionsForRotation(dataBase, contactData.Value, contactData.User); err != nil {
+			return contactDTO, err
+		}
+	}
 	if err := dataBase.SaveContact(&contactData); err != nil {
 		return contactDTO, api.ErrorInternalServer(err)
 	}
@@ -66,7 +76,7 @@ func UpdateContact(dataBase moira.Database, contactDTO dto.Contact, contactData
 	return contactDTO, nil
 }
 
-// RemoveContact deletes notification contact for current user and remove contactID from all subscriptions
+// RemoveContact deletes notification contact for current user if it is not used in subscriptions and rotations
 func RemoveContact(database moira.Database, contactID string, userLogin string) *api.ErrorResponse {
 	subscriptionIDs, err := database.GetUserSubscriptionIDs(userLogin)
 	if err != nil {
@@ -84,9 +94,8 @@ func RemoveContact(database moira.Database, contactID string, userLogin string)
 		if subscription == nil {
 			continue
 		}
-		for i, contact := range subscription.Contacts {
+		for _, contact := range subscription.GetAllContacts() {
 			if contact == contactID {
-				subscription.Contacts = append(subscription.Contacts[:i], subscription.Contacts[i+1:]...)
 				subscriptionsWithDeletingContact = append(subscriptionsWithDeletingContact, subscription)
 				break
 			}
@@ -112,6 +121,21 @@ func RemoveContact(database moira.Database, contactID string, userLogin string)
 		return api.ErrorInvalidRequest(fmt.Errorf(errBuffer.String()))
 	}
 
+	rotations, err := database.GetUserRotations(userLogin)
+	if err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	for _, rotation := range rotations {
+		if rotation == nil {
+			continue
+		}
+		for _, contact := range rotation.GetContactIDs() {
+			if contact == contactID {
+				return api.ErrorInvalidRequest(fmt.Errorf("This contact is being used in rotation %s", rotation.ID))
+			}
+		}
+	}
+
 	if err := database.RemoveContact(contactID); err != nil {
 		return api.ErrorInternalServer(err)
 	}
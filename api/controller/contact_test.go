@@ -189,6 +189,7 @@ func TestRemoveContact(t *testing.T) {
 	Convey("Delete contact without user subscriptions", t, func() {
 		dataBase.EXPECT().GetUserSubscriptionIDs(userLogin).Return(make([]string, 0), nil)
 		dataBase.EXPECT().GetSubscriptions(make([]string, 0)).Return(make([]*moira.SubscriptionData, 0), nil)
+		dataBase.EXPECT().GetUserRotations(userLogin).Return(make([]*moira.Rotation, 0), nil)
 		dataBase.EXPECT().RemoveContact(contactID).Return(nil)
 		dataBase.EXPECT().SaveSubscriptions(make([]*moira.SubscriptionData, 0)).Return(nil)
 		err := RemoveContact(dataBase, contactID, userLogin)
@@ -203,12 +204,36 @@ func TestRemoveContact(t *testing.T) {
 
 		dataBase.EXPECT().GetUserSubscriptionIDs(userLogin).Return([]string{subscription.ID}, nil)
 		dataBase.EXPECT().GetSubscriptions([]string{subscription.ID}).Return([]*moira.SubscriptionData{subscription}, nil)
+		dataBase.EXPECT().GetUserRotations(userLogin).Return(make([]*moira.Rotation, 0), nil)
 		dataBase.EXPECT().RemoveContact(contactID).Return(nil)
 		dataBase.EXPECT().SaveSubscriptions(make([]*moira.SubscriptionData, 0)).Return(nil)
 		err := RemoveContact(dataBase, contactID, userLogin)
 		So(err, ShouldBeNil)
 	})
 
+	Convey("Delete contact used in rotation", t, func() {
+		Convey("As participant", func() {
+			rotation := &moira.Rotation{ID: uuid.NewV4().String(), Participants: []string{uuid.NewV4().String(), contactID}}
+			dataBase.EXPECT().GetUserSubscriptionIDs(userLogin).Return(make([]string, 0), nil)
+			dataBase.EXPECT().GetSubscriptions(make([]string, 0)).Return(make([]*moira.SubscriptionData, 0), nil)
+			dataBase.EXPECT().GetUserRotations(userLogin).Return([]*moira.Rotation{rotation}, nil)
+			err := RemoveContact(dataBase, contactID, userLogin)
+			So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("This contact is being used in rotation %s", rotation.ID)))
+		})
+		Convey("As override", func() {
+			rotation := &moira.Rotation{
+				ID:           uuid.NewV4().String(),
+				Participants: []string{uuid.NewV4().String()},
+				Overrides:    []moira.RotationOverride{{Contact: contactID, Start: 100, End: 200}},
+			}
+			dataBase.EXPECT().GetUserSubscriptionIDs(userLogin).Return(make([]string, 0), nil)
+			dataBase.EXPECT().GetSubscriptions(make([]string, 0)).Return(make([]*moira.SubscriptionData, 0), nil)
+			dataBase.EXPECT().GetUserRotations(userLogin).Return([]*moira.Rotation{nil, rotation}, nil)
+			err := RemoveContact(dataBase, contactID, userLogin)
+			So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("This contact is being used in rotation %s", rotation.ID)))
+		})
+	})
+
 	Convey("Error tests", t, func() {
 		Convey("GetUserSubscriptionIDs", func() {
 			expectedError := fmt.Errorf("Oooops! Can not read user subscription ids")
@@ -223,6 +248,14 @@ func TestRemoveContact(t *testing.T) {
 			err := RemoveContact(dataBase, contactID, userLogin)
 			So(err, ShouldResemble, api.ErrorInternalServer(expectedError))
 		})
+		Convey("GetUserRotations", func() {
+			expectedError := fmt.Errorf("Oooops! Can not read user rotations")
+			dataBase.EXPECT().GetUserSubscriptionIDs(userLogin).Return(make([]string, 0), nil)
+			dataBase.EXPECT().GetSubscriptions(make([]string, 0)).Return(make([]*moira.SubscriptionData, 0), nil)
+			dataBase.EXPECT().GetUserRotations(userLogin).Return(nil, expectedError)
+			err := RemoveContact(dataBase, contactID, userLogin)
+			So(err, ShouldResemble, api.ErrorInternalServer(expectedError))
+		})
 		Convey("RemoveContact", func() {
 			subscription := moira.SubscriptionData{
 				Contacts: []string{contactID},
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"sort"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+)
+
+// GetTriggerEscalations gets scheduled escalation notifications of trigger alerts ordered by time of sending
+func GetTriggerEscalations(dataBase moira.Database, triggerID string) (*dto.TriggerEscalations, *api.ErrorResponse) {
+	notifications, err := dataBase.GetTriggerEscalations(triggerID)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	var lastCheck *moira.CheckData
+	checkData, err := dataBase.GetTriggerLastCheck(triggerID)
+	if err != nil && err != database.ErrNil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	if err == nil {
+		lastCheck = &checkData
+	}
+
+	escalations := dto.TriggerEscalations{
+		List: make([]*dto.Escalation, 0),
+	}
+	for _, notification := range notifications {
+		escalation := &dto.Escalation{
+			Step:           notification.EscalationStep,
+			ContactID:      notification.Contact.ID,
+			Metric:         notification.Event.Metric,
+			State:          notification.Event.State,
+			EventTimestamp: notification.Event.Timestamp,
+			Timestamp:      notification.Timestamp,
+			Cancelled:      lastCheck == nil || !lastCheck.IsEscalationActual(&notification.Event),
+		}
+		if notification.Event.SubscriptionID != nil {
+			escalation.SubscriptionID = *notification.Event.SubscriptionID
+		}
+		escalations.List = append(escalations.List, escalation)
+	}
+	sort.SliceStable(escalations.List, func(i, j int) bool {
+		return escalations.List[i].Timestamp < escalations.List[j].Timestamp
+	})
+	return &escalations, nil
+}
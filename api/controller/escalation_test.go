@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/mock/moira-alert"
+)
+
+func TestGetTriggerEscalations(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	triggerID := "trigger1"
+	subscriptionID := "subscription1"
+	event := moira.NotificationEvent{TriggerID: triggerID, Metric: "metric", State: "ERROR", OldState: "OK", Timestamp: 100, SubscriptionID: &subscriptionID}
+	notifications := []*moira.ScheduledNotification{
+		{Event: event, Contact: moira.ContactData{ID: "contact3"}, Timestamp: 1900, EscalationStep: 2},
+		{Event: event, Contact: moira.ContactData{ID: "contact2"}, Timestamp: 700, EscalationStep: 1},
+	}
+
+	Convey("Pending escalations", t, func() {
+		dataBase.EXPECT().GetTriggerEscalations(triggerID).Return(notifications, nil)
+		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{
+			Metrics: map[string]moira.MetricState{"metric": {State: "ERROR"}},
+		}, nil)
+		actual, err := GetTriggerEscalations(dataBase, triggerID)
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, &dto.TriggerEscalations{
+			List: []*dto.Escalation{
+				{SubscriptionID: subscriptionID, Step: 1, ContactID: "contact2", Metric: "metric", State: "ERROR", EventTimestamp: 100, Timestamp: 700},
+				{SubscriptionID: subscriptionID, Step: 2, ContactID: "contact3", Metric: "metric", State: "ERROR", EventTimestamp: 100, Timestamp: 1900},
+			},
+		})
+	})
+
+	Convey("Escalations of recovered alert are cancelled", t, func() {
+		dataBase.EXPECT().GetTriggerEscalations(triggerID).Return(notifications[1:], nil)
+		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, database.ErrNil)
+		actual, err := GetTriggerEscalations(dataBase, triggerID)
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, &dto.TriggerEscalations{
+			List: []*dto.Escalation{
+				{SubscriptionID: subscriptionID, Step: 1, ContactID: "contact2", Metric: "metric", State: "ERROR", EventTimestamp: 100, Timestamp: 700, Cancelled: true},
+			},
+		})
+	})
+
+	Convey("Error get trigger escalations", t, func() {
+		expected := fmt.Errorf("Oooops! Error get")
+		dataBase.EXPECT().GetTriggerEscalations(triggerID).Return(nil, expected)
+		actual, err := GetTriggerEscalations(dataBase, triggerID)
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+		So(actual, ShouldBeNil)
+	})
+}
@@ -1,6 +1,8 @@
 package controller
 
 import (
+	"gopkg.in/tomb.v2"
+
 	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/api"
 	"github.com/moira-alert/moira/api/dto"
@@ -35,3 +37,41 @@ func DeleteAllEvents(database moira.Database) *api.ErrorResponse {
 	}
 	return nil
 }
+
+// EventsStreamFilter selects stream events of given triggers which have all given tags, empty filter selects all events
+type EventsStreamFilter struct {
+	TriggerIDs []string
+	Tags       []string
+}
+
+// Match checks that stream event satisfies filter
+func (filter *EventsStreamFilter) Match(event *moira.StreamEvent) bool {
+	if len(filter.TriggerIDs) > 0 && !subset([]string{event.TriggerID}, filter.TriggerIDs) {
+		return false
+	}
+	return subset(filter.Tags, event.Tags)
+}
+
+// SubscribeEventsStream subscribes to trigger state changes and notification events until tomb is killed
+func SubscribeEventsStream(database moira.Database, tomb *tomb.Tomb) (<-chan *moira.StreamEvent, *api.ErrorResponse) {
+	events, err := database.SubscribeStreamEvents(tomb)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	return events, nil
+}
+
+func subset(first, second []string) bool {
+	set := make(map[string]bool)
+	for _, value := range second {
+		set[value] = true
+	}
+
+	for _, value := range first {
+		if !set[value] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/moira-alert/moira/mock/moira-alert"
 	"github.com/satori/go.uuid"
 	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/tomb.v2"
 	"testing"
 )
 
@@ -75,3 +76,55 @@ func TestDeleteAllNotificationEvents(t *testing.T) {
 		So(err, ShouldResemble, api.ErrorInternalServer(expected))
 	})
 }
+
+func TestEventsStreamFilter(t *testing.T) {
+	event := &moira.StreamEvent{
+		Type:      moira.StateChangeStreamEvent,
+		TriggerID: "trigger1",
+		Tags:      []string{"tag1", "tag2"},
+	}
+
+	Convey("Empty filter should match all events", t, func() {
+		filter := &EventsStreamFilter{}
+		So(filter.Match(event), ShouldBeTrue)
+	})
+
+	Convey("Filter by trigger IDs", t, func() {
+		So((&EventsStreamFilter{TriggerIDs: []string{"trigger2", "trigger1"}}).Match(event), ShouldBeTrue)
+		So((&EventsStreamFilter{TriggerIDs: []string{"trigger2"}}).Match(event), ShouldBeFalse)
+	})
+
+	Convey("Filter by tags should match events having all tags", t, func() {
+		So((&EventsStreamFilter{Tags: []string{"tag2"}}).Match(event), ShouldBeTrue)
+		So((&EventsStreamFilter{Tags: []string{"tag1", "tag2"}}).Match(event), ShouldBeTrue)
+		So((&EventsStreamFilter{Tags: []string{"tag1", "tag3"}}).Match(event), ShouldBeFalse)
+	})
+
+	Convey("Filter by trigger IDs and tags", t, func() {
+		So((&EventsStreamFilter{TriggerIDs: []string{"trigger1"}, Tags: []string{"tag1"}}).Match(event), ShouldBeTrue)
+		So((&EventsStreamFilter{TriggerIDs: []string{"trigger1"}, Tags: []string{"tag3"}}).Match(event), ShouldBeFalse)
+	})
+}
+
+func TestSubscribeEventsStream(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	defer mockCtrl.Finish()
+	subscription := &tomb.Tomb{}
+
+	Convey("Success", t, func() {
+		events := make(chan *moira.StreamEvent)
+		dataBase.EXPECT().SubscribeStreamEvents(subscription).Return(events, nil)
+		actual, err := SubscribeEventsStream(dataBase, subscription)
+		So(err, ShouldBeNil)
+		So(actual, ShouldEqual, (<-chan *moira.StreamEvent)(events))
+	})
+
+	Convey("Error subscribe", t, func() {
+		expected := fmt.Errorf("oooops! Can not subscribe")
+		dataBase.EXPECT().SubscribeStreamEvents(subscription).Return(nil, expected)
+		actual, err := SubscribeEventsStream(dataBase, subscription)
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+		So(actual, ShouldBeNil)
+	})
+}
@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/satori/go.uuid"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+)
+
+// GetMaintenanceWindows gets maintenance windows which are active at given time or have upcoming occurrences, ordered by next occurrence start
+func GetMaintenanceWindows(dataBase moira.Database, timestamp int64) (*dto.MaintenanceWindowsList, *api.ErrorResponse) {
+	windows, err := dataBase.GetMaintenanceWindows(timestamp)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	windowsList := dto.MaintenanceWindowsList{
+		List: make([]*dto.MaintenanceWindow, 0, len(windows)),
+	}
+	for _, window := range windows {
+		windowDTO := dto.CreateMaintenanceWindow(*window, timestamp)
+		if windowDTO.NextStart == 0 {
+			continue
+		}
+		windowsList.List = append(windowsList.List, windowDTO)
+	}
+	sort.SliceStable(windowsList.List, func(i, j int) bool {
+		return windowsList.List[i].NextStart < windowsList.List[j].NextStart
+	})
+	return &windowsList, nil
+}
+
+// CreateMaintenanceWindow creates new maintenance window on behalf of current user
+func CreateMaintenanceWindow(dataBase moira.Database, window *dto.MaintenanceWindow, userLogin string, timestamp int64) *api.ErrorResponse {
+	window.ID = uuid.NewV4().String()
+	window.Author = userLogin
+	window.CreatedAt = timestamp
+	if err := dataBase.SaveMaintenanceWindow(&window.MaintenanceWindow); err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	*window = *dto.CreateMaintenanceWindow(window.MaintenanceWindow, timestamp)
+	return nil
+}
+
+// GetMaintenanceWindow gets maintenance window by its id
+func GetMaintenanceWindow(dataBase moira.Database, windowID string, timestamp int64) (*dto.MaintenanceWindow, *api.ErrorResponse) {
+	window, err := dataBase.GetMaintenanceWindow(windowID)
+	if err != nil {
+		if err == database.ErrNil {
+			return nil, api.ErrorNotFound(fmt.Sprintf("Maintenance window with ID '%s' does not exists", windowID))
+		}
+		return nil, api.ErrorInternalServer(err)
+	}
+	return dto.CreateMaintenanceWindow(window, timestamp), nil
+}
+
+// RemoveMaintenanceWindow deletes maintenance window
+func RemoveMaintenanceWindow(dataBase moira.Database, windowID string) *api.ErrorResponse {
+	if err := dataBase.RemoveMaintenanceWindow(windowID); err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	return nil
+}
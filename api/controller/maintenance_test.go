@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/mock/moira-alert"
+)
+
+func TestGetMaintenanceWindows(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	// 2019-01-02 00:00:00 UTC
+	var day int64 = 1546387200
+	activeWindow := &moira.MaintenanceWindow{ID: "active", Start: day - 3600, End: day + 3600, Scope: moira.MaintenanceScope{TriggerID: "trigger1"}}
+	upcomingWindow := &moira.MaintenanceWindow{ID: "upcoming", Start: day - 3600, End: day, Recurrence: "0 3 * * *", Scope: moira.MaintenanceScope{Tags: []string{"db"}}}
+	endedWindow := &moira.MaintenanceWindow{ID: "ended", Start: day - 3600, End: day, Recurrence: "0 3 * * *", RecurrenceEnd: day - 1800, Scope: moira.MaintenanceScope{Tags: []string{"db"}}}
+
+	Convey("Active and upcoming windows are listed", t, func() {
+		dataBase.EXPECT().GetMaintenanceWindows(day).Return([]*moira.MaintenanceWindow{activeWindow, upcomingWindow, endedWindow}, nil)
+		list, err := GetMaintenanceWindows(dataBase, day)
+		So(err, ShouldBeNil)
+		So(list, ShouldResemble, &dto.MaintenanceWindowsList{
+			List: []*dto.MaintenanceWindow{
+				{MaintenanceWindow: *activeWindow, Active: true, NextStart: day - 3600, NextEnd: day + 3600},
+				{MaintenanceWindow: *upcomingWindow, NextStart: day + 3*3600, NextEnd: day + 4*3600},
+			},
+		})
+	})
+
+	Convey("Error get windows", t, func() {
+		expected := fmt.Errorf("Oooops! Can not get maintenance windows")
+		dataBase.EXPECT().GetMaintenanceWindows(day).Return(nil, expected)
+		list, err := GetMaintenanceWindows(dataBase, day)
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+		So(list, ShouldBeNil)
+	})
+}
+
+func TestCreateMaintenanceWindow(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	userLogin := "user"
+
+	Convey("Success", t, func() {
+		window := &dto.MaintenanceWindow{
+			MaintenanceWindow: moira.MaintenanceWindow{Start: 100, End: 200, Reason: "deploy", Scope: moira.MaintenanceScope{TriggerID: "trigger1"}},
+		}
+		dataBase.EXPECT().SaveMaintenanceWindow(gomock.Any()).Return(nil)
+		err := CreateMaintenanceWindow(dataBase, window, userLogin, 150)
+		So(err, ShouldBeNil)
+		So(window.ID, ShouldNotBeEmpty)
+		So(window.Author, ShouldEqual, userLogin)
+		So(window.CreatedAt, ShouldEqual, 150)
+		So(window.Active, ShouldBeTrue)
+		So(window.NextStart, ShouldEqual, 100)
+		So(window.NextEnd, ShouldEqual, 200)
+	})
+
+	Convey("Error save window", t, func() {
+		window := &dto.MaintenanceWindow{}
+		expected := fmt.Errorf("Oooops! Can not save maintenance window")
+		dataBase.EXPECT().SaveMaintenanceWindow(gomock.Any()).Return(expected)
+		err := CreateMaintenanceWindow(dataBase, window, userLogin, 150)
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+	})
+}
+
+func TestGetMaintenanceWindow(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	window := moira.MaintenanceWindow{ID: "window1", Start: 100, End: 200}
+
+	Convey("Success", t, func() {
+		dataBase.EXPECT().GetMaintenanceWindow(window.ID).Return(window, nil)
+		actual, err := GetMaintenanceWindow(dataBase, window.ID, 50)
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, &dto.MaintenanceWindow{MaintenanceWindow: window, NextStart: 100, NextEnd: 200})
+	})
+
+	Convey("Window does not exist", t, func() {
+		dataBase.EXPECT().GetMaintenanceWindow(window.ID).Return(moira.MaintenanceWindow{}, database.ErrNil)
+		actual, err := GetMaintenanceWindow(dataBase, window.ID, 50)
+		So(err, ShouldResemble, api.ErrorNotFound(fmt.Sprintf("Maintenance window with ID '%s' does not exists", window.ID)))
+		So(actual, ShouldBeNil)
+	})
+}
+
+func TestRemoveMaintenanceWindow(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	Convey("Success", t, func() {
+		dataBase.EXPECT().RemoveMaintenanceWindow("window1").Return(nil)
+		So(RemoveMaintenanceWindow(dataBase, "window1"), ShouldBeNil)
+	})
+
+	Convey("Error", t, func() {
+		expected := fmt.Errorf("Oooops! Can not remove maintenance window")
+		dataBase.EXPECT().RemoveMaintenanceWindow("window1").Return(expected)
+		So(RemoveMaintenanceWindow(dataBase, "window1"), ShouldResemble, api.ErrorInternalServer(expected))
+	})
+}
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/satori/go.uuid"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+)
+
+// GetUserRotations gets all rotations of current user
+func GetUserRotations(dataBase moira.Database, userLogin string) (*dto.RotationsList, *api.ErrorResponse) {
+	rotations, err := dataBase.GetUserRotations(userLogin)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	return &dto.RotationsList{List: rotations}, nil
+}
+
+// CreateRotation creates new on-call rotation for current user
+func CreateRotation(dataBase moira.Database, rotation *dto.Rotation, userLogin string) *api.ErrorResponse {
+	rotation.ID = uuid.NewV4().String()
+	rotation.User = userLogin
+	if err := checkRotationContacts(dataBase, rotation); err != nil {
+		return err
+	}
+	if err := dataBase.SaveRotation((*moira.Rotation)(rotation)); err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	return nil
+}
+
+// UpdateRotation updates on-call rotation of current user
+func UpdateRotation(dataBase moira.Database, rotation *dto.Rotation, rotationData moira.Rotation) *api.ErrorResponse {
+	rotation.ID = rotationData.ID
+	rotation.User = rotationData.User
+	if err := checkRotationContacts(dataBase, rotation); err != nil {
+		return err
+	}
+	if err := dataBase.SaveRotation((*moira.Rotation)(rotation)); err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	return nil
+}
+
+// RemoveRotation deletes on-call rotation if there are no contacts which are resolved to it
+func RemoveRotation(dataBase moira.Database, rotationData moira.Rotation) *api.ErrorResponse {
+	contactIDs, err := dataBase.GetUserContactIDs(rotationData.User)
+	if err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	contacts, err := dataBase.GetContacts(contactIDs)
+	if err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	for _, contact := range contacts {
+		if contact != nil && contact.Type == moira.RotationContactType && contact.Value == rotationData.ID {
+			return api.ErrorInvalidRequest(fmt.Errorf("This rotation is being used in contact %s", contact.ID))
+		}
+	}
+	if err := dataBase.RemoveRotation(rotationData.ID); err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	return nil
+}
+
+// CheckUserPermissionsForRotation checks rotation for existence and permissions for given user
+func CheckUserPermissionsForRotation(dataBase moira.Database, rotationID string, userLogin string) (moira.Rotation, *api.ErrorResponse) {
+	rotation, err := dataBase.GetRotation(rotationID)
+	if err != nil {
+		if err == database.ErrNil {
+			return rotation, api.ErrorNotFound(fmt.Sprintf("Rotation with ID '%s' does not exists", rotationID))
+		}
+		return rotation, api.ErrorInternalServer(err)
+	}
+	if rotation.User != userLogin {
+		return rotation, api.ErrorForbidden("You have not permissions")
+	}
+	return rotation, nil
+}
+
+// checkRotationContacts checks that rotation participants and overrides are existing contacts of rotation user,
+// which are not rotations themselves
+func checkRotationContacts(dataBase moira.Database, rotation *dto.Rotation) *api.ErrorResponse {
+	contactIDs := (*moira.Rotation)(rotation).GetContactIDs()
+	contacts, err := dataBase.GetContacts(contactIDs)
+	if err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	for i, contact := range contacts {
+		if contact == nil || contact.User != rotation.User {
+			return api.ErrorInvalidRequest(fmt.Errorf("Contact with ID '%s' does not exists", contactIDs[i]))
+		}
+		if contact.Type == moira.RotationContactType {
+			return api.ErrorInvalidRequest(fmt.Errorf("Contact with ID '%s' is rotation and can not be rotation participant", contactIDs[i]))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/mock/moira-alert"
+)
+
+func TestGetUserRotations(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	login := "user"
+
+	Convey("Success", t, func() {
+		rotations := []*moira.Rotation{{ID: "rotation1", User: login}}
+		dataBase.EXPECT().GetUserRotations(login).Return(rotations, nil)
+		list, err := GetUserRotations(dataBase, login)
+		So(err, ShouldBeNil)
+		So(list, ShouldResemble, &dto.RotationsList{List: rotations})
+	})
+
+	Convey("Error", t, func() {
+		expected := fmt.Errorf("Oooops! Can not get rotations")
+		dataBase.EXPECT().GetUserRotations(login).Return(nil, expected)
+		list, err := GetUserRotations(dataBase, login)
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+		So(list, ShouldBeNil)
+	})
+}
+
+func TestCreateRotation(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	login := "user"
+	contact1 := &moira.ContactData{ID: "contact1", User: login, Type: "email"}
+	contact2 := &moira.ContactData{ID: "contact2", User: login, Type: "slack"}
+
+	Convey("Success", t, func() {
+		rotation := &dto.Rotation{Name: "On-call", Participants: []string{"contact1"}, Length: 604800, Overrides: []moira.RotationOverride{{Contact: "contact2", Start: 1, End: 2}}}
+		dataBase.EXPECT().GetContacts([]string{"contact1", "contact2"}).Return([]*moira.ContactData{contact1, contact2}, nil)
+		dataBase.EXPECT().SaveRotation(gomock.Any()).Return(nil)
+		err := CreateRotation(dataBase, rotation, login)
+		So(err, ShouldBeNil)
+		So(rotation.ID, ShouldNotBeEmpty)
+		So(rotation.User, ShouldEqual, login)
+	})
+
+	Convey("Contact of other user", t, func() {
+		rotation := &dto.Rotation{Name: "On-call", Participants: []string{"contact1"}, Length: 604800}
+		dataBase.EXPECT().GetContacts([]string{"contact1"}).Return([]*moira.ContactData{{ID: "contact1", User: "other"}}, nil)
+		err := CreateRotation(dataBase, rotation, login)
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("Contact with ID 'contact1' does not exists")))
+	})
+
+	Convey("Rotation contact", t, func() {
+		rotation := &dto.Rotation{Name: "On-call", Participants: []string{"contact1"}, Length: 604800}
+		dataBase.EXPECT().GetContacts([]string{"contact1"}).Return([]*moira.ContactData{{ID: "contact1", User: login, Type: moira.RotationContactType}}, nil)
+		err := CreateRotation(dataBase, rotation, login)
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("Contact with ID 'contact1' is rotation and can not be rotation participant")))
+	})
+
+	Convey("Error save", t, func() {
+		expected := fmt.Errorf("Oooops! Can not save rotation")
+		rotation := &dto.Rotation{Name: "On-call", Participants: []string{"contact1"}, Length: 604800}
+		dataBase.EXPECT().GetContacts([]string{"contact1"}).Return([]*moira.ContactData{contact1}, nil)
+		dataBase.EXPECT().SaveRotation(gomock.Any()).Return(expected)
+		err := CreateRotation(dataBase, rotation, login)
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+	})
+}
+
+func TestUpdateRotation(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	login := "user"
+	rotationData := moira.Rotation{ID: "rotation1", User: login}
+
+	Convey("Success", t, func() {
+		rotation := &dto.Rotation{Name: "On-call", Participants: []string{"contact1"}, Length: 86400}
+		dataBase.EXPECT().GetContacts([]string{"contact1"}).Return([]*moira.ContactData{{ID: "contact1", User: login}}, nil)
+		dataBase.EXPECT().SaveRotation(&moira.Rotation{ID: "rotation1", User: login, Name: "On-call", Participants: []string{"contact1"}, Length: 86400}).Return(nil)
+		err := UpdateRotation(dataBase, rotation, rotationData)
+		So(err, ShouldBeNil)
+	})
+}
+
+func TestRemoveRotation(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	login := "user"
+	rotationData := moira.Rotation{ID: "rotation1", User: login}
+
+	Convey("Success", t, func() {
+		dataBase.EXPECT().GetUserContactIDs(login).Return([]string{"contact1"}, nil)
+		dataBase.EXPECT().GetContacts([]string{"contact1"}).Return([]*moira.ContactData{{ID: "contact1", Type: "email", Value: "rotation1"}}, nil)
+		dataBase.EXPECT().RemoveRotation(rotationData.ID).Return(nil)
+		err := RemoveRotation(dataBase, rotationData)
+		So(err, ShouldBeNil)
+	})
+
+	Convey("Rotation is used by contact", t, func() {
+		dataBase.EXPECT().GetUserContactIDs(login).Return([]string{"contact1"}, nil)
+		dataBase.EXPECT().GetContacts([]string{"contact1"}).Return([]*moira.ContactData{{ID: "contact1", Type: moira.RotationContactType, Value: "rotation1"}}, nil)
+		err := RemoveRotation(dataBase, rotationData)
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("This rotation is being used in contact contact1")))
+	})
+}
+
+func TestCheckUserPermissionsForRotation(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	rotation := moira.Rotation{ID: "rotation1", User: "user"}
+
+	Convey("Owner", t, func() {
+		dataBase.EXPECT().GetRotation(rotation.ID).Return(rotation, nil)
+		actual, err := CheckUserPermissionsForRotation(dataBase, rotation.ID, "user")
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, rotation)
+	})
+
+	Convey("Other user", t, func() {
+		dataBase.EXPECT().GetRotation(rotation.ID).Return(rotation, nil)
+		_, err := CheckUserPermissionsForRotation(dataBase, rotation.ID, "other")
+		So(err, ShouldResemble, api.ErrorForbidden("You have not permissions"))
+	})
+
+	Convey("Not found", t, func() {
+		dataBase.EXPECT().GetRotation(rotation.ID).Return(moira.Rotation{}, database.ErrNil)
+		_, err := CheckUserPermissionsForRotation(dataBase, rotation.ID, "user")
+		So(err, ShouldResemble, api.ErrorNotFound("Rotation with ID 'rotation1' does not exists"))
+	})
+
+	Convey("Rotation contact of other user can not be created", t, func() {
+		dataBase.EXPECT().GetRotation(rotation.ID).Return(rotation, nil)
+		contact := &dto.Contact{Type: moira.RotationContactType, Value: rotation.ID}
+		err := CreateContact(dataBase, contact, "other")
+		So(err, ShouldResemble, api.ErrorForbidden("You have not permissions"))
+	})
+}
@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"sort"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+)
+
+// GetTriggerTimeline gets state intervals of trigger and its metrics within given time range
+func GetTriggerTimeline(database moira.Database, triggerID string, from, to int64) (*dto.TriggerTimeline, *api.ErrorResponse) {
+	intervals, err := getTriggerStateIntervals(database, triggerID, from, to)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	timeline := &dto.TriggerTimeline{
+		TriggerID: triggerID,
+		From:      from,
+		To:        to,
+		States:    make([]moira.StateInterval, 0),
+		Metrics:   make(map[string][]moira.StateInterval),
+	}
+	for _, interval := range intervals {
+		if interval.Metric == "" {
+			timeline.States = append(timeline.States, *interval)
+		} else {
+			timeline.Metrics[interval.Metric] = append(timeline.Metrics[interval.Metric], *interval)
+		}
+	}
+	return timeline, nil
+}
+
+// GetTriggerStateStats gets time spent by trigger in every state and mean time to recovery within given time range
+func GetTriggerStateStats(database moira.Database, triggerID string, from, to int64) (*dto.StateStats, *api.ErrorResponse) {
+	intervals, err := getTriggerStateIntervals(database, triggerID, from, to)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	return calculateStateStats(from, to, intervals), nil
+}
+
+// GetTagStateStats gets time spent by triggers with given tag in every state and their mean time to recovery within given time range
+func GetTagStateStats(database moira.Database, tag string, from, to int64) (*dto.StateStats, *api.ErrorResponse) {
+	triggerIDs, err := database.GetTagTriggerIDs(tag)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	triggersIntervals := make([][]*moira.StateInterval, 0, len(triggerIDs))
+	for _, triggerID := range triggerIDs {
+		intervals, err := getTriggerStateIntervals(database, triggerID, from, to)
+		if err != nil {
+			return nil, api.ErrorInternalServer(err)
+		}
+		triggersIntervals = append(triggersIntervals, intervals)
+	}
+	return calculateStateStats(from, to, triggersIntervals...), nil
+}
+
+// getTriggerStateIntervals returns saved state intervals and current states of trigger and its metrics, which are
+// considered lasting until the last check. Intervals are cut to given time range and sorted by start time
+func getTriggerStateIntervals(dataBase moira.Database, triggerID string, from, to int64) ([]*moira.StateInterval, error) {
+	intervals, err := dataBase.GetTriggerStateHistory(triggerID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	lastCheck, err := dataBase.GetTriggerLastCheck(triggerID)
+	if err != nil && err != database.ErrNil {
+		return nil, err
+	}
+	if err == nil {
+		intervals = append(intervals, &moira.StateInterval{
+			State: lastCheck.State,
+			Start: lastCheck.GetStateTimestamp(),
+			End:   lastCheck.Timestamp,
+		})
+		for metric, metricState := range lastCheck.Metrics {
+			intervals = append(intervals, &moira.StateInterval{
+				Metric: metric,
+				State:  metricState.State,
+				Start:  metricState.GetStateTimestamp(),
+				End:    lastCheck.Timestamp,
+			})
+		}
+	}
+
+	result := make([]*moira.StateInterval, 0, len(intervals))
+	for _, interval := range intervals {
+		if interval.Start < from {
+			interval.Start = from
+		}
+		if interval.End > to {
+			interval.End = to
+		}
+		if interval.Start >= interval.End {
+			continue
+		}
+		result = append(result, interval)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Start < result[j].Start })
+	return result, nil
+}
+
+// calculateStateStats sums trigger states durations and counts incidents: trigger state periods other than OK,
+// which have ended with OK state within time range. Incidents started before time range are measured from its beginning
+func calculateStateStats(from, to int64, triggersIntervals ...[]*moira.StateInterval) *dto.StateStats {
+	stats := &dto.StateStats{
+		From:        from,
+		To:          to,
+		TimeInState: make(map[string]int64),
+	}
+	var recoveryTime int64
+	for _, intervals := range triggersIntervals {
+		var incidentStart int64
+		isIncident := false
+		for _, interval := range intervals {
+			if interval.Metric != "" {
+				continue
+			}
+			stats.TimeInState[interval.State] += interval.End - interval.Start
+			switch {
+			case interval.State != "OK" && !isIncident:
+				isIncident = true
+				incidentStart = interval.Start
+			case interval.State == "OK" && isIncident:
+				isIncident = false
+				stats.Incidents++
+				recoveryTime += interval.Start - incidentStart
+			}
+		}
+	}
+	if stats.Incidents > 0 {
+		stats.MTTR = recoveryTime / stats.Incidents
+	}
+	return stats
+}
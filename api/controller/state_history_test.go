@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetTriggerTimeline(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	defer mockCtrl.Finish()
+	triggerID := "triggerID"
+
+	Convey("Has history and last check", t, func() {
+		dataBase.EXPECT().GetTriggerStateHistory(triggerID, int64(100), int64(1000)).Return([]*moira.StateInterval{
+			{State: "OK", Start: 50, End: 300},
+			{Metric: "metric", State: "OK", Start: 60, End: 250},
+			{State: "ERROR", Start: 300, End: 400},
+		}, nil)
+		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{
+			State:          "OK",
+			Timestamp:      900,
+			StateTimestamp: 400,
+			Metrics: map[string]moira.MetricState{
+				"metric": {State: "ERROR", StateTimestamp: 250},
+			},
+		}, nil)
+		timeline, err := GetTriggerTimeline(dataBase, triggerID, 100, 1000)
+		So(err, ShouldBeNil)
+		So(timeline, ShouldResemble, &dto.TriggerTimeline{
+			TriggerID: triggerID,
+			From:      100,
+			To:        1000,
+			States: []moira.StateInterval{
+				{State: "OK", Start: 100, End: 300},
+				{State: "ERROR", Start: 300, End: 400},
+				{State: "OK", Start: 400, End: 900},
+			},
+			Metrics: map[string][]moira.StateInterval{
+				"metric": {
+					{Metric: "metric", State: "OK", Start: 100, End: 250},
+					{Metric: "metric", State: "ERROR", Start: 250, End: 900},
+				},
+			},
+		})
+	})
+
+	Convey("Trigger was not checked", t, func() {
+		dataBase.EXPECT().GetTriggerStateHistory(triggerID, int64(100), int64(1000)).Return([]*moira.StateInterval{}, nil)
+		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, database.ErrNil)
+		timeline, err := GetTriggerTimeline(dataBase, triggerID, 100, 1000)
+		So(err, ShouldBeNil)
+		So(timeline, ShouldResemble, &dto.TriggerTimeline{
+			TriggerID: triggerID,
+			From:      100,
+			To:        1000,
+			States:    []moira.StateInterval{},
+			Metrics:   map[string][]moira.StateInterval{},
+		})
+	})
+
+	Convey("Get history error", t, func() {
+		expected := fmt.Errorf("oooops! Can not get history")
+		dataBase.EXPECT().GetTriggerStateHistory(triggerID, int64(100), int64(1000)).Return(nil, expected)
+		timeline, err := GetTriggerTimeline(dataBase, triggerID, 100, 1000)
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+		So(timeline, ShouldBeNil)
+	})
+
+	Convey("Get last check error", t, func() {
+		expected := fmt.Errorf("oooops! Can not get last check")
+		dataBase.EXPECT().GetTriggerStateHistory(triggerID, int64(100), int64(1000)).Return([]*moira.StateInterval{}, nil)
+		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, expected)
+		timeline, err := GetTriggerTimeline(dataBase, triggerID, 100, 1000)
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+		So(timeline, ShouldBeNil)
+	})
+}
+
+func TestGetTriggerStateStats(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	defer mockCtrl.Finish()
+	triggerID := "triggerID"
+
+	Convey("Success", t, func() {
+		dataBase.EXPECT().GetTriggerStateHistory(triggerID, int64(0), int64(1000)).Return([]*moira.StateInterval{
+			{State: "OK", Start: 0, End: 100},
+			{State: "WARN", Start: 100, End: 200},
+			{State: "ERROR", Start: 200, End: 300},
+			{State: "OK", Start: 300, End: 500},
+			{Metric: "metric", State: "ERROR", Start: 0, End: 500},
+			{State: "NODATA", Start: 500, End: 600},
+		}, nil)
+		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{State: "OK", Timestamp: 700, StateTimestamp: 600}, nil)
+		stats, err := GetTriggerStateStats(dataBase, triggerID, 0, 1000)
+		So(err, ShouldBeNil)
+		So(stats, ShouldResemble, &dto.StateStats{
+			From:        0,
+			To:          1000,
+			TimeInState: map[string]int64{"OK": 400, "WARN": 100, "ERROR": 100, "NODATA": 100},
+			Incidents:   2,
+			MTTR:        150,
+		})
+	})
+
+	Convey("Error", t, func() {
+		expected := fmt.Errorf("oooops! Can not get history")
+		dataBase.EXPECT().GetTriggerStateHistory(triggerID, int64(0), int64(1000)).Return(nil, expected)
+		stats, err := GetTriggerStateStats(dataBase, triggerID, 0, 1000)
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+		So(stats, ShouldBeNil)
+	})
+}
+
+func TestGetTagStateStats(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	defer mockCtrl.Finish()
+	tag := "tag"
+
+	Convey("Success", t, func() {
+		dataBase.EXPECT().GetTagTriggerIDs(tag).Return([]string{"trigger1", "trigger2"}, nil)
+		dataBase.EXPECT().GetTriggerStateHistory("trigger1", int64(0), int64(1000)).Return([]*moira.StateInterval{
+			{State: "ERROR", Start: 100, End: 200},
+		}, nil)
+		dataBase.EXPECT().GetTriggerLastCheck("trigger1").Return(moira.CheckData{State: "OK", Timestamp: 300, StateTimestamp: 200}, nil)
+		dataBase.EXPECT().GetTriggerStateHistory("trigger2", int64(0), int64(1000)).Return([]*moira.StateInterval{
+			{State: "NODATA", Start: 0, End: 300},
+		}, nil)
+		dataBase.EXPECT().GetTriggerLastCheck("trigger2").Return(moira.CheckData{State: "OK", Timestamp: 400, StateTimestamp: 300}, nil)
+		stats, err := GetTagStateStats(dataBase, tag, 0, 1000)
+		So(err, ShouldBeNil)
+		So(stats, ShouldResemble, &dto.StateStats{
+			From:        0,
+			To:          1000,
+			TimeInState: map[string]int64{"OK": 200, "ERROR": 100, "NODATA": 300},
+			Incidents:   2,
+			MTTR:        200,
+		})
+	})
+
+	Convey("Get tag triggers error", t, func() {
+		expected := fmt.Errorf("oooops! Can not get tag triggers")
+		dataBase.EXPECT().GetTagTriggerIDs(tag).Return(nil, expected)
+		stats, err := GetTagStateStats(dataBase, tag, 0, 1000)
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+		So(stats, ShouldBeNil)
+	})
+}
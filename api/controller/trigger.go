@@ -26,6 +26,11 @@ func UpdateTrigger(dataBase moira.Database, trigger *dto.TriggerModel, triggerID
 
 // saveTrigger create or update trigger data and update trigger metrics in last state
 func saveTrigger(dataBase moira.Database, trigger *moira.Trigger, triggerID string, timeSeriesNames map[string]bool) (*dto.SaveTriggerResponse, *api.ErrorResponse) {
+	if trigger.IsComposite() {
+		if errorResponse := checkCompositeTriggerInputs(dataBase, triggerID, trigger.Inputs); errorResponse != nil {
+			return nil, errorResponse
+		}
+	}
 	if err := dataBase.AcquireTriggerCheckLock(triggerID, 10); err != nil {
 		return nil, api.ErrorInternalServer(err)
 	}
@@ -53,7 +58,7 @@ func saveTrigger(dataBase moira.Database, trigger *moira.Trigger, triggerID stri
 		lastCheck.UpdateScore()
 	}
 
-	if err = dataBase.SetTriggerLastCheck(triggerID, &lastCheck, trigger.IsRemote); err != nil {
+	if err = dataBase.SetTriggerLastCheck(triggerID, &lastCheck, trigger.GetRemoteSource()); err != nil {
 		return nil, api.ErrorInternalServer(err)
 	}
 
@@ -68,6 +73,44 @@ func saveTrigger(dataBase moira.Database, trigger *moira.Trigger, triggerID stri
 	return &resp, nil
 }
 
+// checkCompositeTriggerInputs checks that all composite trigger inputs exist
+// and that trigger is not reachable from its inputs, so that composite triggers do not form a cycle
+func checkCompositeTriggerInputs(dataBase moira.Database, triggerID string, inputs []string) *api.ErrorResponse {
+	inputTriggers, err := dataBase.GetTriggers(inputs)
+	if err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	for i, inputTrigger := range inputTriggers {
+		if inputTrigger == nil {
+			return api.ErrorInvalidRequest(fmt.Errorf("Input trigger with ID = '%s' does not exists", inputs[i]))
+		}
+	}
+	visited := make(map[string]bool)
+	for len(inputTriggers) > 0 {
+		nextInputs := make([]string, 0)
+		for _, inputTrigger := range inputTriggers {
+			if inputTrigger == nil {
+				continue
+			}
+			if inputTrigger.ID == triggerID {
+				return api.ErrorInvalidRequest(fmt.Errorf("Composite trigger inputs form a cycle through trigger with ID = '%s'", triggerID))
+			}
+			if visited[inputTrigger.ID] {
+				continue
+			}
+			visited[inputTrigger.ID] = true
+			nextInputs = append(nextInputs, inputTrigger.Inputs...)
+		}
+		if len(nextInputs) == 0 {
+			break
+		}
+		if inputTriggers, err = dataBase.GetTriggers(nextInputs); err != nil {
+			return api.ErrorInternalServer(err)
+		}
+	}
+	return nil
+}
+
 // GetTrigger gets trigger with his throttling - next allowed message time
 func GetTrigger(dataBase moira.Database, triggerID string) (*dto.Trigger, *api.ErrorResponse) {
 	trigger, err := dataBase.GetTrigger(triggerID)
@@ -131,6 +174,20 @@ func GetTriggerLastCheck(dataBase moira.Database, triggerID string) (*dto.Trigge
 		TriggerID: triggerID,
 	}
 
+	if lastCheck != nil && len(lastCheck.Metrics) > 0 {
+		trigger, err := dataBase.GetTrigger(triggerID)
+		if err != nil && err != database.ErrNil {
+			return nil, api.ErrorInternalServer(err)
+		}
+		if err == nil {
+			triggerCheck.MetricsTTL = make(map[string]dto.MetricTTL, len(lastCheck.Metrics))
+			for metric := range lastCheck.Metrics {
+				ttl, ttlState := trigger.GetMetricTTL(metric)
+				triggerCheck.MetricsTTL[metric] = dto.MetricTTL{TTL: ttl, TTLState: ttlState}
+			}
+		}
+	}
+
 	return &triggerCheck, nil
 }
 
@@ -187,7 +244,7 @@ func DeleteTriggerMetric(dataBase moira.Database, metricName string, triggerID s
 	if err = dataBase.RemovePatternsMetrics(trigger.Patterns); err != nil {
 		return api.ErrorInternalServer(err)
 	}
-	if err = dataBase.SetTriggerLastCheck(triggerID, &lastCheck, trigger.IsRemote); err != nil {
+	if err = dataBase.SetTriggerLastCheck(triggerID, &lastCheck, trigger.GetRemoteSource()); err != nil {
 		return api.ErrorInternalServer(err)
 	}
 	return nil
@@ -201,6 +258,14 @@ func SetMetricsMaintenance(database moira.Database, triggerID string, metricsMai
 	return nil
 }
 
+// SetTriggerMaintenance sets maintenance of the whole trigger, events of the trigger and all its metrics are suppressed until given timestamp
+func SetTriggerMaintenance(database moira.Database, triggerID string, triggerMaintenance dto.TriggerMaintenance) *api.ErrorResponse {
+	if err := database.SetTriggerMaintenance(triggerID, triggerMaintenance.Until); err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	return nil
+}
+
 // GetTriggerMetrics gets all trigger metrics values, default values from: now - 10min, to: now
 func GetTriggerMetrics(dataBase moira.Database, from, to int64, triggerID string) (dto.TriggerMetrics, *api.ErrorResponse) {
 	trigger, err := dataBase.GetTrigger(triggerID)
@@ -28,7 +28,7 @@ func TestUpdateTrigger(t *testing.T) {
 		dataBase.EXPECT().AcquireTriggerCheckLock(gomock.Any(), 10)
 		dataBase.EXPECT().DeleteTriggerCheckLock(gomock.Any())
 		dataBase.EXPECT().GetTriggerLastCheck(gomock.Any()).Return(moira.CheckData{}, database.ErrNil)
-		dataBase.EXPECT().SetTriggerLastCheck(gomock.Any(), gomock.Any(), trigger.IsRemote).Return(nil)
+		dataBase.EXPECT().SetTriggerLastCheck(gomock.Any(), gomock.Any(), trigger.GetRemoteSource()).Return(nil)
 		dataBase.EXPECT().SaveTrigger(gomock.Any(), trigger).Return(nil)
 		resp, err := UpdateTrigger(dataBase, &triggerModel, triggerModel.ID, make(map[string]bool))
 		So(err, ShouldBeNil)
@@ -74,7 +74,7 @@ func TestSaveTrigger(t *testing.T) {
 			dataBase.EXPECT().AcquireTriggerCheckLock(triggerID, 10)
 			dataBase.EXPECT().DeleteTriggerCheckLock(triggerID)
 			dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, database.ErrNil)
-			dataBase.EXPECT().SetTriggerLastCheck(triggerID, gomock.Any(), trigger.IsRemote).Return(nil)
+			dataBase.EXPECT().SetTriggerLastCheck(triggerID, gomock.Any(), trigger.GetRemoteSource()).Return(nil)
 			dataBase.EXPECT().SaveTrigger(triggerID, &trigger).Return(nil)
 			resp, err := saveTrigger(dataBase, &trigger, triggerID, make(map[string]bool))
 			So(err, ShouldBeNil)
@@ -85,7 +85,7 @@ func TestSaveTrigger(t *testing.T) {
 			dataBase.EXPECT().AcquireTriggerCheckLock(triggerID, 10)
 			dataBase.EXPECT().DeleteTriggerCheckLock(triggerID)
 			dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(actualLastCheck, nil)
-			dataBase.EXPECT().SetTriggerLastCheck(triggerID, &actualLastCheck, trigger.IsRemote).Return(nil)
+			dataBase.EXPECT().SetTriggerLastCheck(triggerID, &actualLastCheck, trigger.GetRemoteSource()).Return(nil)
 			dataBase.EXPECT().SaveTrigger(triggerID, &trigger).Return(nil)
 			resp, err := saveTrigger(dataBase, &trigger, triggerID, make(map[string]bool))
 			So(err, ShouldBeNil)
@@ -99,7 +99,7 @@ func TestSaveTrigger(t *testing.T) {
 		dataBase.EXPECT().AcquireTriggerCheckLock(triggerID, 10)
 		dataBase.EXPECT().DeleteTriggerCheckLock(triggerID)
 		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, database.ErrNil)
-		dataBase.EXPECT().SetTriggerLastCheck(triggerID, gomock.Any(), trigger.IsRemote).Return(nil)
+		dataBase.EXPECT().SetTriggerLastCheck(triggerID, gomock.Any(), trigger.GetRemoteSource()).Return(nil)
 		dataBase.EXPECT().SaveTrigger(triggerID, &trigger).Return(nil)
 		resp, err := saveTrigger(dataBase, &trigger, triggerID, map[string]bool{"super.metric1": true, "super.metric2": true})
 		So(err, ShouldBeNil)
@@ -131,7 +131,7 @@ func TestSaveTrigger(t *testing.T) {
 			dataBase.EXPECT().AcquireTriggerCheckLock(triggerID, 10)
 			dataBase.EXPECT().DeleteTriggerCheckLock(triggerID)
 			dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, database.ErrNil)
-			dataBase.EXPECT().SetTriggerLastCheck(triggerID, gomock.Any(), trigger.IsRemote).Return(expected)
+			dataBase.EXPECT().SetTriggerLastCheck(triggerID, gomock.Any(), trigger.GetRemoteSource()).Return(expected)
 			resp, err := saveTrigger(dataBase, &trigger, triggerID, make(map[string]bool))
 			So(err, ShouldResemble, api.ErrorInternalServer(expected))
 			So(resp, ShouldBeNil)
@@ -142,7 +142,7 @@ func TestSaveTrigger(t *testing.T) {
 			dataBase.EXPECT().AcquireTriggerCheckLock(triggerID, 10)
 			dataBase.EXPECT().DeleteTriggerCheckLock(triggerID)
 			dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, database.ErrNil)
-			dataBase.EXPECT().SetTriggerLastCheck(triggerID, gomock.Any(), trigger.IsRemote).Return(nil)
+			dataBase.EXPECT().SetTriggerLastCheck(triggerID, gomock.Any(), trigger.GetRemoteSource()).Return(nil)
 			dataBase.EXPECT().SaveTrigger(triggerID, &trigger).Return(expected)
 			resp, err := saveTrigger(dataBase, &trigger, triggerID, make(map[string]bool))
 			So(err, ShouldResemble, api.ErrorInternalServer(expected))
@@ -151,6 +151,57 @@ func TestSaveTrigger(t *testing.T) {
 	})
 }
 
+func TestCheckCompositeTriggerInputs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	triggerID := "composite"
+
+	Convey("Inputs without cycle", t, func() {
+		dataBase.EXPECT().GetTriggers([]string{"latency", "errors-composite"}).Return([]*moira.Trigger{
+			{ID: "latency"},
+			{ID: "errors-composite", Inputs: []string{"errors", "latency"}},
+		}, nil)
+		dataBase.EXPECT().GetTriggers([]string{"errors", "latency"}).Return([]*moira.Trigger{
+			{ID: "errors"},
+			{ID: "latency"},
+		}, nil)
+		err := checkCompositeTriggerInputs(dataBase, triggerID, []string{"latency", "errors-composite"})
+		So(err, ShouldBeNil)
+	})
+
+	Convey("Input trigger does not exists", t, func() {
+		dataBase.EXPECT().GetTriggers([]string{"latency", "errors"}).Return([]*moira.Trigger{{ID: "latency"}, nil}, nil)
+		err := checkCompositeTriggerInputs(dataBase, triggerID, []string{"latency", "errors"})
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("Input trigger with ID = 'errors' does not exists")))
+	})
+
+	Convey("Inputs form a cycle", t, func() {
+		dataBase.EXPECT().GetTriggers([]string{"errors-composite"}).Return([]*moira.Trigger{
+			{ID: "errors-composite", Inputs: []string{"errors", triggerID}},
+		}, nil)
+		dataBase.EXPECT().GetTriggers([]string{"errors", triggerID}).Return([]*moira.Trigger{
+			{ID: "errors"},
+			{ID: triggerID, Inputs: []string{"errors-composite"}},
+		}, nil)
+		err := checkCompositeTriggerInputs(dataBase, triggerID, []string{"errors-composite"})
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("Composite trigger inputs form a cycle through trigger with ID = '%s'", triggerID)))
+	})
+
+	Convey("Trigger is input of itself", t, func() {
+		dataBase.EXPECT().GetTriggers([]string{triggerID}).Return([]*moira.Trigger{{ID: triggerID}}, nil)
+		err := checkCompositeTriggerInputs(dataBase, triggerID, []string{triggerID})
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("Composite trigger inputs form a cycle through trigger with ID = '%s'", triggerID)))
+	})
+
+	Convey("Database error", t, func() {
+		expected := fmt.Errorf("GetTriggers error")
+		dataBase.EXPECT().GetTriggers([]string{"latency"}).Return(nil, expected)
+		err := checkCompositeTriggerInputs(dataBase, triggerID, []string{"latency"})
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+	})
+}
+
 func TestVariousTtlState(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -175,7 +226,7 @@ func TestVariousTtlState(t *testing.T) {
 			dataBase.EXPECT().AcquireTriggerCheckLock(triggerID, 10)
 			dataBase.EXPECT().DeleteTriggerCheckLock(triggerID)
 			dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, database.ErrNil)
-			dataBase.EXPECT().SetTriggerLastCheck(triggerID, &lastCheck, trigger.IsRemote).Return(nil)
+			dataBase.EXPECT().SetTriggerLastCheck(triggerID, &lastCheck, trigger.GetRemoteSource()).Return(nil)
 			dataBase.EXPECT().SaveTrigger(triggerID, &trigger).Return(nil)
 			resp, err := saveTrigger(dataBase, &trigger, triggerID, make(map[string]bool))
 			So(err, ShouldBeNil)
@@ -190,7 +241,7 @@ func TestVariousTtlState(t *testing.T) {
 			dataBase.EXPECT().AcquireTriggerCheckLock(triggerID, 10)
 			dataBase.EXPECT().DeleteTriggerCheckLock(triggerID)
 			dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, database.ErrNil)
-			dataBase.EXPECT().SetTriggerLastCheck(triggerID, &lastCheck, trigger.IsRemote).Return(nil)
+			dataBase.EXPECT().SetTriggerLastCheck(triggerID, &lastCheck, trigger.GetRemoteSource()).Return(nil)
 			dataBase.EXPECT().SaveTrigger(triggerID, &trigger).Return(nil)
 			resp, err := saveTrigger(dataBase, &trigger, triggerID, make(map[string]bool))
 			So(err, ShouldBeNil)
@@ -205,7 +256,7 @@ func TestVariousTtlState(t *testing.T) {
 			dataBase.EXPECT().AcquireTriggerCheckLock(triggerID, 10)
 			dataBase.EXPECT().DeleteTriggerCheckLock(triggerID)
 			dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, database.ErrNil)
-			dataBase.EXPECT().SetTriggerLastCheck(triggerID, &lastCheck, trigger.IsRemote).Return(nil)
+			dataBase.EXPECT().SetTriggerLastCheck(triggerID, &lastCheck, trigger.GetRemoteSource()).Return(nil)
 			dataBase.EXPECT().SaveTrigger(triggerID, &trigger).Return(nil)
 			resp, err := saveTrigger(dataBase, &trigger, triggerID, make(map[string]bool))
 			So(err, ShouldBeNil)
@@ -220,7 +271,7 @@ func TestVariousTtlState(t *testing.T) {
 			dataBase.EXPECT().AcquireTriggerCheckLock(triggerID, 10)
 			dataBase.EXPECT().DeleteTriggerCheckLock(triggerID)
 			dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, database.ErrNil)
-			dataBase.EXPECT().SetTriggerLastCheck(triggerID, &lastCheck, trigger.IsRemote).Return(nil)
+			dataBase.EXPECT().SetTriggerLastCheck(triggerID, &lastCheck, trigger.GetRemoteSource()).Return(nil)
 			dataBase.EXPECT().SaveTrigger(triggerID, &trigger).Return(nil)
 			resp, err := saveTrigger(dataBase, &trigger, triggerID, make(map[string]bool))
 			So(err, ShouldBeNil)
@@ -235,7 +286,7 @@ func TestVariousTtlState(t *testing.T) {
 			dataBase.EXPECT().AcquireTriggerCheckLock(triggerID, 10)
 			dataBase.EXPECT().DeleteTriggerCheckLock(triggerID)
 			dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, database.ErrNil)
-			dataBase.EXPECT().SetTriggerLastCheck(triggerID, &lastCheck, trigger.IsRemote).Return(nil)
+			dataBase.EXPECT().SetTriggerLastCheck(triggerID, &lastCheck, trigger.GetRemoteSource()).Return(nil)
 			dataBase.EXPECT().SaveTrigger(triggerID, &trigger).Return(nil)
 			resp, err := saveTrigger(dataBase, &trigger, triggerID, make(map[string]bool))
 			So(err, ShouldBeNil)
@@ -374,6 +425,33 @@ func TestGetTriggerLastCheck(t *testing.T) {
 		})
 	})
 
+	Convey("Success with metrics TTL", t, func() {
+		ttlState := "ERROR"
+		trigger := moira.Trigger{
+			ID:           triggerID,
+			TTL:          600,
+			TTLOverrides: []moira.TTLOverride{{Pattern: "batch.*.run", TTL: 7200, TTLState: &ttlState}},
+		}
+		metricsLastCheck := moira.CheckData{
+			Metrics: map[string]moira.MetricState{
+				"host.cpu":      {},
+				"batch.job.run": {},
+			},
+		}
+		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(metricsLastCheck, nil)
+		dataBase.EXPECT().GetTrigger(triggerID).Return(trigger, nil)
+		check, err := GetTriggerLastCheck(dataBase, triggerID)
+		So(err, ShouldBeNil)
+		So(check, ShouldResemble, &dto.TriggerCheck{
+			TriggerID: triggerID,
+			CheckData: &metricsLastCheck,
+			MetricsTTL: map[string]dto.MetricTTL{
+				"host.cpu":      {TTL: 600, TTLState: "NODATA"},
+				"batch.job.run": {TTL: 7200, TTLState: "ERROR"},
+			},
+		})
+	})
+
 	Convey("Error", t, func() {
 		expected := fmt.Errorf("Oooops! Error get")
 		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, expected)
@@ -429,7 +507,7 @@ func TestDeleteTriggerMetric(t *testing.T) {
 		dataBase.EXPECT().DeleteTriggerCheckLock(triggerID)
 		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(expectedLastCheck, nil)
 		dataBase.EXPECT().RemovePatternsMetrics(trigger.Patterns).Return(nil)
-		dataBase.EXPECT().SetTriggerLastCheck(triggerID, &expectedLastCheck, trigger.IsRemote)
+		dataBase.EXPECT().SetTriggerLastCheck(triggerID, &expectedLastCheck, trigger.GetRemoteSource())
 		err := DeleteTriggerMetric(dataBase, "super.metric1", triggerID)
 		So(err, ShouldBeNil)
 		So(expectedLastCheck, ShouldResemble, emptyLastCheck)
@@ -442,7 +520,7 @@ func TestDeleteTriggerMetric(t *testing.T) {
 		dataBase.EXPECT().DeleteTriggerCheckLock(triggerID)
 		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(expectedLastCheck, nil)
 		dataBase.EXPECT().RemovePatternsMetrics(trigger.Patterns).Return(nil)
-		dataBase.EXPECT().SetTriggerLastCheck(triggerID, &expectedLastCheck, trigger.IsRemote)
+		dataBase.EXPECT().SetTriggerLastCheck(triggerID, &expectedLastCheck, trigger.GetRemoteSource())
 		err := DeleteTriggerMetric(dataBase, "super.metric1", triggerID)
 		So(err, ShouldBeNil)
 		So(expectedLastCheck, ShouldResemble, emptyLastCheck)
@@ -506,7 +584,7 @@ func TestDeleteTriggerMetric(t *testing.T) {
 		dataBase.EXPECT().DeleteTriggerCheckLock(triggerID)
 		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(lastCheck, nil)
 		dataBase.EXPECT().RemovePatternsMetrics(trigger.Patterns).Return(nil)
-		dataBase.EXPECT().SetTriggerLastCheck(triggerID, &lastCheck, trigger.IsRemote).Return(expected)
+		dataBase.EXPECT().SetTriggerLastCheck(triggerID, &lastCheck, trigger.GetRemoteSource()).Return(expected)
 		err := DeleteTriggerMetric(dataBase, "super.metric1", triggerID)
 		So(err, ShouldResemble, api.ErrorInternalServer(expected))
 	})
@@ -533,6 +611,27 @@ func TestSetMetricsMaintenance(t *testing.T) {
 	})
 }
 
+func TestSetTriggerMaintenance(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	triggerID := uuid.NewV4().String()
+	maintenance := dto.TriggerMaintenance{Until: 1000}
+
+	Convey("Success", t, func() {
+		dataBase.EXPECT().SetTriggerMaintenance(triggerID, maintenance.Until).Return(nil)
+		err := SetTriggerMaintenance(dataBase, triggerID, maintenance)
+		So(err, ShouldBeNil)
+	})
+
+	Convey("Error", t, func() {
+		expected := fmt.Errorf("Oooops! Error set")
+		dataBase.EXPECT().SetTriggerMaintenance(triggerID, maintenance.Until).Return(expected)
+		err := SetTriggerMaintenance(dataBase, triggerID, maintenance)
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+	})
+}
+
 func TestGetTriggerMetrics(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
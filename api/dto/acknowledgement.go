@@ -0,0 +1,27 @@
+// nolint
+package dto
+
+import (
+	"net/http"
+
+	"github.com/moira-alert/moira"
+)
+
+// AcknowledgementRequest acknowledges current state of trigger metric or of the whole trigger if Metric is empty
+type AcknowledgementRequest struct {
+	Metric string `json:"metric,omitempty"`
+}
+
+func (*AcknowledgementRequest) Bind(r *http.Request) error {
+	return nil
+}
+
+type Acknowledgement struct {
+	moira.Acknowledgement
+	TriggerID string `json:"trigger_id"`
+	Metric    string `json:"metric,omitempty"`
+}
+
+func (*Acknowledgement) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
@@ -0,0 +1,26 @@
+// nolint
+package dto
+
+import (
+	"net/http"
+)
+
+type TriggerEscalations struct {
+	List []*Escalation `json:"list"`
+}
+
+func (*TriggerEscalations) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// Escalation is scheduled notification of subscription escalation step, it is cancelled if alert is acknowledged or recovered
+type Escalation struct {
+	SubscriptionID string `json:"subscription_id"`
+	Step           int    `json:"step"`
+	ContactID      string `json:"contact_id"`
+	Metric         string `json:"metric"`
+	State          string `json:"state"`
+	EventTimestamp int64  `json:"event_timestamp"`
+	Timestamp      int64  `json:"timestamp"`
+	Cancelled      bool   `json:"cancelled"`
+}
@@ -0,0 +1,74 @@
+// nolint
+package dto
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/moira-alert/moira"
+)
+
+type MaintenanceWindowsList struct {
+	List []*MaintenanceWindow `json:"list"`
+}
+
+func (*MaintenanceWindowsList) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// MaintenanceWindow is moira.MaintenanceWindow api representation, id, author and created_at are set by api
+type MaintenanceWindow struct {
+	moira.MaintenanceWindow
+	// Window is active now
+	Active bool `json:"active"`
+	// Start and end of the current or the nearest upcoming window occurrence
+	NextStart int64 `json:"next_start,omitempty"`
+	NextEnd   int64 `json:"next_end,omitempty"`
+}
+
+func (*MaintenanceWindow) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+func (window *MaintenanceWindow) Bind(r *http.Request) error {
+	if window.Start <= 0 || window.End <= window.Start {
+		return fmt.Errorf("maintenance window end must be greater than start")
+	}
+	if window.Reason == "" {
+		return fmt.Errorf("maintenance window reason is required")
+	}
+	if window.IsRecurring() {
+		if _, err := moira.ParseCronSchedule(window.Recurrence); err != nil {
+			return err
+		}
+		if window.RecurrenceEnd != 0 && window.RecurrenceEnd < window.Start {
+			return fmt.Errorf("maintenance window recurrence_end can not be less than start")
+		}
+	} else if window.RecurrenceEnd != 0 {
+		return fmt.Errorf("maintenance window recurrence_end can be set only with recurrence")
+	}
+	if expiration := window.GetExpiration(); expiration != 0 && expiration <= time.Now().Unix() {
+		return fmt.Errorf("maintenance window has already ended")
+	}
+	if window.Scope.TriggerID == "" && len(window.Scope.Tags) == 0 {
+		return fmt.Errorf("maintenance window scope must have trigger_id or tags")
+	}
+	if window.Scope.Metric != "" {
+		if err := moira.ValidateGraphitePattern(window.Scope.Metric); err != nil {
+			return fmt.Errorf("maintenance window scope metric '%s' is invalid: %s", window.Scope.Metric, err.Error())
+		}
+	}
+	return nil
+}
+
+// CreateMaintenanceWindow transforms moira.MaintenanceWindow to MaintenanceWindow with its occurrence at given time
+func CreateMaintenanceWindow(window moira.MaintenanceWindow, timestamp int64) *MaintenanceWindow {
+	nextStart, nextEnd := window.GetOccurrence(timestamp)
+	return &MaintenanceWindow{
+		MaintenanceWindow: window,
+		Active:            nextStart <= timestamp && timestamp < nextEnd,
+		NextStart:         nextStart,
+		NextEnd:           nextEnd,
+	}
+}
@@ -0,0 +1,44 @@
+// nolint
+package dto
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/moira-alert/moira"
+)
+
+type RotationsList struct {
+	List []*moira.Rotation `json:"list"`
+}
+
+func (*RotationsList) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+type Rotation moira.Rotation
+
+func (*Rotation) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+func (rotation *Rotation) Bind(r *http.Request) error {
+	if rotation.Name == "" {
+		return fmt.Errorf("Rotation name can not be empty")
+	}
+	if len(rotation.Participants) == 0 {
+		return fmt.Errorf("Rotation must have participants")
+	}
+	if rotation.Length <= 0 {
+		return fmt.Errorf("Rotation length must be positive")
+	}
+	for _, override := range rotation.Overrides {
+		if override.Contact == "" {
+			return fmt.Errorf("Rotation override contact can not be empty")
+		}
+		if override.Start >= override.End {
+			return fmt.Errorf("Rotation override start must be before its end")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+// nolint
+package dto
+
+import (
+	"net/http"
+
+	"github.com/moira-alert/moira"
+)
+
+type TriggerTimeline struct {
+	TriggerID string                           `json:"trigger_id"`
+	From      int64                            `json:"from"`
+	To        int64                            `json:"to"`
+	States    []moira.StateInterval            `json:"states"`
+	Metrics   map[string][]moira.StateInterval `json:"metrics"`
+}
+
+func (*TriggerTimeline) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+type StateStats struct {
+	From        int64            `json:"from"`
+	To          int64            `json:"to"`
+	TimeInState map[string]int64 `json:"time_in_state"`
+	Incidents   int64            `json:"incidents"`
+	MTTR        int64            `json:"mttr"`
+}
+
+func (*StateStats) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
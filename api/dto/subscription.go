@@ -28,5 +28,15 @@ func (subscription *Subscription) Bind(r *http.Request) error {
 	if len(subscription.Contacts) == 0 {
 		return fmt.Errorf("Subscription must have contacts")
 	}
+	var previousDelay int64
+	for i, step := range subscription.Escalations {
+		if len(step.Contacts) == 0 {
+			return fmt.Errorf("Escalation step %d must have contacts", i+1)
+		}
+		if step.Delay <= previousDelay {
+			return fmt.Errorf("Escalation step %d delay must be greater than delay of previous step", i+1)
+		}
+		previousDelay = step.Delay
+	}
 	return nil
 }
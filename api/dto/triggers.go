@@ -4,11 +4,13 @@ package dto
 import (
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/api/middleware"
 	"github.com/moira-alert/moira/checker"
+	"github.com/moira-alert/moira/database"
 	"github.com/moira-alert/moira/expression"
 	"github.com/moira-alert/moira/remote"
 	"github.com/moira-alert/moira/target"
@@ -44,7 +46,7 @@ type TriggerModel struct {
 	WarnValue *float64 `json:"warn_value"`
 	// ERROR threshold
 	ErrorValue *float64 `json:"error_value"`
-	// Could be: rising, falling, expression
+	// Could be: rising, falling, expression, composite, slo
 	TriggerType string `json:"trigger_type"`
 	// Set of tags to manipulate subscriptions
 	Tags []string `json:"tags"`
@@ -60,49 +62,105 @@ type TriggerModel struct {
 	Patterns []string `json:"patterns"`
 	// Shows if trigger is remote (graphite-backend) based or stored inside Moira-Redis DB
 	IsRemote bool `json:"is_remote"`
+	// Name of remote source to fetch remote trigger metrics from, "default" remote source is used if not set
+	RemoteSource string `json:"remote_source,omitempty"`
+	// Language of targets: graphite (default) or promql, promql targets can be used only with prometheus remote sources
+	TargetLanguage string `json:"target_language,omitempty"`
+	// If set, trigger state is calculated from the share of metrics in WARN and ERROR states
+	Aggregation *moira.Aggregation `json:"aggregation,omitempty"`
+	// IDs of triggers which states are used in composite trigger expression: t1, t2, ...
+	Inputs []string `json:"inputs,omitempty"`
+	// SLO settings of slo trigger, targets are: t1 - good events count, t2 - total events count
+	SLO *moira.SLO `json:"slo,omitempty"`
+	// Minimal interval between trigger checks in seconds, trigger is checked on every new metric value if it is not set
+	CheckInterval int64 `json:"check_interval,omitempty"`
+	// Interval and maximum number of repeated notifications about trigger or metric staying in the same bad state
+	Reminder *moira.Reminder `json:"reminder,omitempty"`
+	// TTL and TTL state of metrics which names match glob patterns, the first matching override is applied instead of trigger TTL and TTLState
+	TTLOverrides []moira.TTLOverride `json:"ttl_overrides,omitempty"`
 }
 
 // ToMoiraTrigger transforms TriggerModel to moira.Trigger
 func (model *TriggerModel) ToMoiraTrigger() *moira.Trigger {
 	return &moira.Trigger{
-		ID:          model.ID,
-		Name:        model.Name,
-		Desc:        model.Desc,
-		Targets:     model.Targets,
-		WarnValue:   model.WarnValue,
-		ErrorValue:  model.ErrorValue,
-		TriggerType: model.TriggerType,
-		Tags:        model.Tags,
-		TTLState:    model.TTLState,
-		TTL:         model.TTL,
-		Schedule:    model.Schedule,
-		Expression:  &model.Expression,
-		Patterns:    model.Patterns,
-		IsRemote:    model.IsRemote,
+		ID:             model.ID,
+		Name:           model.Name,
+		Desc:           model.Desc,
+		Targets:        model.Targets,
+		WarnValue:      model.WarnValue,
+		ErrorValue:     model.ErrorValue,
+		TriggerType:    model.TriggerType,
+		Tags:           model.Tags,
+		TTLState:       model.TTLState,
+		TTL:            model.TTL,
+		Schedule:       model.Schedule,
+		Expression:     &model.Expression,
+		Patterns:       model.Patterns,
+		IsRemote:       model.IsRemote,
+		RemoteSource:   model.RemoteSource,
+		TargetLanguage: model.TargetLanguage,
+		Aggregation:    model.Aggregation,
+		Inputs:         model.Inputs,
+		SLO:            model.SLO,
+		CheckInterval:  model.CheckInterval,
+		Reminder:       model.Reminder,
+		TTLOverrides:   model.TTLOverrides,
 	}
 }
 
 // CreateTriggerModel transforms moira.Trigger to TriggerModel
 func CreateTriggerModel(trigger *moira.Trigger) TriggerModel {
 	return TriggerModel{
-		ID:          trigger.ID,
-		Name:        trigger.Name,
-		Desc:        trigger.Desc,
-		Targets:     trigger.Targets,
-		WarnValue:   trigger.WarnValue,
-		ErrorValue:  trigger.ErrorValue,
-		TriggerType: trigger.TriggerType,
-		Tags:        trigger.Tags,
-		TTLState:    trigger.TTLState,
-		TTL:         trigger.TTL,
-		Schedule:    trigger.Schedule,
-		Expression:  moira.UseString(trigger.Expression),
-		Patterns:    trigger.Patterns,
-		IsRemote:    trigger.IsRemote,
+		ID:             trigger.ID,
+		Name:           trigger.Name,
+		Desc:           trigger.Desc,
+		Targets:        trigger.Targets,
+		WarnValue:      trigger.WarnValue,
+		ErrorValue:     trigger.ErrorValue,
+		TriggerType:    trigger.TriggerType,
+		Tags:           trigger.Tags,
+		TTLState:       trigger.TTLState,
+		TTL:            trigger.TTL,
+		Schedule:       trigger.Schedule,
+		Expression:     moira.UseString(trigger.Expression),
+		Patterns:       trigger.Patterns,
+		IsRemote:       trigger.IsRemote,
+		RemoteSource:   trigger.RemoteSource,
+		TargetLanguage: trigger.TargetLanguage,
+		Aggregation:    trigger.Aggregation,
+		Inputs:         trigger.Inputs,
+		SLO:            trigger.SLO,
+		CheckInterval:  trigger.CheckInterval,
+		Reminder:       trigger.Reminder,
+		TTLOverrides:   trigger.TTLOverrides,
 	}
 }
 
 func (trigger *Trigger) Bind(request *http.Request) error {
+	if trigger.CheckInterval < 0 {
+		return fmt.Errorf("check_interval can not be negative")
+	}
+	if trigger.RemoteSource != "" {
+		trigger.IsRemote = true
+	}
+	if err := checkTTLState(trigger.TTLState); err != nil {
+		return err
+	}
+	if err := checkReminder(trigger.Reminder); err != nil {
+		return err
+	}
+	if err := checkTTLOverrides(trigger.TTLOverrides); err != nil {
+		return err
+	}
+	if err := checkTargetLanguage(trigger); err != nil {
+		return err
+	}
+	switch trigger.TriggerType {
+	case moira.CompositeTrigger:
+		return bindCompositeTrigger(request, trigger)
+	case moira.SLOTrigger:
+		return bindSLOTrigger(request, trigger)
+	}
 	if len(trigger.Targets) == 0 {
 		return fmt.Errorf("targets is required")
 	}
@@ -115,6 +173,9 @@ func (trigger *Trigger) Bind(request *http.Request) error {
 	if err := checkWarnErrorExpression(trigger); err != nil {
 		return err
 	}
+	if err := checkAggregation(trigger.Aggregation); err != nil {
+		return err
+	}
 
 	triggerExpression := expression.TriggerExpression{
 		AdditionalTargetsValues: make(map[string]float64),
@@ -125,11 +186,6 @@ func (trigger *Trigger) Bind(request *http.Request) error {
 		Expression:              &trigger.Expression,
 	}
 
-	remoteCfg := middleware.GetRemoteConfig(request)
-	if trigger.IsRemote && !remoteCfg.IsEnabled() {
-		return fmt.Errorf("remote graphite storage is not enabled")
-	}
-
 	if err := resolvePatterns(request, trigger, &triggerExpression); err != nil {
 		return err
 	}
@@ -139,20 +195,229 @@ func (trigger *Trigger) Bind(request *http.Request) error {
 	return nil
 }
 
+func bindCompositeTrigger(request *http.Request, trigger *Trigger) error {
+	if len(trigger.Inputs) == 0 {
+		return fmt.Errorf("inputs is required")
+	}
+	if len(trigger.Tags) == 0 {
+		return fmt.Errorf("tags is required")
+	}
+	if trigger.Name == "" {
+		return fmt.Errorf("trigger name is required")
+	}
+	if trigger.Expression == "" {
+		return fmt.Errorf("trigger_type set to composite, but no expression provided")
+	}
+	if len(trigger.Targets) != 0 || trigger.IsRemote || trigger.Aggregation != nil {
+		return fmt.Errorf("composite trigger can not have targets, is_remote or aggregation")
+	}
+
+	inputsStates := make(map[string]string, len(trigger.Inputs))
+	for i := range trigger.Inputs {
+		inputsStates[fmt.Sprintf("t%v", i+1)] = checker.OK
+	}
+	triggerExpression := expression.TriggerExpression{
+		TriggerType:   trigger.TriggerType,
+		PreviousState: checker.NODATA,
+		Expression:    &trigger.Expression,
+		InputsStates:  inputsStates,
+	}
+	if _, err := triggerExpression.Evaluate(); err != nil {
+		return err
+	}
+
+	trigger.Patterns = make([]string, 0)
+	middleware.SetTimeSeriesNames(request, make(map[string]bool))
+	return nil
+}
+
+func bindSLOTrigger(request *http.Request, trigger *Trigger) error {
+	if len(trigger.Targets) != 2 {
+		return fmt.Errorf("slo trigger requires exactly two targets: t1 - good events count, t2 - total events count")
+	}
+	if len(trigger.Tags) == 0 {
+		return fmt.Errorf("tags is required")
+	}
+	if trigger.Name == "" {
+		return fmt.Errorf("trigger name is required")
+	}
+	if trigger.Aggregation != nil {
+		return fmt.Errorf("slo trigger can not have aggregation")
+	}
+	metricsTTL, err := getMetricsTTL(middleware.GetDatabase(request))
+	if err != nil {
+		return err
+	}
+	if err := checkSLO(trigger.SLO, trigger.IsRemote, metricsTTL); err != nil {
+		return err
+	}
+
+	triggerExpression := expression.TriggerExpression{
+		AdditionalTargetsValues: make(map[string]float64),
+	}
+	return resolvePatterns(request, trigger, &triggerExpression)
+}
+
+// checkTTLState checks that metrics without data are switched to one of severities or deleted
+func checkTTLState(ttlState *string) error {
+	if ttlState == nil || *ttlState == checker.DEL || moira.GetSeverityModel().IsSeverity(*ttlState) {
+		return nil
+	}
+	return fmt.Errorf("ttl_state must be one of: %s, %s", strings.Join(moira.GetSeverityModel().Names(), ", "), checker.DEL)
+}
+
+func checkTTLOverrides(overrides []moira.TTLOverride) error {
+	for _, override := range overrides {
+		if override.Pattern == "" {
+			return fmt.Errorf("ttl override pattern is required")
+		}
+		if err := moira.ValidateGraphitePattern(override.Pattern); err != nil {
+			return fmt.Errorf("ttl override pattern '%s' is invalid: %s", override.Pattern, err.Error())
+		}
+		if override.TTL < 0 {
+			return fmt.Errorf("ttl override of '%s' can not be negative", override.Pattern)
+		}
+		if err := checkTTLState(override.TTLState); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkReminder(reminder *moira.Reminder) error {
+	if reminder == nil {
+		return nil
+	}
+	if reminder.Interval < 0 {
+		return fmt.Errorf("reminder interval can not be negative")
+	}
+	if reminder.MaxCount < 0 {
+		return fmt.Errorf("reminder max_count can not be negative")
+	}
+	for state, interval := range reminder.StateIntervals {
+		if !moira.GetSeverityModel().IsSeverity(state) || state == checker.OK {
+			return fmt.Errorf("reminder state must be one of: %s", strings.Join(getBadSeverities(), ", "))
+		}
+		if interval < 0 {
+			return fmt.Errorf("reminder interval of %s state can not be negative", state)
+		}
+	}
+	return nil
+}
+
+// getBadSeverities returns severities which can be reminded about
+func getBadSeverities() []string {
+	severities := make([]string, 0)
+	for _, name := range moira.GetSeverityModel().Names() {
+		if name != checker.OK {
+			severities = append(severities, name)
+		}
+	}
+	return severities
+}
+
+// getAlertingSeverities returns severities which can be set when metric values are received
+func getAlertingSeverities() []string {
+	severities := make([]string, 0)
+	for _, name := range moira.GetSeverityModel().Names() {
+		if name != checker.OK && name != checker.NODATA {
+			severities = append(severities, name)
+		}
+	}
+	return severities
+}
+
+func checkTargetLanguage(trigger *Trigger) error {
+	switch trigger.TargetLanguage {
+	case "", moira.GraphiteTargetLanguage:
+		return nil
+	case moira.PromQLTargetLanguage:
+		if !trigger.IsRemote {
+			return fmt.Errorf("promql targets can be used only in remote triggers")
+		}
+		for _, tar := range trigger.Targets {
+			if err := remote.ValidatePromQL(tar); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("target_language must be one of: %s, %s", moira.GraphiteTargetLanguage, moira.PromQLTargetLanguage)
+	}
+}
+
+func getTargetLanguage(trigger *Trigger) string {
+	if trigger.TargetLanguage == "" {
+		return moira.GraphiteTargetLanguage
+	}
+	return trigger.TargetLanguage
+}
+
+// getMetricsTTL returns time to live of local metrics set by checker, 0 is returned if checker has not set it yet
+func getMetricsTTL(dataBase moira.Database) (int64, error) {
+	ttl, err := dataBase.GetMetricsTTL()
+	if err == database.ErrNil {
+		return 0, nil
+	}
+	return ttl, err
+}
+
+func checkSLO(slo *moira.SLO, isRemote bool, metricsTTL int64) error {
+	if slo == nil {
+		return fmt.Errorf("trigger_type set to slo, but no slo provided")
+	}
+	if slo.Objective <= 0 || slo.Objective >= 100 {
+		return fmt.Errorf("slo objective must be in range (0, 100), got %v", slo.Objective)
+	}
+	if slo.Period < 0 {
+		return fmt.Errorf("slo period can not be negative")
+	}
+	for _, window := range slo.Windows {
+		if window.Short <= 0 || window.Long <= window.Short {
+			return fmt.Errorf("slo window long must be greater than short, and short must be positive")
+		}
+		if window.BurnRate <= 0 {
+			return fmt.Errorf("slo window burn_rate must be positive")
+		}
+		if !moira.GetSeverityModel().IsSeverity(window.State) || window.State == checker.OK || window.State == checker.NODATA {
+			return fmt.Errorf("slo window state must be one of: %s", strings.Join(getAlertingSeverities(), ", "))
+		}
+	}
+	if !isRemote && metricsTTL > 0 && slo.GetLongestWindow() > metricsTTL {
+		return fmt.Errorf("slo window %s is longer than checker metrics ttl %s, set shorter windows or increase metrics ttl",
+			time.Duration(slo.GetLongestWindow())*time.Second, time.Duration(metricsTTL)*time.Second)
+	}
+	return nil
+}
+
 func resolvePatterns(request *http.Request, trigger *Trigger, expressionValues *expression.TriggerExpression) error {
 	now := time.Now().Unix()
 	targetNum := 1
 	trigger.Patterns = make([]string, 0)
 	timeSeriesNames := make(map[string]bool)
 
-	remoteCfg := middleware.GetRemoteConfig(request)
 	database := middleware.GetDatabase(request)
+	moiraTrigger := trigger.ToMoiraTrigger()
+	var remoteCfg *remote.Config
 	var err error
+	if trigger.IsRemote {
+		remoteSource := moiraTrigger.GetRemoteSource()
+		if remoteCfg, err = middleware.GetRemoteSources(request).Get(remoteSource); err != nil {
+			return err
+		}
+		if moiraTrigger.IsPromQL() != remoteCfg.IsPrometheus() {
+			return fmt.Errorf("remote source '%s' does not support %s targets", remoteSource, getTargetLanguage(trigger))
+		}
+	}
 
 	for _, tar := range trigger.Targets {
 		var timeSeries []*target.TimeSeries
 		if trigger.IsRemote {
-			timeSeries, err = remote.Fetch(remoteCfg, tar, now-600, now, false)
+			if moiraTrigger.IsPromQL() {
+				timeSeries, err = remote.FetchPrometheus(remoteCfg, tar, now-600, now, false)
+			} else {
+				timeSeries, err = remote.Fetch(remoteCfg, tar, now-600, now, false)
+			}
 			if err != nil {
 				return err
 			}
@@ -229,20 +494,45 @@ func checkWarnErrorExpression(trigger *Trigger) error {
 			return fmt.Errorf("trigger_type set to expression, but no expression provided")
 		}
 	default:
-		return fmt.Errorf("wrong trigger_type: %v, allowable values: '%v', '%v', '%v'",
-			trigger.TriggerType, moira.RisingTrigger, moira.FallingTrigger, moira.ExpressionTrigger)
+		return fmt.Errorf("wrong trigger_type: %v, allowable values: '%v', '%v', '%v', '%v', '%v'",
+			trigger.TriggerType, moira.RisingTrigger, moira.FallingTrigger, moira.ExpressionTrigger, moira.CompositeTrigger, moira.SLOTrigger)
 	}
 
 	return nil
 }
 
+func checkAggregation(aggregation *moira.Aggregation) error {
+	if aggregation == nil {
+		return nil
+	}
+	if aggregation.WarnPercent == nil && aggregation.ErrorPercent == nil {
+		return fmt.Errorf("at least one of aggregation warn_percent or error_percent is required")
+	}
+	for _, percent := range []*float64{aggregation.WarnPercent, aggregation.ErrorPercent} {
+		if percent != nil && (*percent <= 0 || *percent > 100) {
+			return fmt.Errorf("aggregation percent must be in range (0, 100], got %v", *percent)
+		}
+	}
+	if aggregation.MinCount < 0 {
+		return fmt.Errorf("aggregation min_count can not be negative")
+	}
+	return nil
+}
+
 func (*Trigger) Render(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
 type TriggerCheck struct {
 	*moira.CheckData
-	TriggerID string `json:"trigger_id"`
+	TriggerID  string               `json:"trigger_id"`
+	MetricsTTL map[string]MetricTTL `json:"metrics_ttl,omitempty"`
+}
+
+// MetricTTL is TTL and TTL state applied to trigger metric
+type MetricTTL struct {
+	TTL      int64  `json:"ttl"`
+	TTLState string `json:"ttl_state"`
 }
 
 func (*TriggerCheck) Render(w http.ResponseWriter, r *http.Request) error {
@@ -255,6 +545,18 @@ func (*MetricsMaintenance) Bind(r *http.Request) error {
 	return nil
 }
 
+// TriggerMaintenance is maintenance of the whole trigger, zero Until disables it
+type TriggerMaintenance struct {
+	Until int64 `json:"until"`
+}
+
+func (maintenance *TriggerMaintenance) Bind(r *http.Request) error {
+	if maintenance.Until < 0 {
+		return fmt.Errorf("maintenance end must not be negative")
+	}
+	return nil
+}
+
 type ThrottlingResponse struct {
 	Throttling int64 `json:"throttling"`
 }
@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+)
+
+func TestCheckSLO(t *testing.T) {
+	var metricsTTL int64 = 3600
+
+	Convey("Default windows fit default metrics ttl", t, func() {
+		slo := &moira.SLO{Objective: 99.9}
+		So(checkSLO(slo, false, metricsTTL), ShouldBeNil)
+	})
+
+	Convey("Windows longer than metrics ttl", t, func() {
+		slo := &moira.SLO{
+			Objective: 99.9,
+			Windows:   []moira.BurnRateWindow{{Long: 6 * 3600, Short: 1800, BurnRate: 6, State: "WARN"}},
+		}
+		So(checkSLO(slo, false, metricsTTL), ShouldResemble, fmt.Errorf("slo window 6h0m0s is longer than checker metrics ttl 1h0m0s, set shorter windows or increase metrics ttl"))
+		So(checkSLO(slo, true, metricsTTL), ShouldBeNil)
+		So(checkSLO(slo, false, 0), ShouldBeNil)
+	})
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"gopkg.in/tomb.v2"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/controller"
+	"github.com/moira-alert/moira/api/middleware"
+)
+
+// eventsStreamKeepAliveInterval is an interval of comments sent to idle stream to keep connection through proxies
+const eventsStreamKeepAliveInterval = 30 * time.Second
+
+func eventsStream(router chi.Router) {
+	router.Get("/stream", streamEvents)
+}
+
+// streamEvents sends trigger state changes and notification events as Server-Sent Events until client disconnects
+func streamEvents(writer http.ResponseWriter, request *http.Request) {
+	flusher := getFlusher(writer)
+	if flusher == nil {
+		render.Render(writer, request, api.ErrorInternalServer(fmt.Errorf("Streaming is not supported")))
+		return
+	}
+	filter := &controller.EventsStreamFilter{
+		TriggerIDs: getRequestTriggerIDs(request),
+		Tags:       getRequestTags(request),
+	}
+
+	var subscription tomb.Tomb
+	events, errorResponse := controller.SubscribeEventsStream(database, &subscription)
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse)
+		return
+	}
+	defer func() {
+		subscription.Kill(nil)
+		for range events {
+		}
+	}()
+
+	writer.Header().Set("Content-Type", "text/event-stream")
+	writer.Header().Set("Connection", "keep-alive")
+	writer.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAliveTicker := time.NewTicker(eventsStreamKeepAliveInterval)
+	defer keepAliveTicker.Stop()
+	for {
+		select {
+		case <-request.Context().Done():
+			return
+		case <-keepAliveTicker.C:
+			if _, err := fmt.Fprint(writer, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if !filter.Match(event) {
+				continue
+			}
+			if err := writeStreamEvent(writer, event); err != nil {
+				middleware.GetLoggerEntry(request).Errorf("Failed to write stream event: %s", err.Error())
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeStreamEvent(writer http.ResponseWriter, event *moira.StreamEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(writer, "event: %s\ndata: %s\n\n", event.Type, data)
+	return err
+}
+
+// getFlusher returns flusher of response writer or of writers wrapped by it
+func getFlusher(writer http.ResponseWriter) http.Flusher {
+	for {
+		if flusher, ok := writer.(http.Flusher); ok {
+			return flusher
+		}
+		wrapper, ok := writer.(interface {
+			Unwrap() http.ResponseWriter
+		})
+		if !ok {
+			return nil
+		}
+		writer = wrapper.Unwrap()
+	}
+}
+
+func getRequestTriggerIDs(request *http.Request) []string {
+	var triggerIDs []string
+	i := 0
+	for {
+		triggerID := request.FormValue(fmt.Sprintf("triggerIds[%v]", i))
+		if triggerID == "" {
+			break
+		}
+		triggerIDs = append(triggerIDs, triggerID)
+		i++
+	}
+	return triggerIDs
+}
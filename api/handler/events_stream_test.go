@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/mock/moira-alert"
+)
+
+func TestStreamEvents(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	database = dataBase
+
+	Convey("Events matching filter should be written to stream", t, func() {
+		events := make(chan *moira.StreamEvent, 3)
+		events <- &moira.StreamEvent{Type: moira.StateChangeStreamEvent, TriggerID: "trigger1", Tags: []string{"db"}, Timestamp: 100, State: "ERROR", OldState: "OK"}
+		events <- &moira.StreamEvent{Type: moira.StateChangeStreamEvent, TriggerID: "trigger2", Tags: []string{"db"}, Timestamp: 100, State: "OK", OldState: "ERROR"}
+		events <- &moira.StreamEvent{Type: moira.MetricStateChangeStreamEvent, TriggerID: "trigger1", Tags: []string{"db"}, Metric: "db.cpu", Timestamp: 100, State: "ERROR"}
+		close(events)
+		dataBase.EXPECT().SubscribeStreamEvents(gomock.Any()).Return((<-chan *moira.StreamEvent)(events), nil)
+
+		request := httptest.NewRequest("GET", "/stream?triggerIds[0]=trigger1&tags[0]=db", nil)
+		recorder := httptest.NewRecorder()
+		streamEvents(recorder, request)
+
+		So(recorder.Code, ShouldEqual, http.StatusOK)
+		So(recorder.Header().Get("Content-Type"), ShouldEqual, "text/event-stream")
+		So(recorder.Body.String(), ShouldEqual, "event: state\n"+
+			`data: {"type":"state","trigger_id":"trigger1","tags":["db"],"timestamp":100,"state":"ERROR","old_state":"OK"}`+"\n\n"+
+			"event: metric_state\n"+
+			`data: {"type":"metric_state","trigger_id":"trigger1","tags":["db"],"metric":"db.cpu","timestamp":100,"state":"ERROR"}`+"\n\n")
+	})
+
+	Convey("Subscription error should be returned", t, func() {
+		dataBase.EXPECT().SubscribeStreamEvents(gomock.Any()).Return(nil, fmt.Errorf("Oooops! Can not subscribe"))
+
+		request := httptest.NewRequest("GET", "/stream", nil)
+		recorder := httptest.NewRecorder()
+		streamEvents(recorder, request)
+
+		So(recorder.Code, ShouldEqual, http.StatusInternalServerError)
+	})
+}
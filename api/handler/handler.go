@@ -19,9 +19,10 @@ var database moira.Database
 
 const contactKey moira_middle.ContextKey = "contact"
 const subscriptionKey moira_middle.ContextKey = "subscription"
+const rotationKey moira_middle.ContextKey = "rotation"
 
 // NewHandler creates new api handler request uris based on github.com/go-chi/chi
-func NewHandler(db moira.Database, log moira.Logger, config *api.Config, remoteConfig *remote.Config, configFile []byte) http.Handler {
+func NewHandler(db moira.Database, log moira.Logger, config *api.Config, remoteSources remote.Sources, configFile []byte) http.Handler {
 	database = db
 	router := chi.NewRouter()
 	router.Use(render.SetContentType(render.ContentTypeJSON))
@@ -36,13 +37,16 @@ func NewHandler(db moira.Database, log moira.Logger, config *api.Config, remoteC
 		router.Use(moira_middle.DatabaseContext(database))
 		router.Get("/config", webConfig(configFile))
 		router.Route("/user", user)
-		router.Route("/trigger", triggers(remoteConfig))
+		router.Route("/trigger", triggers(remoteSources))
 		router.Route("/tag", tag)
 		router.Route("/pattern", pattern)
 		router.Route("/event", event)
+		router.Route("/events", eventsStream)
 		router.Route("/contact", contact)
 		router.Route("/subscription", subscription)
 		router.Route("/notification", notification)
+		router.Route("/maintenance", maintenance)
+		router.Route("/rotation", rotation)
 		router.Route("/health", health)
 	})
 	if config.EnableCORS {
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/controller"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/api/middleware"
+)
+
+func maintenance(router chi.Router) {
+	router.Get("/", getMaintenanceWindows)
+	router.Put("/", createMaintenanceWindow)
+	router.Route("/{maintenanceId}", func(router chi.Router) {
+		router.Use(middleware.MaintenanceWindowContext)
+		router.Get("/", getMaintenanceWindow)
+		router.Delete("/", removeMaintenanceWindow)
+	})
+}
+
+func getMaintenanceWindows(writer http.ResponseWriter, request *http.Request) {
+	windows, err := controller.GetMaintenanceWindows(database, time.Now().Unix())
+	if err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+	if err := render.Render(writer, request, windows); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
+func createMaintenanceWindow(writer http.ResponseWriter, request *http.Request) {
+	window := &dto.MaintenanceWindow{}
+	if err := render.Bind(request, window); err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err))
+		return
+	}
+	userLogin := middleware.GetLogin(request)
+
+	if err := controller.CreateMaintenanceWindow(database, window, userLogin, time.Now().Unix()); err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+	if err := render.Render(writer, request, window); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
+func getMaintenanceWindow(writer http.ResponseWriter, request *http.Request) {
+	windowID := middleware.GetMaintenanceWindowID(request)
+	window, err := controller.GetMaintenanceWindow(database, windowID, time.Now().Unix())
+	if err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+	if err := render.Render(writer, request, window); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
+func removeMaintenanceWindow(writer http.ResponseWriter, request *http.Request) {
+	windowID := middleware.GetMaintenanceWindowID(request)
+	if err := controller.RemoveMaintenanceWindow(database, windowID); err != nil {
+		render.Render(writer, request, err)
+	}
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/controller"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/api/middleware"
+)
+
+func rotation(router chi.Router) {
+	router.Get("/", getUserRotations)
+	router.Put("/", createRotation)
+	router.Route("/{rotationId}", func(router chi.Router) {
+		router.Use(middleware.RotationContext)
+		router.Use(rotationFilter)
+		router.Get("/", getRotation)
+		router.Put("/", updateRotation)
+		router.Delete("/", removeRotation)
+	})
+}
+
+func getUserRotations(writer http.ResponseWriter, request *http.Request) {
+	userLogin := middleware.GetLogin(request)
+	rotations, err := controller.GetUserRotations(database, userLogin)
+	if err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+	if err := render.Render(writer, request, rotations); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
+func createRotation(writer http.ResponseWriter, request *http.Request) {
+	rotation := &dto.Rotation{}
+	if err := render.Bind(request, rotation); err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err))
+		return
+	}
+	userLogin := middleware.GetLogin(request)
+
+	if err := controller.CreateRotation(database, rotation, userLogin); err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+	if err := render.Render(writer, request, rotation); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
+// rotationFilter is middleware for check rotation existence and user permissions
+func rotationFilter(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		rotationID := middleware.GetRotationID(request)
+		userLogin := middleware.GetLogin(request)
+		rotationData, err := controller.CheckUserPermissionsForRotation(database, rotationID, userLogin)
+		if err != nil {
+			render.Render(writer, request, err)
+			return
+		}
+		ctx := context.WithValue(request.Context(), rotationKey, rotationData)
+		next.ServeHTTP(writer, request.WithContext(ctx))
+	})
+}
+
+func getRotation(writer http.ResponseWriter, request *http.Request) {
+	rotationData := request.Context().Value(rotationKey).(moira.Rotation)
+	rotation := dto.Rotation(rotationData)
+	if err := render.Render(writer, request, &rotation); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
+func updateRotation(writer http.ResponseWriter, request *http.Request) {
+	rotation := &dto.Rotation{}
+	if err := render.Bind(request, rotation); err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err))
+		return
+	}
+	rotationData := request.Context().Value(rotationKey).(moira.Rotation)
+
+	if err := controller.UpdateRotation(database, rotation, rotationData); err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+	if err := render.Render(writer, request, rotation); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
+func removeRotation(writer http.ResponseWriter, request *http.Request) {
+	rotationData := request.Context().Value(rotationKey).(moira.Rotation)
+	if err := controller.RemoveRotation(database, rotationData); err != nil {
+		render.Render(writer, request, err)
+	}
+}
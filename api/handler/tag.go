@@ -15,6 +15,7 @@ func tag(router chi.Router) {
 	router.Route("/{tag}", func(router chi.Router) {
 		router.Use(middleware.TagContext)
 		router.Delete("/", removeTag)
+		router.With(middleware.DateRange("-30days", "now")).Get("/stats", getTagStateStats)
 	})
 }
 
@@ -56,3 +57,20 @@ func removeTag(writer http.ResponseWriter, request *http.Request) {
 		return
 	}
 }
+
+func getTagStateStats(writer http.ResponseWriter, request *http.Request) {
+	tagName := middleware.GetTag(request)
+	from, to, errorResponse := getDateRange(request)
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse)
+		return
+	}
+	stats, errorResponse := controller.GetTagStateStats(database, tagName, from, to)
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse)
+		return
+	}
+	if err := render.Render(writer, request, stats); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
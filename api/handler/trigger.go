@@ -33,6 +33,14 @@ func trigger(router chi.Router) {
 		router.Delete("/", deleteTriggerMetric)
 	})
 	router.Put("/maintenance", setMetricsMaintenance)
+	router.Put("/maintenance/trigger", setTriggerMaintenance)
+	router.Route("/ack", func(router chi.Router) {
+		router.Put("/", acknowledgeTriggerState)
+		router.Delete("/", removeTriggerStateAcknowledgement)
+	})
+	router.Get("/escalations", getTriggerEscalations)
+	router.With(middleware.DateRange("-30days", "now")).Get("/timeline", getTriggerTimeline)
+	router.With(middleware.DateRange("-30days", "now")).Get("/stats", getTriggerStateStats)
 }
 
 func updateTrigger(writer http.ResponseWriter, request *http.Request) {
@@ -44,6 +52,8 @@ func updateTrigger(writer http.ResponseWriter, request *http.Request) {
 			render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Invalid graphite targets: %s", err.Error())))
 		case expression.ErrInvalidExpression:
 			render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Invalid expression: %s", err.Error())))
+		case remote.ErrInvalidPromQL:
+			render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Invalid PromQL targets: %s", err.Error())))
 		case remote.ErrRemoteTriggerResponse:
 			render.Render(writer, request, api.ErrorRemoteServerUnavailable(err))
 		default:
@@ -122,16 +132,9 @@ func deleteThrottling(writer http.ResponseWriter, request *http.Request) {
 
 func getTriggerMetrics(writer http.ResponseWriter, request *http.Request) {
 	triggerID := middleware.GetTriggerID(request)
-	fromStr := middleware.GetFromStr(request)
-	toStr := middleware.GetToStr(request)
-	from := date.DateParamToEpoch(fromStr, "UTC", 0, time.UTC)
-	if from == 0 {
-		render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Can not parse from: %s", fromStr)))
-		return
-	}
-	to := date.DateParamToEpoch(toStr, "UTC", 0, time.UTC)
-	if to == 0 {
-		render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Can not parse to: %v", to)))
+	from, to, errorResponse := getDateRange(request)
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse)
 		return
 	}
 	triggerMetrics, err := controller.GetTriggerMetrics(database, from, to, triggerID)
@@ -164,3 +167,103 @@ func setMetricsMaintenance(writer http.ResponseWriter, request *http.Request) {
 		render.Render(writer, request, err)
 	}
 }
+
+func setTriggerMaintenance(writer http.ResponseWriter, request *http.Request) {
+	triggerID := middleware.GetTriggerID(request)
+	triggerMaintenance := dto.TriggerMaintenance{}
+	if err := render.Bind(request, &triggerMaintenance); err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err))
+		return
+	}
+	err := controller.SetTriggerMaintenance(database, triggerID, triggerMaintenance)
+	if err != nil {
+		render.Render(writer, request, err)
+	}
+}
+
+func acknowledgeTriggerState(writer http.ResponseWriter, request *http.Request) {
+	triggerID := middleware.GetTriggerID(request)
+	acknowledgementRequest := dto.AcknowledgementRequest{}
+	if err := render.Bind(request, &acknowledgementRequest); err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err))
+		return
+	}
+	userLogin := middleware.GetLogin(request)
+	acknowledgement, err := controller.AcknowledgeTriggerState(database, triggerID, acknowledgementRequest.Metric, userLogin, time.Now().Unix())
+	if err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+	if err := render.Render(writer, request, acknowledgement); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
+func removeTriggerStateAcknowledgement(writer http.ResponseWriter, request *http.Request) {
+	triggerID := middleware.GetTriggerID(request)
+	metric := request.URL.Query().Get("metric")
+	if err := controller.RemoveTriggerStateAcknowledgement(database, triggerID, metric); err != nil {
+		render.Render(writer, request, err)
+	}
+}
+
+func getTriggerEscalations(writer http.ResponseWriter, request *http.Request) {
+	triggerID := middleware.GetTriggerID(request)
+	escalations, err := controller.GetTriggerEscalations(database, triggerID)
+	if err != nil {
+		render.Render(writer, request, err)
+		return
+	}
+	if err := render.Render(writer, request, escalations); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
+func getTriggerTimeline(writer http.ResponseWriter, request *http.Request) {
+	triggerID := middleware.GetTriggerID(request)
+	from, to, errorResponse := getDateRange(request)
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse)
+		return
+	}
+	timeline, errorResponse := controller.GetTriggerTimeline(database, triggerID, from, to)
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse)
+		return
+	}
+	if err := render.Render(writer, request, timeline); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
+func getTriggerStateStats(writer http.ResponseWriter, request *http.Request) {
+	triggerID := middleware.GetTriggerID(request)
+	from, to, errorResponse := getDateRange(request)
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse)
+		return
+	}
+	stats, errorResponse := controller.GetTriggerStateStats(database, triggerID, from, to)
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse)
+		return
+	}
+	if err := render.Render(writer, request, stats); err != nil {
+		render.Render(writer, request, api.ErrorRender(err))
+	}
+}
+
+// getDateRange parses time range set in DateRange middleware
+func getDateRange(request *http.Request) (int64, int64, *api.ErrorResponse) {
+	fromStr := middleware.GetFromStr(request)
+	toStr := middleware.GetToStr(request)
+	from := date.DateParamToEpoch(fromStr, "UTC", 0, time.UTC)
+	if from == 0 {
+		return 0, 0, api.ErrorInvalidRequest(fmt.Errorf("Can not parse from: %s", fromStr))
+	}
+	to := date.DateParamToEpoch(toStr, "UTC", 0, time.UTC)
+	if to == 0 {
+		return 0, 0, api.ErrorInvalidRequest(fmt.Errorf("Can not parse to: %s", toStr))
+	}
+	return from, to, nil
+}
@@ -17,9 +17,9 @@ import (
 	"github.com/moira-alert/moira/target"
 )
 
-func triggers(cfg *remote.Config) func(chi.Router) {
+func triggers(sources remote.Sources) func(chi.Router) {
 	return func(router chi.Router) {
-		router.Use(middleware.RemoteConfigContext(cfg))
+		router.Use(middleware.RemoteSourcesContext(sources))
 		router.Get("/", getAllTriggers)
 		router.Put("/", createTrigger)
 		router.With(middleware.Paginate(0, 10)).Get("/page", getTriggersPage)
@@ -48,6 +48,8 @@ func createTrigger(writer http.ResponseWriter, request *http.Request) {
 			render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Invalid graphite targets: %s", err.Error())))
 		case expression.ErrInvalidExpression:
 			render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Invalid expression: %s", err.Error())))
+		case remote.ErrInvalidPromQL:
+			render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("Invalid PromQL targets: %s", err.Error())))
 		case remote.ErrRemoteTriggerResponse:
 			render.Render(writer, request, api.ErrorRemoteServerUnavailable(err))
 		default:
@@ -58,6 +58,32 @@ func ContactContext(next http.Handler) http.Handler {
 	})
 }
 
+// MaintenanceWindowContext gets maintenanceId from parsed URI corresponding to maintenance routes and set it to request context
+func MaintenanceWindowContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		windowID := chi.URLParam(request, "maintenanceId")
+		if windowID == "" {
+			render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("MaintenanceID must be set")))
+			return
+		}
+		ctx := context.WithValue(request.Context(), maintenanceIDKey, windowID)
+		next.ServeHTTP(writer, request.WithContext(ctx))
+	})
+}
+
+// RotationContext gets rotationId from parsed URI corresponding to rotation routes and set it to request context
+func RotationContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		rotationID := chi.URLParam(request, "rotationId")
+		if rotationID == "" {
+			render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("RotationID must be set")))
+			return
+		}
+		ctx := context.WithValue(request.Context(), rotationIDKey, rotationID)
+		next.ServeHTTP(writer, request.WithContext(ctx))
+	})
+}
+
 // TagContext gets tagName from parsed URI corresponding to tag routes and set it to request context
 func TagContext(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
@@ -84,11 +110,11 @@ func SubscriptionContext(next http.Handler) http.Handler {
 	})
 }
 
-// RemoteConfigContext adds remote config struct to request context
-func RemoteConfigContext(cfg *remote.Config) func(next http.Handler) http.Handler {
+// RemoteSourcesContext adds remote sources configs to request context
+func RemoteSourcesContext(sources remote.Sources) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
-			ctx := context.WithValue(request.Context(), remoteConfigKey, cfg)
+			ctx := context.WithValue(request.Context(), remoteSourcesKey, sources)
 			next.ServeHTTP(writer, request.WithContext(ctx))
 		})
 	}
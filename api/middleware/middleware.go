@@ -27,7 +27,9 @@ var (
 	toKey              ContextKey = "to"
 	loginKey           ContextKey = "login"
 	timeSeriesNamesKey ContextKey = "timeSeriesNames"
-	remoteConfigKey    ContextKey = "remoteConfig"
+	remoteSourcesKey   ContextKey = "remoteSources"
+	maintenanceIDKey   ContextKey = "maintenanceID"
+	rotationIDKey      ContextKey = "rotationID"
 )
 
 // GetDatabase gets moira.Database realization from request context
@@ -60,6 +62,16 @@ func GetContactID(request *http.Request) string {
 	return request.Context().Value(contactIDKey).(string)
 }
 
+// GetMaintenanceWindowID gets maintenance window ID string from request context, which was sets in MaintenanceWindowContext middleware
+func GetMaintenanceWindowID(request *http.Request) string {
+	return request.Context().Value(maintenanceIDKey).(string)
+}
+
+// GetRotationID gets rotation ID string from request context, which was sets in RotationContext middleware
+func GetRotationID(request *http.Request) string {
+	return request.Context().Value(rotationIDKey).(string)
+}
+
 // GetPage gets page value from request context, which was sets in Paginate middleware
 func GetPage(request *http.Request) int64 {
 	return request.Context().Value(pageKey).(int64)
@@ -91,7 +103,7 @@ func GetTimeSeriesNames(request *http.Request) map[string]bool {
 	return request.Context().Value(timeSeriesNamesKey).(map[string]bool)
 }
 
-// GetRemoteConfig gets remote config from request context
-func GetRemoteConfig(request *http.Request) *remote.Config {
-	return request.Context().Value(remoteConfigKey).(*remote.Config)
+// GetRemoteSources gets remote sources configs from request context
+func GetRemoteSources(request *http.Request) remote.Sources {
+	return request.Context().Value(remoteSourcesKey).(remote.Sources)
 }
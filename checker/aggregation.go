@@ -0,0 +1,47 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/moira-alert/moira"
+)
+
+// getAggregatedState calculates trigger state from the share of trigger metrics in WARN and ERROR states
+func (triggerChecker *TriggerChecker) getAggregatedState(checkData moira.CheckData) (string, string) {
+	aggregation := triggerChecker.trigger.Aggregation
+	total := int64(len(checkData.Metrics))
+	if total == 0 {
+		return OK, ""
+	}
+	statesCount := checkData.GetMetricsStatesCount()
+	errorCount := statesCount[ERROR]
+	badCount := statesCount[WARN] + errorCount
+
+	state := OK
+	switch {
+	case isAggregationThresholdReached(errorCount, total, aggregation.ErrorPercent, aggregation.MinCount):
+		state = ERROR
+	case isAggregationThresholdReached(badCount, total, aggregation.WarnPercent, aggregation.MinCount):
+		state = WARN
+	}
+	message := fmt.Sprintf("%d of %d metrics in ERROR (%s), %d in WARN (%s), %d in NODATA",
+		errorCount, total, formatPercent(errorCount, total),
+		statesCount[WARN], formatPercent(statesCount[WARN], total),
+		statesCount[NODATA])
+	return state, message
+}
+
+func isAggregationThresholdReached(count, total int64, thresholdPercent *float64, minCount int64) bool {
+	if thresholdPercent == nil || count == 0 || count < minCount {
+		return false
+	}
+	return getPercent(count, total) >= *thresholdPercent
+}
+
+func getPercent(count, total int64) float64 {
+	return float64(count) * 100 / float64(total)
+}
+
+func formatPercent(count, total int64) string {
+	return fmt.Sprintf("%.1f%%", getPercent(count, total))
+}
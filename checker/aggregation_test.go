@@ -0,0 +1,75 @@
+package checker
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+)
+
+func TestGetAggregatedState(t *testing.T) {
+	var warnPercent float64 = 20
+	var errorPercent float64 = 50
+	triggerChecker := TriggerChecker{
+		trigger: &moira.Trigger{
+			Aggregation: &moira.Aggregation{
+				WarnPercent:  &warnPercent,
+				ErrorPercent: &errorPercent,
+			},
+		},
+	}
+
+	getCheckData := func(states ...string) moira.CheckData {
+		checkData := moira.CheckData{Metrics: make(map[string]moira.MetricState)}
+		for i, state := range states {
+			checkData.Metrics[string(rune('a'+i))] = moira.MetricState{State: state}
+		}
+		return checkData
+	}
+
+	Convey("No metrics, should be OK", t, func() {
+		state, message := triggerChecker.getAggregatedState(getCheckData())
+		So(state, ShouldEqual, OK)
+		So(message, ShouldBeEmpty)
+	})
+
+	Convey("All metrics are OK", t, func() {
+		state, message := triggerChecker.getAggregatedState(getCheckData(OK, OK, OK, OK))
+		So(state, ShouldEqual, OK)
+		So(message, ShouldEqual, "0 of 4 metrics in ERROR (0.0%), 0 in WARN (0.0%), 0 in NODATA")
+	})
+
+	Convey("Share of bad metrics reaches warn percent", t, func() {
+		state, message := triggerChecker.getAggregatedState(getCheckData(OK, OK, OK, WARN, NODATA))
+		So(state, ShouldEqual, WARN)
+		So(message, ShouldEqual, "0 of 5 metrics in ERROR (0.0%), 1 in WARN (20.0%), 1 in NODATA")
+	})
+
+	Convey("WARN and ERROR metrics are summed up for warn percent", t, func() {
+		state, _ := triggerChecker.getAggregatedState(getCheckData(OK, OK, OK, OK, OK, OK, OK, OK, WARN, ERROR))
+		So(state, ShouldEqual, WARN)
+	})
+
+	Convey("Share of ERROR metrics reaches error percent", t, func() {
+		state, message := triggerChecker.getAggregatedState(getCheckData(OK, ERROR, ERROR, WARN))
+		So(state, ShouldEqual, ERROR)
+		So(message, ShouldEqual, "2 of 4 metrics in ERROR (50.0%), 1 in WARN (25.0%), 0 in NODATA")
+	})
+
+	Convey("Min count is not reached", t, func() {
+		triggerChecker.trigger.Aggregation.MinCount = 3
+		defer func() { triggerChecker.trigger.Aggregation.MinCount = 0 }()
+		state, _ := triggerChecker.getAggregatedState(getCheckData(OK, ERROR, ERROR, WARN))
+		So(state, ShouldEqual, ERROR)
+		state, _ = triggerChecker.getAggregatedState(getCheckData(OK, ERROR, WARN))
+		So(state, ShouldEqual, OK)
+	})
+
+	Convey("Only warn percent is set", t, func() {
+		triggerChecker.trigger.Aggregation.ErrorPercent = nil
+		defer func() { triggerChecker.trigger.Aggregation.ErrorPercent = &errorPercent }()
+		state, _ := triggerChecker.getAggregatedState(getCheckData(ERROR, ERROR))
+		So(state, ShouldEqual, WARN)
+	})
+}
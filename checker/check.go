@@ -43,7 +43,16 @@ func (err ErrTriggerHasSameTimeSeriesNames) Error() string {
 // Check handle trigger and last check and write new state of trigger, if state were change then write new NotificationEvent
 func (triggerChecker *TriggerChecker) Check() error {
 	triggerChecker.Logger.Debugf("Checking trigger %s", triggerChecker.TriggerID)
-	checkData, err := triggerChecker.handleMetricsCheck()
+	var checkData moira.CheckData
+	var err error
+	switch {
+	case triggerChecker.trigger.IsComposite():
+		checkData, err = triggerChecker.handleCompositeCheck()
+	case triggerChecker.trigger.IsSLO():
+		checkData, err = triggerChecker.handleSLOCheck()
+	default:
+		checkData, err = triggerChecker.handleMetricsCheck()
+	}
 
 	checkData, err = triggerChecker.handleTriggerCheck(checkData, err)
 	if err != nil {
@@ -51,7 +60,19 @@ func (triggerChecker *TriggerChecker) Check() error {
 	}
 
 	checkData.UpdateScore()
-	return triggerChecker.Database.SetTriggerLastCheck(triggerChecker.TriggerID, &checkData, triggerChecker.trigger.IsRemote)
+	triggerChecker.updateStateHistory(&checkData)
+	if err = triggerChecker.Database.SetTriggerLastCheck(triggerChecker.TriggerID, &checkData, triggerChecker.trigger.GetRemoteSource()); err != nil {
+		return err
+	}
+	triggerChecker.saveStateHistory()
+	triggerChecker.publishMetricsStateChanges(checkData)
+	// Composite triggers depend only on input triggers states, so recheck them only if the state has changed
+	// State change is also published to real-time subscribers
+	if checkData.State != triggerChecker.lastCheck.State {
+		triggerChecker.publishStateChange(checkData)
+		return triggerChecker.scheduleCompositeTriggersCheck()
+	}
+	return nil
 }
 
 func (triggerChecker *TriggerChecker) handleMetricsCheck() (moira.CheckData, error) {
@@ -151,7 +172,14 @@ func (triggerChecker *TriggerChecker) checkTimeSeries(timeSeries *target.TimeSer
 
 func (triggerChecker *TriggerChecker) handleTriggerCheck(checkData moira.CheckData, checkingError error) (moira.CheckData, error) {
 	if checkingError == nil {
-		checkData.State = OK
+		switch {
+		case triggerChecker.trigger.IsComposite(), triggerChecker.trigger.IsSLO():
+			// State is already evaluated from input triggers states or error budget burn rates
+		case triggerChecker.trigger.IsAggregated():
+			checkData.State, checkData.Message = triggerChecker.getAggregatedState(checkData)
+		default:
+			checkData.State = OK
+		}
 		return triggerChecker.compareTriggerStates(checkData)
 	}
 
@@ -163,10 +191,11 @@ func (triggerChecker *TriggerChecker) handleTriggerCheck(checkData moira.CheckDa
 			checkData.State = triggerState
 			checkData.Message = checkingError.Error()
 			if triggerChecker.ttl == 0 {
+				checkData.Maintenance = triggerChecker.lastCheck.Maintenance
 				return checkData, nil
 			}
 		}
-	case target.ErrUnknownFunction:
+	case target.ErrUnknownFunction, remote.ErrInvalidPromQL:
 		triggerChecker.Logger.Warningf("Trigger %s: %s", triggerChecker.TriggerID, checkingError.Error())
 		checkData.State = EXCEPTION
 		checkData.Message = checkingError.Error()
@@ -190,21 +219,22 @@ func (triggerChecker *TriggerChecker) handleTriggerCheck(checkData moira.CheckDa
 }
 
 func (triggerChecker *TriggerChecker) checkForNoData(timeSeries *target.TimeSeries, metricLastState moira.MetricState) (bool, *moira.MetricState) {
-	if triggerChecker.ttl == 0 {
+	ttl, ttlState := triggerChecker.trigger.GetTTLOverride(timeSeries.Name).Apply(triggerChecker.ttl, triggerChecker.ttlState)
+	if ttl == 0 {
 		return false, nil
 	}
 	lastCheckTimeStamp := triggerChecker.lastCheck.Timestamp
 
-	if metricLastState.Timestamp+triggerChecker.ttl >= lastCheckTimeStamp {
+	if metricLastState.Timestamp+ttl >= lastCheckTimeStamp {
 		return false, nil
 	}
 	triggerChecker.Logger.Debugf("[TriggerID:%s][TimeSeries:%s] Metric TTL expired for state %v", triggerChecker.TriggerID, timeSeries.Name, metricLastState)
-	if triggerChecker.ttlState == DEL && metricLastState.EventTimestamp != 0 {
+	if ttlState == DEL && metricLastState.EventTimestamp != 0 {
 		return true, nil
 	}
 	return false, &moira.MetricState{
-		State:       toMetricState(triggerChecker.ttlState),
-		Timestamp:   lastCheckTimeStamp - triggerChecker.ttl,
+		State:       toMetricState(ttlState),
+		Timestamp:   lastCheckTimeStamp - ttl,
 		Value:       nil,
 		Maintenance: metricLastState.Maintenance,
 		Suppressed:  metricLastState.Suppressed,
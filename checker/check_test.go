@@ -280,7 +280,7 @@ func TestCheckForNODATA(t *testing.T) {
 		MetricData: types.MetricData{FetchResponse: fetchResponse1},
 	}
 	Convey("No TTL", t, func() {
-		triggerChecker := TriggerChecker{}
+		triggerChecker := TriggerChecker{trigger: &moira.Trigger{}}
 		needToDeleteMetric, currentState := triggerChecker.checkForNoData(timeSeries, metricLastState)
 		So(needToDeleteMetric, ShouldBeFalse)
 		So(currentState, ShouldBeNil)
@@ -291,6 +291,7 @@ func TestCheckForNODATA(t *testing.T) {
 		Metrics: metrics.ConfigureCheckerMetrics("checker", false),
 		Logger:  logger,
 		ttl:     ttl,
+		trigger: &moira.Trigger{},
 		lastCheck: &moira.CheckData{
 			Timestamp: 1000,
 		},
@@ -361,6 +362,44 @@ func TestCheckForNODATA(t *testing.T) {
 			})
 		})
 	})
+
+	ttlStateError := ERROR
+	triggerChecker.trigger = &moira.Trigger{
+		TTLOverrides: []moira.TTLOverride{
+			{Pattern: "main.*", TTL: 300, TTLState: &ttlStateError},
+			{Pattern: "main.metric", TTL: 0},
+		},
+	}
+
+	Convey("TTL override matches metric", t, func() {
+		Convey("Override TTL is not expired", func() {
+			metricLastState.Timestamp = 701
+			needToDeleteMetric, currentState := triggerChecker.checkForNoData(timeSeries, metricLastState)
+			So(needToDeleteMetric, ShouldBeFalse)
+			So(currentState, ShouldBeNil)
+		})
+
+		Convey("Override TTL is expired", func() {
+			metricLastState.Timestamp = 699
+			needToDeleteMetric, currentState := triggerChecker.checkForNoData(timeSeries, metricLastState)
+			So(needToDeleteMetric, ShouldBeFalse)
+			So(currentState, ShouldResemble, &moira.MetricState{
+				State:       ERROR,
+				Timestamp:   triggerChecker.lastCheck.Timestamp - 300,
+				Value:       nil,
+				Maintenance: metricLastState.Maintenance,
+				Suppressed:  metricLastState.Suppressed,
+			})
+		})
+
+		Convey("The first matching override is used", func() {
+			triggerChecker.trigger.TTLOverrides[0].Pattern = "other.*"
+			metricLastState.Timestamp = 1
+			needToDeleteMetric, currentState := triggerChecker.checkForNoData(timeSeries, metricLastState)
+			So(needToDeleteMetric, ShouldBeFalse)
+			So(currentState, ShouldBeNil)
+		})
+	})
 }
 
 func TestCheckErrors(t *testing.T) {
@@ -449,12 +488,21 @@ func TestCheckErrors(t *testing.T) {
 		},
 	}
 
+	lastCheckMetrics := map[string]moira.MetricState{
+		metric: {
+			State:          OK,
+			Timestamp:      26,
+			StateTimestamp: 26,
+		},
+	}
+
 	Convey("GetTimeSeries error", t, func() {
 		lastCheck := moira.CheckData{
-			Metrics:        triggerChecker.lastCheck.Metrics,
+			Metrics:        lastCheckMetrics,
 			State:          OK,
 			Timestamp:      triggerChecker.Until,
 			EventTimestamp: triggerChecker.Until,
+			StateTimestamp: triggerChecker.Until,
 			Score:          0,
 			Message:        "",
 		}
@@ -462,7 +510,7 @@ func TestCheckErrors(t *testing.T) {
 		dataBase.EXPECT().GetPatternMetrics(pattern).Return([]string{metric}, nil)
 		dataBase.EXPECT().GetMetricRetention(metric).Return(retention, nil)
 		dataBase.EXPECT().GetMetricsValues([]string{metric}, triggerChecker.From, triggerChecker.Until).Return(nil, metricErr)
-		dataBase.EXPECT().SetTriggerLastCheck(triggerChecker.TriggerID, &lastCheck, triggerChecker.trigger.IsRemote).Return(nil)
+		dataBase.EXPECT().SetTriggerLastCheck(triggerChecker.TriggerID, &lastCheck, triggerChecker.trigger.GetRemoteSource()).Return(nil)
 		err := triggerChecker.Check()
 		So(err, ShouldBeNil)
 	})
@@ -480,10 +528,11 @@ func TestCheckErrors(t *testing.T) {
 			}
 
 			lastCheck := moira.CheckData{
-				Metrics:        triggerChecker.lastCheck.Metrics,
+				Metrics:        lastCheckMetrics,
 				State:          EXCEPTION,
 				Timestamp:      triggerChecker.Until,
 				EventTimestamp: triggerChecker.Until,
+				StateTimestamp: triggerChecker.Until,
 				Score:          100000,
 				Message:        messageException,
 			}
@@ -492,7 +541,23 @@ func TestCheckErrors(t *testing.T) {
 			dataBase.EXPECT().GetMetricRetention(metric).Return(retention, nil)
 			dataBase.EXPECT().GetMetricsValues([]string{metric}, triggerChecker.From, triggerChecker.Until).Return(nil, unknownFunctionExc)
 			dataBase.EXPECT().PushNotificationEvent(&event, true).Return(nil)
-			dataBase.EXPECT().SetTriggerLastCheck(triggerChecker.TriggerID, &lastCheck, triggerChecker.trigger.IsRemote).Return(nil)
+			dataBase.EXPECT().PublishStreamEvent(&moira.StreamEvent{
+				Type:      moira.NotificationStreamEvent,
+				TriggerID: triggerChecker.TriggerID,
+				Timestamp: 67,
+				State:     EXCEPTION,
+				OldState:  OK,
+				Event:     &event,
+			}).Return(nil)
+			dataBase.EXPECT().SetTriggerLastCheck(triggerChecker.TriggerID, &lastCheck, triggerChecker.trigger.GetRemoteSource()).Return(nil)
+			dataBase.EXPECT().PublishStreamEvent(&moira.StreamEvent{
+				Type:      moira.StateChangeStreamEvent,
+				TriggerID: triggerChecker.TriggerID,
+				Timestamp: 67,
+				State:     EXCEPTION,
+				OldState:  OK,
+			}).Return(nil)
+			dataBase.EXPECT().GetCompositeTriggerIDs(triggerChecker.TriggerID).Return(nil, nil)
 			err := triggerChecker.Check()
 			So(err, ShouldBeNil)
 		})
@@ -506,6 +571,7 @@ func TestCheckErrors(t *testing.T) {
 			eventMetrics := map[string]moira.MetricState{
 				metric: {
 					EventTimestamp: 17,
+					StateTimestamp: 17,
 					State:          "OK",
 					Suppressed:     false,
 					Timestamp:      57,
@@ -528,6 +594,7 @@ func TestCheckErrors(t *testing.T) {
 				State:          OK,
 				Timestamp:      triggerChecker.Until,
 				EventTimestamp: triggerChecker.Until,
+				StateTimestamp: triggerChecker.Until,
 				Score:          0,
 			}
 
@@ -536,13 +603,71 @@ func TestCheckErrors(t *testing.T) {
 			dataBase.EXPECT().GetMetricRetention(metric).Return(retention, nil)
 			dataBase.EXPECT().GetMetricsValues([]string{metric}, triggerChecker.From, triggerChecker.Until).Return(dataList, nil)
 			dataBase.EXPECT().PushNotificationEvent(&event, true).Return(nil)
-			dataBase.EXPECT().SetTriggerLastCheck(triggerChecker.TriggerID, &lastCheck, triggerChecker.trigger.IsRemote).Return(nil)
+			dataBase.EXPECT().PublishStreamEvent(gomock.Any()).Return(nil).Times(2)
+			dataBase.EXPECT().SetTriggerLastCheck(triggerChecker.TriggerID, &lastCheck, triggerChecker.trigger.GetRemoteSource()).Return(nil)
+			dataBase.EXPECT().GetCompositeTriggerIDs(triggerChecker.TriggerID).Return(nil, nil)
 			err := triggerChecker.Check()
 			So(err, ShouldBeNil)
 		})
 	})
 }
 
+func TestPublishMetricsStateChanges(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	logger, _ := logging.GetLogger("Test")
+	defer mockCtrl.Finish()
+
+	triggerChecker := TriggerChecker{
+		TriggerID: "SuperId",
+		Database:  dataBase,
+		Logger:    logger,
+		trigger:   &moira.Trigger{Tags: []string{"db"}},
+		lastCheck: &moira.CheckData{
+			Metrics: map[string]moira.MetricState{
+				"metric.ok":      {State: OK},
+				"metric.changed": {State: OK},
+				"metric.deleted": {State: ERROR},
+			},
+		},
+	}
+
+	Convey("Changed and new metrics states should be published", t, func() {
+		checkData := moira.CheckData{
+			Timestamp: 100,
+			Metrics: map[string]moira.MetricState{
+				"metric.ok":      {State: OK},
+				"metric.changed": {State: ERROR, Suppressed: true},
+				"metric.new":     {State: NODATA},
+			},
+		}
+		gomock.InOrder(
+			dataBase.EXPECT().PublishStreamEvent(&moira.StreamEvent{
+				Type:      moira.MetricStateChangeStreamEvent,
+				TriggerID: "SuperId",
+				Tags:      []string{"db"},
+				Metric:    "metric.changed",
+				Timestamp: 100,
+				State:     ERROR,
+				OldState:  OK,
+			}).Return(nil),
+			dataBase.EXPECT().PublishStreamEvent(&moira.StreamEvent{
+				Type:      moira.MetricStateChangeStreamEvent,
+				TriggerID: "SuperId",
+				Tags:      []string{"db"},
+				Metric:    "metric.new",
+				Timestamp: 100,
+				State:     NODATA,
+			}).Return(fmt.Errorf("Oooops! Can not publish")),
+		)
+		triggerChecker.publishMetricsStateChanges(checkData)
+	})
+
+	Convey("Nothing should be published if metrics states are the same", t, func() {
+		triggerChecker.publishMetricsStateChanges(*triggerChecker.lastCheck)
+	})
+}
+
 func TestHandleTrigger(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
@@ -627,6 +752,7 @@ func TestHandleTrigger(t *testing.T) {
 			Metric:    metric,
 			Value:     &val,
 			Message:   nil}, true).Return(nil)
+		dataBase.EXPECT().PublishStreamEvent(gomock.Any()).Return(nil)
 		checkData, err := triggerChecker.handleMetricsCheck()
 		So(err, ShouldBeNil)
 		So(checkData, ShouldResemble, moira.CheckData{
@@ -699,6 +825,7 @@ func TestHandleTrigger(t *testing.T) {
 			Metric:    metric,
 			Value:     nil,
 			Message:   nil}, true).Return(nil)
+		dataBase.EXPECT().PublishStreamEvent(gomock.Any()).Return(nil)
 		checkData, err := triggerChecker.handleMetricsCheck()
 		So(err, ShouldBeNil)
 		So(checkData, ShouldResemble, moira.CheckData{
@@ -770,6 +897,7 @@ func TestHandleTrigger(t *testing.T) {
 		dataBase.EXPECT().GetMetricsValues([]string{metric1, metric2}, triggerChecker1.From, triggerChecker1.Until).Return(map[string][]*moira.MetricValue{metric1: metricValues, metric2: metricValues}, nil)
 		dataBase.EXPECT().RemoveMetricsValues([]string{metric1, metric2}, gomock.Any())
 		dataBase.EXPECT().PushNotificationEvent(gomock.Any(), true).Return(nil)
+		dataBase.EXPECT().PublishStreamEvent(gomock.Any()).Return(nil)
 		checkData, err := triggerChecker1.handleMetricsCheck()
 		So(err, ShouldResemble, ErrTriggerHasSameTimeSeriesNames{names: []string{"super"}})
 		So(checkData, ShouldResemble, moira.CheckData{
@@ -859,7 +987,7 @@ func TestHandleErrorCheck(t *testing.T) {
 					State:     NODATA,
 				},
 			}
-			err1 := "This metric has been in bad state for more than 24 hours - please, fix."
+			err1 := "This metric has been in bad state for more than 24 hours - please, fix. Reminder 1."
 			checkData := moira.CheckData{
 				State:     OK,
 				Timestamp: time.Now().Unix(),
@@ -874,11 +1002,13 @@ func TestHandleErrorCheck(t *testing.T) {
 			}
 
 			dataBase.EXPECT().PushNotificationEvent(event, true).Return(nil)
+			dataBase.EXPECT().PublishStreamEvent(gomock.Any()).Return(nil)
 			actual, err := triggerChecker.handleTriggerCheck(checkData, ErrTriggerHasNoTimeSeries{})
 			expected := moira.CheckData{
 				State:          NODATA,
 				Timestamp:      checkData.Timestamp,
 				EventTimestamp: checkData.Timestamp,
+				RemindersCount: 1,
 				Message:        "Trigger has no metrics, check your target",
 			}
 			So(err, ShouldBeNil)
@@ -906,6 +1036,7 @@ func TestHandleErrorCheck(t *testing.T) {
 		}
 
 		dataBase.EXPECT().PushNotificationEvent(gomock.Any(), true).Return(nil)
+		dataBase.EXPECT().PublishStreamEvent(gomock.Any()).Return(nil)
 		actual, err := triggerChecker.handleTriggerCheck(checkData, ErrTriggerHasOnlyWildcards{})
 		expected := moira.CheckData{
 			State:          ERROR,
@@ -1033,6 +1164,7 @@ func TestHandleErrorCheck(t *testing.T) {
 		}
 
 		dataBase.EXPECT().PushNotificationEvent(gomock.Any(), true).Return(nil)
+		dataBase.EXPECT().PublishStreamEvent(gomock.Any()).Return(nil)
 
 		actual, err := triggerChecker.handleTriggerCheck(checkData, target.ErrUnknownFunction{FuncName: "123"})
 		expected := moira.CheckData{
@@ -1065,6 +1197,7 @@ func TestHandleErrorCheck(t *testing.T) {
 		}
 
 		dataBase.EXPECT().PushNotificationEvent(gomock.Any(), true).Return(nil)
+		dataBase.EXPECT().PublishStreamEvent(gomock.Any()).Return(nil)
 
 		actual, err := triggerChecker.handleTriggerCheck(checkData, ErrTriggerHasSameTimeSeriesNames{names: []string{"first", "second"}})
 		expected := moira.CheckData{
@@ -1100,6 +1233,7 @@ func TestHandleErrorCheck(t *testing.T) {
 		}
 
 		dataBase.EXPECT().PushNotificationEvent(gomock.Any(), true).Return(nil)
+		dataBase.EXPECT().PublishStreamEvent(gomock.Any()).Return(nil)
 
 		actual, err := triggerChecker.handleTriggerCheck(checkData, ErrWrongTriggerTargets([]int{2}))
 		expected := moira.CheckData{
@@ -0,0 +1,93 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/expression"
+)
+
+// handleCompositeCheck evaluates composite trigger expression over the states of its input triggers
+// Inputs are available in expression as t1, t2, ... in order of trigger inputs
+func (triggerChecker *TriggerChecker) handleCompositeCheck() (moira.CheckData, error) {
+	checkData := moira.CheckData{
+		Metrics:        make(map[string]moira.MetricState),
+		State:          triggerChecker.lastCheck.State,
+		Timestamp:      triggerChecker.Until,
+		EventTimestamp: triggerChecker.lastCheck.EventTimestamp,
+		Score:          triggerChecker.lastCheck.Score,
+	}
+
+	inputsStates, err := triggerChecker.getInputsStates()
+	if err != nil {
+		return checkData, err
+	}
+
+	triggerExpression := expression.TriggerExpression{
+		Expression:    triggerChecker.trigger.Expression,
+		TriggerType:   triggerChecker.trigger.TriggerType,
+		PreviousState: triggerChecker.lastCheck.State,
+		InputsStates:  inputsStates,
+	}
+	state, err := triggerExpression.Evaluate()
+	if err != nil {
+		checkData.State = EXCEPTION
+		checkData.Message = err.Error()
+		return checkData, nil
+	}
+
+	inputs := make([]string, 0, len(inputsStates))
+	for i := range triggerChecker.trigger.Inputs {
+		inputName := fmt.Sprintf("t%v", i+1)
+		inputs = append(inputs, fmt.Sprintf("%s: %s", inputName, inputsStates[inputName]))
+	}
+	checkData.State = state
+	checkData.Message = fmt.Sprintf("Inputs states: %s", strings.Join(inputs, ", "))
+	return checkData, nil
+}
+
+// getInputsStates returns last check states of composite trigger inputs
+// Inputs which were never checked or were removed are considered to be in NODATA state
+func (triggerChecker *TriggerChecker) getInputsStates() (map[string]string, error) {
+	inputsChecks, err := triggerChecker.Database.GetTriggerChecks(triggerChecker.trigger.Inputs)
+	if err != nil {
+		return nil, err
+	}
+	inputsStates := make(map[string]string, len(inputsChecks))
+	for i, inputCheck := range inputsChecks {
+		state := NODATA
+		if inputCheck != nil && inputCheck.LastCheck.State != "" {
+			state = inputCheck.LastCheck.State
+		}
+		inputsStates[fmt.Sprintf("t%v", i+1)] = state
+	}
+	return inputsStates, nil
+}
+
+// scheduleCompositeTriggersCheck adds composite triggers, which use current trigger as input, to triggers to check
+func (triggerChecker *TriggerChecker) scheduleCompositeTriggersCheck() error {
+	triggerIDs, err := triggerChecker.Database.GetCompositeTriggerIDs(triggerChecker.TriggerID)
+	if err != nil {
+		return err
+	}
+	for priority, priorityTriggerIDs := range SplitTriggerIDsByPriority(triggerIDs, triggerChecker.PriorityTriggerIDs) {
+		if err := triggerChecker.Database.AddTriggersToCheck(priorityTriggerIDs, priority); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SplitTriggerIDsByPriority splits trigger IDs to high priority ones, which are in priorityTriggerIDs, and normal priority ones
+func SplitTriggerIDsByPriority(triggerIDs []string, priorityTriggerIDs map[string]bool) map[moira.TriggerCheckPriority][]string {
+	triggerIDsByPriority := make(map[moira.TriggerCheckPriority][]string)
+	for _, triggerID := range triggerIDs {
+		priority := moira.NormalCheckPriority
+		if priorityTriggerIDs[triggerID] {
+			priority = moira.HighCheckPriority
+		}
+		triggerIDsByPriority[priority] = append(triggerIDsByPriority[priority], triggerID)
+	}
+	return triggerIDsByPriority
+}
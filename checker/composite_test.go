@@ -0,0 +1,115 @@
+package checker
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/mock/moira-alert"
+)
+
+func TestHandleCompositeCheck(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	expression := "t1 == ERROR && t2 == ERROR ? ERROR : OK"
+	inputs := []string{"latency", "errors"}
+	triggerChecker := TriggerChecker{
+		TriggerID: "composite",
+		Database:  dataBase,
+		Until:     67,
+		trigger: &moira.Trigger{
+			TriggerType: moira.CompositeTrigger,
+			Expression:  &expression,
+			Inputs:      inputs,
+		},
+		lastCheck: &moira.CheckData{
+			State:          OK,
+			Timestamp:      57,
+			EventTimestamp: 17,
+		},
+	}
+
+	Convey("All inputs are in ERROR state", t, func() {
+		dataBase.EXPECT().GetTriggerChecks(inputs).Return([]*moira.TriggerCheck{
+			{LastCheck: moira.CheckData{State: ERROR}},
+			{LastCheck: moira.CheckData{State: ERROR}},
+		}, nil)
+		checkData, err := triggerChecker.handleCompositeCheck()
+		So(err, ShouldBeNil)
+		So(checkData, ShouldResemble, moira.CheckData{
+			Metrics:        make(map[string]moira.MetricState),
+			State:          ERROR,
+			Timestamp:      67,
+			EventTimestamp: 17,
+			Message:        "Inputs states: t1: ERROR, t2: ERROR",
+		})
+	})
+
+	Convey("One of inputs is not checked yet", t, func() {
+		dataBase.EXPECT().GetTriggerChecks(inputs).Return([]*moira.TriggerCheck{
+			{LastCheck: moira.CheckData{State: ERROR}},
+			nil,
+		}, nil)
+		checkData, err := triggerChecker.handleCompositeCheck()
+		So(err, ShouldBeNil)
+		So(checkData.State, ShouldEqual, OK)
+		So(checkData.Message, ShouldEqual, "Inputs states: t1: ERROR, t2: NODATA")
+	})
+
+	Convey("Invalid expression", t, func() {
+		invalidExpression := "t1 == ERROR && t3 == ERROR ? ERROR : OK"
+		triggerChecker.trigger.Expression = &invalidExpression
+		defer func() { triggerChecker.trigger.Expression = &expression }()
+		dataBase.EXPECT().GetTriggerChecks(inputs).Return([]*moira.TriggerCheck{
+			{LastCheck: moira.CheckData{State: ERROR}},
+			{LastCheck: moira.CheckData{State: ERROR}},
+		}, nil)
+		checkData, err := triggerChecker.handleCompositeCheck()
+		So(err, ShouldBeNil)
+		So(checkData.State, ShouldEqual, EXCEPTION)
+		So(checkData.Message, ShouldEqual, "no value with name t3")
+	})
+
+	Convey("Database error", t, func() {
+		dbErr := fmt.Errorf("connection refused")
+		dataBase.EXPECT().GetTriggerChecks(inputs).Return(nil, dbErr)
+		_, err := triggerChecker.handleCompositeCheck()
+		So(err, ShouldResemble, dbErr)
+	})
+}
+
+func TestScheduleCompositeTriggersCheck(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	triggerChecker := TriggerChecker{
+		TriggerID:          "input",
+		Database:           dataBase,
+		PriorityTriggerIDs: map[string]bool{"critical-composite": true},
+	}
+
+	Convey("Composite triggers are scheduled with priority of their tags", t, func() {
+		dataBase.EXPECT().GetCompositeTriggerIDs("input").Return([]string{"composite", "critical-composite"}, nil)
+		dataBase.EXPECT().AddTriggersToCheck([]string{"composite"}, moira.NormalCheckPriority).Return(nil)
+		dataBase.EXPECT().AddTriggersToCheck([]string{"critical-composite"}, moira.HighCheckPriority).Return(nil)
+		So(triggerChecker.scheduleCompositeTriggersCheck(), ShouldBeNil)
+	})
+
+	Convey("Nothing is scheduled without composite triggers", t, func() {
+		dataBase.EXPECT().GetCompositeTriggerIDs("input").Return(nil, nil)
+		So(triggerChecker.scheduleCompositeTriggersCheck(), ShouldBeNil)
+	})
+
+	Convey("Database error", t, func() {
+		dbErr := fmt.Errorf("connection refused")
+		dataBase.EXPECT().GetCompositeTriggerIDs("input").Return([]string{"composite"}, nil)
+		dataBase.EXPECT().AddTriggersToCheck([]string{"composite"}, moira.NormalCheckPriority).Return(dbErr)
+		So(triggerChecker.scheduleCompositeTriggersCheck(), ShouldResemble, dbErr)
+	})
+}
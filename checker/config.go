@@ -6,13 +6,19 @@ import (
 
 // Config represent checker config
 type Config struct {
-	Enabled                     bool
-	NoDataCheckInterval         time.Duration
-	CheckInterval               time.Duration
-	MetricsTTLSeconds           int64
-	StopCheckingIntervalSeconds int64
-	MaxParallelChecks           int
-	MaxParallelRemoteChecks     int
-	LogFile                     string
-	LogLevel                    string
+	Enabled                      bool
+	NoDataCheckInterval          time.Duration
+	CheckInterval                time.Duration
+	MetricsTTLSeconds            int64
+	StopCheckingIntervalSeconds  int64
+	StateHistoryRetentionSeconds int64
+	MaxParallelChecks            int
+	MaxParallelRemoteChecks      int
+	PriorityTags                 []string
+	ShardingEnabled              bool
+	ShardingInstanceID           string
+	ShardingHeartbeatInterval    time.Duration
+	ShardingHeartbeatTTL         time.Duration
+	LogFile                      string
+	LogLevel                     string
 }
@@ -2,15 +2,15 @@ package checker
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/moira-alert/moira"
 )
 
-var badStateReminder = map[string]int64{
-	ERROR:  86400,
-	NODATA: 86400,
-}
+// Default interval in seconds between reminders about bad states which are not warnings
+const defaultRemindInterval int64 = 86400
 
 func (triggerChecker *TriggerChecker) compareTriggerStates(currentCheck moira.CheckData) (moira.CheckData, error) {
 	currentStateValue := currentCheck.State
@@ -18,6 +18,12 @@ func (triggerChecker *TriggerChecker) compareTriggerStates(currentCheck moira.Ch
 	lastStateSuppressed := triggerChecker.lastCheck.Suppressed
 	lastStateSuppressedValue := triggerChecker.lastCheck.SuppressedState
 	timestamp := currentCheck.Timestamp
+	currentCheck.Acknowledgement = moira.GetAcknowledgement(triggerChecker.lastCheck.Acknowledgement, currentStateValue)
+
+	if triggerChecker.isTriggerMaintenanceEnded(timestamp) {
+		return triggerChecker.endTriggerMaintenance(currentCheck)
+	}
+	currentCheck.Maintenance = triggerChecker.lastCheck.Maintenance
 
 	if triggerChecker.lastCheck.EventTimestamp != 0 {
 		currentCheck.EventTimestamp = triggerChecker.lastCheck.EventTimestamp
@@ -31,7 +37,15 @@ func (triggerChecker *TriggerChecker) compareTriggerStates(currentCheck moira.Ch
 
 	currentCheck.SuppressedState = lastStateSuppressedValue
 
-	needSend, message := needSendEvent(currentStateValue, lastStateValue, timestamp, triggerChecker.lastCheck.GetEventTimestamp(), lastStateSuppressed, lastStateSuppressedValue)
+	needSend, message := needSendEvent(currentStateValue, lastStateValue, lastStateSuppressed, lastStateSuppressedValue)
+	isReminder := false
+	if !needSend {
+		currentCheck.RemindersCount = triggerChecker.lastCheck.RemindersCount
+		if currentCheck.Acknowledgement == nil {
+			needSend, message = triggerChecker.needRemindAgain(currentStateValue, timestamp, triggerChecker.lastCheck.GetEventTimestamp(), currentCheck.RemindersCount)
+			isReminder = needSend
+		}
+	}
 	if !needSend {
 		return currentCheck, nil
 	}
@@ -66,8 +80,11 @@ func (triggerChecker *TriggerChecker) compareTriggerStates(currentCheck moira.Ch
 	}
 
 	currentCheck.SuppressedState = ""
+	if isReminder {
+		currentCheck.RemindersCount++
+	}
 	triggerChecker.Logger.Infof("Writing new event: %v", event)
-	err := triggerChecker.Database.PushNotificationEvent(&event, true)
+	err := triggerChecker.pushNotificationEvent(&event)
 	return currentCheck, err
 }
 
@@ -83,12 +100,30 @@ func (triggerChecker *TriggerChecker) compareMetricStates(metric string, current
 	}
 
 	currentState.SuppressedState = lastState.SuppressedState
+	currentState.Acknowledgement = moira.GetAcknowledgement(lastState.Acknowledgement, currentState.State)
 
-	needSend, message := needSendEvent(currentState.State, lastState.State, currentState.Timestamp, lastState.GetEventTimestamp(), lastState.Suppressed, lastState.SuppressedState)
+	needSend, message := needSendEvent(currentState.State, lastState.State, lastState.Suppressed, lastState.SuppressedState)
+	isReminder := false
+	if !needSend {
+		currentState.RemindersCount = lastState.RemindersCount
+		if currentState.Acknowledgement == nil {
+			needSend, message = triggerChecker.needRemindAgain(currentState.State, currentState.Timestamp, lastState.GetEventTimestamp(), currentState.RemindersCount)
+			isReminder = needSend
+		}
+	}
 	if !needSend {
 		return currentState, nil
 	}
 
+	if triggerChecker.trigger.IsAggregated() || (lastState.Suppressed && triggerChecker.isTriggerMaintenanceEnded(currentState.Timestamp)) {
+		// Aggregated trigger notifies only about trigger state changes, metric states are stored for UI.
+		// States changed during trigger maintenance are reported by the single maintenance ended event
+		currentState.EventTimestamp = currentState.Timestamp
+		currentState.Suppressed = false
+		currentState.SuppressedState = ""
+		return currentState, nil
+	}
+
 	eventOldState := lastState.State
 	if lastState.Suppressed {
 		eventOldState = lastState.SuppressedState
@@ -116,8 +151,11 @@ func (triggerChecker *TriggerChecker) compareMetricStates(metric string, current
 	}
 
 	currentState.SuppressedState = ""
+	if isReminder {
+		currentState.RemindersCount++
+	}
 	triggerChecker.Logger.Infof("Writing new event: %v", event)
-	err := triggerChecker.Database.PushNotificationEvent(&event, true)
+	err := triggerChecker.pushNotificationEvent(&event)
 	return currentState, err
 }
 
@@ -130,10 +168,95 @@ func (triggerChecker *TriggerChecker) isTriggerSuppressed(event *moira.Notificat
 		triggerChecker.Logger.Debugf("Event %v suppressed due to metric %s maintenance until %v.", event, metric, time.Unix(stateMaintenance, 0))
 		return true
 	}
+	if triggerMaintenance := triggerChecker.getTriggerMaintenance(); triggerMaintenance >= timestamp {
+		triggerChecker.Logger.Debugf("Event %v suppressed due to trigger maintenance until %v.", event, time.Unix(triggerMaintenance, 0))
+		return true
+	}
+	window := moira.GetActiveMaintenanceWindow(triggerChecker.MaintenanceWindows, triggerChecker.TriggerID, triggerChecker.trigger.Tags, metric, timestamp)
+	if window != nil {
+		triggerChecker.Logger.Debugf("Event %v suppressed due to maintenance window %s by %s: %s", event, window.ID, window.Author, window.Reason)
+		return true
+	}
 	return false
 }
 
-func needSendEvent(currentStateValue string, lastStateValue string, currentStateTimestamp int64, lastStateEventTimestamp int64, isLastCheckSuppressed bool, lastStateSuppressedValue string) (needSend bool, message *string) {
+// getTriggerMaintenance returns timestamp until which the whole trigger is in maintenance
+func (triggerChecker *TriggerChecker) getTriggerMaintenance() int64 {
+	if triggerChecker.lastCheck == nil {
+		return 0
+	}
+	return triggerChecker.lastCheck.Maintenance
+}
+
+// isTriggerMaintenanceEnded checks if trigger maintenance has been set and is over at timestamp
+func (triggerChecker *TriggerChecker) isTriggerMaintenanceEnded(timestamp int64) bool {
+	maintenance := triggerChecker.getTriggerMaintenance()
+	return maintenance != 0 && maintenance < timestamp
+}
+
+// endTriggerMaintenance resets trigger maintenance and suppressed states of trigger and its metrics,
+// single event with current trigger state is sent instead of events about all states changed during maintenance
+func (triggerChecker *TriggerChecker) endTriggerMaintenance(currentCheck moira.CheckData) (moira.CheckData, error) {
+	timestamp := currentCheck.Timestamp
+	eventOldState := triggerChecker.lastCheck.State
+	if triggerChecker.lastCheck.Suppressed && triggerChecker.lastCheck.SuppressedState != "" {
+		eventOldState = triggerChecker.lastCheck.SuppressedState
+	}
+
+	currentCheck.Maintenance = 0
+	currentCheck.EventTimestamp = timestamp
+	currentCheck.Suppressed = false
+	currentCheck.SuppressedState = ""
+	for metric, metricState := range currentCheck.Metrics {
+		if metricState.Suppressed && metricState.Maintenance < timestamp {
+			metricState.Suppressed = false
+			metricState.SuppressedState = ""
+			currentCheck.Metrics[metric] = metricState
+		}
+	}
+
+	message := fmt.Sprintf("Trigger maintenance ended, current state is %s.", currentCheck.State)
+	if statesCount := currentCheck.GetMetricsStatesCount(); len(statesCount) > 0 {
+		message = fmt.Sprintf("%s Metrics states: %s.", message, formatStatesCount(statesCount))
+	}
+	event := moira.NotificationEvent{
+		IsTriggerEvent: true,
+		TriggerID:      triggerChecker.TriggerID,
+		State:          currentCheck.State,
+		OldState:       eventOldState,
+		Timestamp:      timestamp,
+		Metric:         triggerChecker.trigger.Name,
+		Message:        &message,
+	}
+	if triggerChecker.isTriggerSuppressed(&event, timestamp, 0, "") {
+		return currentCheck, nil
+	}
+	triggerChecker.Logger.Infof("Writing new event: %v", event)
+	err := triggerChecker.pushNotificationEvent(&event)
+	return currentCheck, err
+}
+
+// formatStatesCount formats number of metrics in each state in order of severities
+func formatStatesCount(statesCount map[string]int64) string {
+	states := make([]string, 0, len(statesCount))
+	for state := range statesCount {
+		states = append(states, state)
+	}
+	severityModel := moira.GetSeverityModel()
+	sort.Slice(states, func(i, j int) bool {
+		if compared := severityModel.Compare(states[i], states[j]); compared != 0 {
+			return compared > 0
+		}
+		return states[i] < states[j]
+	})
+	formatted := make([]string, 0, len(states))
+	for _, state := range states {
+		formatted = append(formatted, fmt.Sprintf("%d %s", statesCount[state], state))
+	}
+	return strings.Join(formatted, ", ")
+}
+
+func needSendEvent(currentStateValue string, lastStateValue string, isLastCheckSuppressed bool, lastStateSuppressedValue string) (needSend bool, message *string) {
 	if !isLastCheckSuppressed && currentStateValue != lastStateValue {
 		return true, nil
 	}
@@ -141,14 +264,63 @@ func needSendEvent(currentStateValue string, lastStateValue string, currentState
 		message := "This metric changed its state during maintenance interval."
 		return true, &message
 	}
-	remindInterval, ok := badStateReminder[currentStateValue]
-	if ok && needRemindAgain(currentStateTimestamp, lastStateEventTimestamp, remindInterval) {
-		message := fmt.Sprintf("This metric has been in bad state for more than %v hours - please, fix.", remindInterval/3600)
-		return true, &message
-	}
 	return false, nil
 }
 
-func needRemindAgain(currentStateTimestamp, lastStateEventTimestamp, remindInterval int64) bool {
-	return currentStateTimestamp-lastStateEventTimestamp >= remindInterval
+// needRemindAgain checks if it is time to remind about the same state again and returns reminder message
+func (triggerChecker *TriggerChecker) needRemindAgain(state string, currentStateTimestamp, lastStateEventTimestamp, remindersCount int64) (needRemind bool, message *string) {
+	remindInterval := triggerChecker.getRemindInterval(state)
+	if remindInterval <= 0 || currentStateTimestamp-lastStateEventTimestamp < remindInterval {
+		return false, nil
+	}
+	maxCount := triggerChecker.getMaxRemindersCount()
+	if maxCount > 0 && remindersCount >= maxCount {
+		return false, nil
+	}
+	reminder := fmt.Sprintf("This metric has been in bad state for more than %s - please, fix.", formatRemindInterval(remindInterval))
+	if maxCount > 0 {
+		reminder = fmt.Sprintf("%s Reminder %d of %d.", reminder, remindersCount+1, maxCount)
+	} else {
+		reminder = fmt.Sprintf("%s Reminder %d.", reminder, remindersCount+1)
+	}
+	return true, &reminder
+}
+
+// getRemindInterval returns interval in seconds between reminders about state, 0 is returned if state must not be reminded
+func (triggerChecker *TriggerChecker) getRemindInterval(state string) int64 {
+	reminder := triggerChecker.trigger.Reminder
+	if reminder != nil {
+		if interval, ok := reminder.StateIntervals[state]; ok {
+			return interval
+		}
+	}
+	severityModel := moira.GetSeverityModel()
+	if state == OK || !severityModel.IsSeverity(state) || severityModel.IsWarning(state) {
+		return 0
+	}
+	if reminder != nil && reminder.Interval > 0 {
+		return reminder.Interval
+	}
+	return defaultRemindInterval
+}
+
+func (triggerChecker *TriggerChecker) getMaxRemindersCount() int64 {
+	if triggerChecker.trigger.Reminder == nil {
+		return 0
+	}
+	return triggerChecker.trigger.Reminder.MaxCount
+}
+
+func formatRemindInterval(interval int64) string {
+	value, unit := interval, "second"
+	switch {
+	case interval%3600 == 0:
+		value, unit = interval/3600, "hour"
+	case interval%60 == 0:
+		value, unit = interval/60, "minute"
+	}
+	if value == 1 {
+		return fmt.Sprintf("%d %s", value, unit)
+	}
+	return fmt.Sprintf("%d %ss", value, unit)
 }
@@ -82,7 +82,7 @@ func TestCompareMetricStates(t *testing.T) {
 			currentState.State = NODATA
 			currentState.Timestamp = 1502809200
 
-			message := fmt.Sprintf("This metric has been in bad state for more than 24 hours - please, fix.")
+			message := fmt.Sprintf("This metric has been in bad state for more than 24 hours - please, fix. Reminder 1.")
 			dataBase.EXPECT().PushNotificationEvent(&moira.NotificationEvent{
 				TriggerID: triggerChecker.TriggerID,
 				Timestamp: currentState.Timestamp,
@@ -92,9 +92,11 @@ func TestCompareMetricStates(t *testing.T) {
 				Value:     currentState.Value,
 				Message:   &message,
 			}, true).Return(nil)
+			dataBase.EXPECT().PublishStreamEvent(gomock.Any()).Return(nil)
 			actual, err := triggerChecker.compareMetricStates("m1", currentState, lastState)
 			So(err, ShouldBeNil)
 			currentState.EventTimestamp = currentState.Timestamp
+			currentState.RemindersCount = 1
 			currentState.Suppressed = false
 			So(actual, ShouldResemble, currentState)
 		})
@@ -106,7 +108,7 @@ func TestCompareMetricStates(t *testing.T) {
 			currentState.State = ERROR
 			currentState.Timestamp = 1502809200
 
-			message := fmt.Sprintf("This metric has been in bad state for more than 24 hours - please, fix.")
+			message := fmt.Sprintf("This metric has been in bad state for more than 24 hours - please, fix. Reminder 1.")
 			dataBase.EXPECT().PushNotificationEvent(&moira.NotificationEvent{
 				TriggerID: triggerChecker.TriggerID,
 				Timestamp: currentState.Timestamp,
@@ -116,9 +118,11 @@ func TestCompareMetricStates(t *testing.T) {
 				Value:     currentState.Value,
 				Message:   &message,
 			}, true).Return(nil)
+			dataBase.EXPECT().PublishStreamEvent(gomock.Any()).Return(nil)
 			actual, err := triggerChecker.compareMetricStates("m1", currentState, lastState)
 			So(err, ShouldBeNil)
 			currentState.EventTimestamp = currentState.Timestamp
+			currentState.RemindersCount = 1
 			currentState.Suppressed = false
 			So(actual, ShouldResemble, currentState)
 		})
@@ -382,6 +386,7 @@ func TestCheckMetricStateSuppressedState(t *testing.T) {
 				Value:     currentState.Value,
 				Message:   &message,
 			}, true).Return(nil)
+			dataBase.EXPECT().PublishStreamEvent(gomock.Any()).Return(nil)
 
 			actual, err := triggerChecker.compareMetricStates("super.awesome.metric", currentState, lastState)
 			So(err, ShouldBeNil)
@@ -393,3 +398,342 @@ func TestCheckMetricStateSuppressedState(t *testing.T) {
 	})
 
 }
+
+func TestCompareMetricStatesWithReminder(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	logger, _ := logging.GetLogger("Test")
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	triggerChecker := TriggerChecker{
+		TriggerID: "SuperId",
+		Database:  dataBase,
+		Logger:    logger,
+		trigger: &moira.Trigger{
+			Reminder: &moira.Reminder{
+				Interval:       7200,
+				StateIntervals: map[string]int64{WARN: 1800, NODATA: 0},
+				MaxCount:       2,
+			},
+		},
+	}
+
+	lastStateExample := moira.MetricState{
+		Timestamp:      1502712000,
+		EventTimestamp: 1502708400,
+		RemindersCount: 1,
+	}
+	currentStateExample := moira.MetricState{
+		Timestamp: 1502719200,
+	}
+
+	Convey("Same state values", t, func() {
+		Convey("Status WARN and state remind interval, need to send", func() {
+			lastState := lastStateExample
+			currentState := currentStateExample
+			lastState.State = WARN
+			currentState.State = WARN
+
+			message := "This metric has been in bad state for more than 30 minutes - please, fix. Reminder 2 of 2."
+			dataBase.EXPECT().PushNotificationEvent(&moira.NotificationEvent{
+				TriggerID: triggerChecker.TriggerID,
+				Timestamp: currentState.Timestamp,
+				State:     WARN,
+				OldState:  WARN,
+				Metric:    "m1",
+				Message:   &message,
+			}, true).Return(nil)
+			dataBase.EXPECT().PublishStreamEvent(gomock.Any()).Return(nil)
+			actual, err := triggerChecker.compareMetricStates("m1", currentState, lastState)
+			So(err, ShouldBeNil)
+			currentState.EventTimestamp = currentState.Timestamp
+			currentState.RemindersCount = 2
+			So(actual, ShouldResemble, currentState)
+		})
+
+		Convey("Status ERROR and trigger remind interval, need to send", func() {
+			lastState := lastStateExample
+			currentState := currentStateExample
+			lastState.State = ERROR
+			currentState.State = ERROR
+			lastState.RemindersCount = 0
+
+			message := "This metric has been in bad state for more than 2 hours - please, fix. Reminder 1 of 2."
+			dataBase.EXPECT().PushNotificationEvent(&moira.NotificationEvent{
+				TriggerID: triggerChecker.TriggerID,
+				Timestamp: currentState.Timestamp,
+				State:     ERROR,
+				OldState:  ERROR,
+				Metric:    "m1",
+				Message:   &message,
+			}, true).Return(nil)
+			dataBase.EXPECT().PublishStreamEvent(gomock.Any()).Return(nil)
+			actual, err := triggerChecker.compareMetricStates("m1", currentState, lastState)
+			So(err, ShouldBeNil)
+			currentState.EventTimestamp = currentState.Timestamp
+			currentState.RemindersCount = 1
+			So(actual, ShouldResemble, currentState)
+		})
+
+		Convey("Status ERROR and max reminders count is reached, no need to send", func() {
+			lastState := lastStateExample
+			currentState := currentStateExample
+			lastState.State = ERROR
+			currentState.State = ERROR
+			lastState.RemindersCount = 2
+
+			actual, err := triggerChecker.compareMetricStates("m1", currentState, lastState)
+			So(err, ShouldBeNil)
+			currentState.EventTimestamp = lastState.EventTimestamp
+			currentState.RemindersCount = 2
+			So(actual, ShouldResemble, currentState)
+		})
+
+		Convey("Status NODATA and disabled reminders, no need to send", func() {
+			lastState := lastStateExample
+			currentState := currentStateExample
+			lastState.State = NODATA
+			currentState.State = NODATA
+			currentState.Timestamp = 1502809200
+
+			actual, err := triggerChecker.compareMetricStates("m1", currentState, lastState)
+			So(err, ShouldBeNil)
+			currentState.EventTimestamp = lastState.EventTimestamp
+			currentState.RemindersCount = 1
+			So(actual, ShouldResemble, currentState)
+		})
+	})
+
+	Convey("Different state values reset reminders count", t, func() {
+		lastState := lastStateExample
+		currentState := currentStateExample
+		lastState.State = ERROR
+		currentState.State = WARN
+
+		dataBase.EXPECT().PushNotificationEvent(&moira.NotificationEvent{
+			TriggerID: triggerChecker.TriggerID,
+			Timestamp: currentState.Timestamp,
+			State:     WARN,
+			OldState:  ERROR,
+			Metric:    "m1",
+		}, true).Return(nil)
+		dataBase.EXPECT().PublishStreamEvent(gomock.Any()).Return(nil)
+		actual, err := triggerChecker.compareMetricStates("m1", currentState, lastState)
+		So(err, ShouldBeNil)
+		currentState.EventTimestamp = currentState.Timestamp
+		So(actual, ShouldResemble, currentState)
+	})
+}
+
+func TestCompareMetricStatesWithMaintenanceWindows(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	logger, _ := logging.GetLogger("Test")
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	triggerChecker := TriggerChecker{
+		TriggerID: "SuperId",
+		Database:  dataBase,
+		Logger:    logger,
+		trigger:   &moira.Trigger{Tags: []string{"db"}},
+		MaintenanceWindows: []*moira.MaintenanceWindow{
+			{ID: "window1", Start: 1000, End: 2000, Scope: moira.MaintenanceScope{Tags: []string{"db"}, Metric: "db.*"}},
+		},
+	}
+
+	lastState := moira.MetricState{
+		Timestamp:      900,
+		EventTimestamp: 800,
+		State:          OK,
+	}
+
+	Convey("Event of metric in scope of active window is suppressed", t, func() {
+		currentState := moira.MetricState{Timestamp: 1500, State: ERROR}
+		actual, err := triggerChecker.compareMetricStates("db.host1", currentState, lastState)
+		So(err, ShouldBeNil)
+		currentState.EventTimestamp = currentState.Timestamp
+		currentState.Suppressed = true
+		currentState.SuppressedState = OK
+		So(actual, ShouldResemble, currentState)
+	})
+
+	Convey("Event of metric out of scope is sent", t, func() {
+		currentState := moira.MetricState{Timestamp: 1500, State: ERROR}
+		dataBase.EXPECT().PushNotificationEvent(&moira.NotificationEvent{
+			TriggerID: triggerChecker.TriggerID,
+			Timestamp: currentState.Timestamp,
+			State:     ERROR,
+			OldState:  OK,
+			Metric:    "web.host1",
+		}, true).Return(nil)
+		dataBase.EXPECT().PublishStreamEvent(gomock.Any()).Return(nil)
+		actual, err := triggerChecker.compareMetricStates("web.host1", currentState, lastState)
+		So(err, ShouldBeNil)
+		currentState.EventTimestamp = currentState.Timestamp
+		So(actual, ShouldResemble, currentState)
+	})
+
+	Convey("Event after window end is sent", t, func() {
+		currentState := moira.MetricState{Timestamp: 2000, State: ERROR}
+		dataBase.EXPECT().PushNotificationEvent(&moira.NotificationEvent{
+			TriggerID: triggerChecker.TriggerID,
+			Timestamp: currentState.Timestamp,
+			State:     ERROR,
+			OldState:  OK,
+			Metric:    "db.host1",
+		}, true).Return(nil)
+		dataBase.EXPECT().PublishStreamEvent(gomock.Any()).Return(nil)
+		actual, err := triggerChecker.compareMetricStates("db.host1", currentState, lastState)
+		So(err, ShouldBeNil)
+		currentState.EventTimestamp = currentState.Timestamp
+		So(actual, ShouldResemble, currentState)
+	})
+}
+
+func TestTriggerMaintenance(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	logger, _ := logging.GetLogger("Test")
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	triggerChecker := TriggerChecker{
+		TriggerID: "SuperId",
+		Database:  dataBase,
+		Logger:    logger,
+		trigger:   &moira.Trigger{Name: "Super Trigger"},
+		lastCheck: &moira.CheckData{
+			State:          OK,
+			Timestamp:      900,
+			EventTimestamp: 800,
+			Maintenance:    2000,
+		},
+	}
+
+	Convey("Trigger state event is suppressed during maintenance", t, func() {
+		currentCheck := moira.CheckData{Timestamp: 1500, State: EXCEPTION}
+		actual, err := triggerChecker.compareTriggerStates(currentCheck)
+		So(err, ShouldBeNil)
+		currentCheck.EventTimestamp = currentCheck.Timestamp
+		currentCheck.Suppressed = true
+		currentCheck.SuppressedState = OK
+		currentCheck.Maintenance = 2000
+		So(actual, ShouldResemble, currentCheck)
+	})
+
+	Convey("Event of new metric is suppressed during maintenance", t, func() {
+		currentState := moira.MetricState{Timestamp: 1500, State: ERROR}
+		actual, err := triggerChecker.compareMetricStates("metric.new", currentState, moira.MetricState{})
+		So(err, ShouldBeNil)
+		currentState.EventTimestamp = currentState.Timestamp
+		currentState.Suppressed = true
+		So(actual, ShouldResemble, currentState)
+	})
+
+	Convey("Maintenance ended", t, func() {
+		lastCheck := moira.CheckData{
+			State:           EXCEPTION,
+			Timestamp:       1900,
+			EventTimestamp:  1500,
+			Suppressed:      true,
+			SuppressedState: OK,
+			Maintenance:     2000,
+		}
+		triggerChecker.lastCheck = &lastCheck
+
+		Convey("States of metrics changed during maintenance are not sent", func() {
+			currentState := moira.MetricState{Timestamp: 2100, State: ERROR}
+			lastState := moira.MetricState{Timestamp: 1900, EventTimestamp: 1500, State: ERROR, Suppressed: true, SuppressedState: OK}
+			actual, err := triggerChecker.compareMetricStates("metric.changed", currentState, lastState)
+			So(err, ShouldBeNil)
+			currentState.EventTimestamp = currentState.Timestamp
+			So(actual, ShouldResemble, currentState)
+		})
+
+		Convey("Single event with current state is sent", func() {
+			currentCheck := moira.CheckData{
+				Timestamp: 2100,
+				State:     OK,
+				Metrics: map[string]moira.MetricState{
+					"metric.changed": {Timestamp: 2100, EventTimestamp: 1500, State: ERROR, Suppressed: true, SuppressedState: OK},
+					"metric.ok":      {Timestamp: 2100, EventTimestamp: 800, State: OK},
+					"metric.other":   {Timestamp: 2100, EventTimestamp: 800, State: OK, Suppressed: true, Maintenance: 3000},
+				},
+			}
+			message := "Trigger maintenance ended, current state is OK. Metrics states: 1 ERROR, 2 OK."
+			dataBase.EXPECT().PushNotificationEvent(&moira.NotificationEvent{
+				IsTriggerEvent: true,
+				TriggerID:      triggerChecker.TriggerID,
+				Timestamp:      2100,
+				State:          OK,
+				OldState:       OK,
+				Metric:         "Super Trigger",
+				Message:        &message,
+			}, true).Return(nil)
+			dataBase.EXPECT().PublishStreamEvent(gomock.Any()).Return(nil)
+			actual, err := triggerChecker.compareTriggerStates(currentCheck)
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, moira.CheckData{
+				Timestamp:      2100,
+				EventTimestamp: 2100,
+				State:          OK,
+				Metrics: map[string]moira.MetricState{
+					"metric.changed": {Timestamp: 2100, EventTimestamp: 1500, State: ERROR},
+					"metric.ok":      {Timestamp: 2100, EventTimestamp: 800, State: OK},
+					"metric.other":   {Timestamp: 2100, EventTimestamp: 800, State: OK, Suppressed: true, Maintenance: 3000},
+				},
+			})
+		})
+	})
+}
+
+func TestCompareStatesWithAcknowledgement(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	logger, _ := logging.GetLogger("Test")
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	triggerChecker := TriggerChecker{
+		TriggerID: "SuperId",
+		Database:  dataBase,
+		Logger:    logger,
+		trigger:   &moira.Trigger{},
+	}
+	acknowledgement := &moira.Acknowledgement{User: "user", State: ERROR, Timestamp: 1502710000}
+
+	Convey("Acknowledged metric state is not reminded", t, func() {
+		lastState := moira.MetricState{Timestamp: 1502712000, EventTimestamp: 1502000000, State: ERROR, Acknowledgement: acknowledgement}
+		currentState := moira.MetricState{Timestamp: 1502719200, State: ERROR}
+		actual, err := triggerChecker.compareMetricStates("metric", currentState, lastState)
+		So(err, ShouldBeNil)
+		currentState.EventTimestamp = lastState.EventTimestamp
+		currentState.Acknowledgement = acknowledgement
+		So(actual, ShouldResemble, currentState)
+	})
+
+	Convey("Acknowledgement is reset when metric state changes", t, func() {
+		lastState := moira.MetricState{Timestamp: 1502712000, EventTimestamp: 1502000000, State: ERROR, Acknowledgement: acknowledgement}
+		currentState := moira.MetricState{Timestamp: 1502719200, State: NODATA}
+		dataBase.EXPECT().PushNotificationEvent(&moira.NotificationEvent{
+			TriggerID: triggerChecker.TriggerID,
+			Timestamp: currentState.Timestamp,
+			State:     NODATA,
+			OldState:  ERROR,
+			Metric:    "metric",
+		}, true).Return(nil)
+		dataBase.EXPECT().PublishStreamEvent(gomock.Any()).Return(nil)
+		actual, err := triggerChecker.compareMetricStates("metric", currentState, lastState)
+		So(err, ShouldBeNil)
+		currentState.EventTimestamp = currentState.Timestamp
+		So(actual, ShouldResemble, currentState)
+	})
+
+	Convey("Acknowledged trigger state is not reminded", t, func() {
+		triggerChecker.lastCheck = &moira.CheckData{Timestamp: 1502712000, EventTimestamp: 1502000000, State: ERROR, Acknowledgement: acknowledgement}
+		currentCheck := moira.CheckData{Timestamp: 1502719200, State: ERROR}
+		actual, err := triggerChecker.compareTriggerStates(currentCheck)
+		So(err, ShouldBeNil)
+		currentCheck.EventTimestamp = triggerChecker.lastCheck.EventTimestamp
+		currentCheck.Acknowledgement = acknowledgement
+		So(actual, ShouldResemble, currentCheck)
+	})
+}
@@ -0,0 +1,159 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/target"
+)
+
+// handleSLOCheck fetches good and total events timeseries for the longest burn rate window
+// and calculates trigger state from error budget burn rates
+func (triggerChecker *TriggerChecker) handleSLOCheck() (moira.CheckData, error) {
+	checkData := moira.CheckData{
+		Metrics:        make(map[string]moira.MetricState),
+		State:          triggerChecker.lastCheck.State,
+		Timestamp:      triggerChecker.Until,
+		EventTimestamp: triggerChecker.lastCheck.EventTimestamp,
+		Score:          triggerChecker.lastCheck.Score,
+
+		SLOBudgetConsumption: triggerChecker.lastCheck.SLOBudgetConsumption,
+	}
+
+	slo := triggerChecker.trigger.SLO
+	from := triggerChecker.Until - slo.GetLongestWindow()
+
+	var triggerTimeSeries *triggerTimeSeries
+	var err error
+	if triggerChecker.trigger.IsRemote {
+		triggerTimeSeries, err = triggerChecker.getRemoteTimeSeries(from, triggerChecker.Until)
+		if err != nil {
+			return checkData, err
+		}
+	} else {
+		var metrics []string
+		triggerTimeSeries, metrics, err = triggerChecker.getTimeSeries(from, triggerChecker.Until)
+		if err != nil {
+			return checkData, err
+		}
+		triggerChecker.cleanupMetricsValues(metrics, triggerChecker.Until)
+	}
+
+	if len(triggerTimeSeries.Main) == 0 || len(triggerTimeSeries.Additional) == 0 || triggerTimeSeries.Additional[0] == nil {
+		return checkData, ErrTriggerHasNoTimeSeries{}
+	}
+	if len(triggerTimeSeries.Main) > 1 {
+		return checkData, ErrWrongTriggerTargets([]int{1})
+	}
+
+	good, total := triggerTimeSeries.Main[0], triggerTimeSeries.Additional[0]
+	checkData.State, checkData.Message = getSLOState(slo, good, total, triggerChecker.Until)
+
+	// Budget consumed since the last check is added, data before the longest window is not fetched
+	consumedFrom := triggerChecker.lastCheck.Timestamp
+	if consumedFrom < from {
+		consumedFrom = from
+	}
+	checkData.SLOBudgetConsumption = updateSLOBudgetConsumption(checkData.SLOBudgetConsumption, slo, good, total, consumedFrom, triggerChecker.Until)
+	checkData.Message = fmt.Sprintf("%s, %s", checkData.Message, formatRemainingErrorBudget(slo, checkData.SLOBudgetConsumption))
+	return checkData, nil
+}
+
+// getSLOState returns the most severe state of burn rate windows, in which both long and short window burn rates reach window burn rate,
+// and message with burn rates
+func getSLOState(slo *moira.SLO, good, total *target.TimeSeries, until int64) (string, string) {
+	errorBudget := 1 - slo.Objective/100
+	windows := slo.GetWindows()
+
+	state := OK
+	message := ""
+	for _, window := range windows {
+		longBurnRate := getErrorRatio(good, total, until-window.Long, until) / errorBudget
+		shortBurnRate := getErrorRatio(good, total, until-window.Short, until) / errorBudget
+		if longBurnRate < window.BurnRate || shortBurnRate < window.BurnRate {
+			continue
+		}
+		if moira.GetSeverityModel().Compare(window.State, state) > 0 {
+			state = window.State
+			message = fmt.Sprintf("Burn rate %.2f over %s and %.2f over %s reached %.2f",
+				longBurnRate, formatWindow(window.Long), shortBurnRate, formatWindow(window.Short), window.BurnRate)
+		}
+	}
+	if state == OK {
+		longest := slo.GetLongestWindow()
+		message = fmt.Sprintf("Burn rate %.2f over %s", getErrorRatio(good, total, until-longest, until)/errorBudget, formatWindow(longest))
+	}
+	return state, message
+}
+
+// sloBudgetParts is the number of SLO period parts, error budget consumption is stored for every part
+// to calculate remaining budget over sliding SLO period without fetching data for the whole period
+const sloBudgetParts = 30
+
+// updateSLOBudgetConsumption adds share of error budget consumed in (from, until] interval to consumption of SLO period part
+// containing until, parts which are out of SLO period are removed
+func updateSLOBudgetConsumption(consumption map[int64]float64, slo *moira.SLO, good, total *target.TimeSeries, from, until int64) map[int64]float64 {
+	period := slo.GetPeriod()
+	partLength := period / sloBudgetParts
+	if partLength < 1 {
+		partLength = 1
+	}
+	updated := make(map[int64]float64, len(consumption)+1)
+	for partStart, consumed := range consumption {
+		if partStart+partLength > until-period {
+			updated[partStart] = consumed
+		}
+	}
+	if until > from {
+		burnRate := getErrorRatio(good, total, from, until) / (1 - slo.Objective/100)
+		updated[until-until%partLength] += burnRate * float64(until-from) / float64(period)
+	}
+	return updated
+}
+
+// formatRemainingErrorBudget returns share of SLO period error budget which is not consumed yet.
+// Consumption is known only since trigger has been checked, so it is underestimated during the first period of new trigger
+func formatRemainingErrorBudget(slo *moira.SLO, consumption map[int64]float64) string {
+	remaining := 100.0
+	for _, consumed := range consumption {
+		remaining -= 100 * consumed
+	}
+	return fmt.Sprintf("%.1f%% of %s error budget remaining", remaining, formatWindow(slo.GetPeriod()))
+}
+
+// getErrorRatio returns share of not good events in (from, until] interval
+func getErrorRatio(good, total *target.TimeSeries, from, until int64) float64 {
+	var goodCount, totalCount float64
+	if total.StepTime <= 0 {
+		return 0
+	}
+	for valueTimestamp := total.StartTime; valueTimestamp <= until; valueTimestamp += total.StepTime {
+		if valueTimestamp <= from {
+			continue
+		}
+		totalValue := total.GetTimestampValue(valueTimestamp)
+		goodValue := good.GetTimestampValue(valueTimestamp)
+		if IsInvalidValue(totalValue) || IsInvalidValue(goodValue) {
+			continue
+		}
+		totalCount += totalValue
+		goodCount += goodValue
+	}
+	if totalCount <= 0 {
+		return 0
+	}
+	return 1 - goodCount/totalCount
+}
+
+func formatWindow(seconds int64) string {
+	switch {
+	case seconds%86400 == 0:
+		return fmt.Sprintf("%dd", seconds/86400)
+	case seconds%3600 == 0:
+		return fmt.Sprintf("%dh", seconds/3600)
+	case seconds%60 == 0:
+		return fmt.Sprintf("%dm", seconds/60)
+	default:
+		return fmt.Sprintf("%ds", seconds)
+	}
+}
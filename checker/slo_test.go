@@ -0,0 +1,107 @@
+package checker
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-graphite/carbonapi/expr/types"
+	pb "github.com/go-graphite/protocol/carbonapi_v3_pb"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/target"
+)
+
+func TestGetSLOState(t *testing.T) {
+	var until int64 = 3600
+	slo := &moira.SLO{
+		Objective: 99,
+		Period:    36000,
+		Windows: []moira.BurnRateWindow{
+			{Long: 3600, Short: 600, BurnRate: 10, State: ERROR},
+			{Long: 3600, Short: 1200, BurnRate: 2, State: WARN},
+		},
+	}
+
+	// Every point is 600 seconds with 100 events, last point has timestamp 3600
+	getTimeSeries := func(values ...float64) *target.TimeSeries {
+		return &target.TimeSeries{MetricData: types.MetricData{FetchResponse: pb.FetchResponse{
+			StartTime: until - int64(len(values)-1)*600,
+			StopTime:  until,
+			StepTime:  600,
+			Values:    values,
+		}}}
+	}
+	total := getTimeSeries(100, 100, 100, 100, 100, 100)
+
+	Convey("No errors", t, func() {
+		good := getTimeSeries(100, 100, 100, 100, 100, 100)
+		state, message := getSLOState(slo, good, total, until)
+		So(state, ShouldEqual, OK)
+		So(message, ShouldEqual, "Burn rate 0.00 over 1h")
+	})
+
+	Convey("Short window burn rate is high, but long one is not", t, func() {
+		good := getTimeSeries(100, 100, 100, 100, 100, 80)
+		state, message := getSLOState(slo, good, total, until)
+		So(state, ShouldEqual, WARN)
+		So(message, ShouldEqual, "Burn rate 3.33 over 1h and 10.00 over 20m reached 2.00")
+	})
+
+	Convey("Both windows burn rates are high", t, func() {
+		good := getTimeSeries(80, 80, 80, 80, 80, 80)
+		state, message := getSLOState(slo, good, total, until)
+		So(state, ShouldEqual, ERROR)
+		So(message, ShouldEqual, "Burn rate 20.00 over 1h and 20.00 over 10m reached 10.00")
+	})
+
+	Convey("Empty values are skipped", t, func() {
+		good := getTimeSeries(math.NaN(), 100, 100, 100, 100, 100)
+		state, _ := getSLOState(slo, good, getTimeSeries(100, math.NaN(), 100, 100, 100, 100), until)
+		So(state, ShouldEqual, OK)
+	})
+
+	Convey("Default windows and period", t, func() {
+		slo := &moira.SLO{Objective: 99.9}
+		So(slo.GetPeriod(), ShouldEqual, int64(30*24*60*60))
+		So(slo.GetWindows(), ShouldHaveLength, 2)
+		good := getTimeSeries(100, 100, 100, 100, 100, 100)
+		state, message := getSLOState(slo, good, total, until)
+		So(state, ShouldEqual, OK)
+		So(message, ShouldEqual, "Burn rate 0.00 over 1h")
+	})
+}
+
+func TestUpdateSLOBudgetConsumption(t *testing.T) {
+	slo := &moira.SLO{Objective: 99, Period: 36000}
+
+	// Every point is 600 seconds with 100 events, 20 of them are errors
+	getTimeSeries := func(values ...float64) *target.TimeSeries {
+		return &target.TimeSeries{MetricData: types.MetricData{FetchResponse: pb.FetchResponse{
+			StartTime: 600,
+			StopTime:  600 * int64(len(values)),
+			StepTime:  600,
+			Values:    values,
+		}}}
+	}
+	good := getTimeSeries(80, 80, 80, 80, 80, 80)
+	total := getTimeSeries(100, 100, 100, 100, 100, 100)
+
+	Convey("Consumption since last check is added to period part", t, func() {
+		consumption := updateSLOBudgetConsumption(nil, slo, good, total, 2400, 3600)
+		So(consumption, ShouldHaveLength, 1)
+		So(consumption[3600], ShouldAlmostEqual, 20*1200/36000.0)
+		So(formatRemainingErrorBudget(slo, consumption), ShouldEqual, "33.3% of 10h error budget remaining")
+	})
+
+	Convey("Nothing is consumed if trigger has been checked at until", t, func() {
+		consumption := updateSLOBudgetConsumption(map[int64]float64{2400: 0.5}, slo, good, total, 3600, 3600)
+		So(consumption, ShouldResemble, map[int64]float64{2400: 0.5})
+		So(formatRemainingErrorBudget(slo, consumption), ShouldEqual, "50.0% of 10h error budget remaining")
+	})
+
+	Convey("Parts out of period are removed", t, func() {
+		consumption := updateSLOBudgetConsumption(map[int64]float64{-33600: 0.5, -32400: 0.1}, slo, good, total, 3600, 3600)
+		So(consumption, ShouldResemble, map[int64]float64{-32400: 0.1})
+	})
+}
@@ -0,0 +1,58 @@
+package checker
+
+import (
+	"github.com/moira-alert/moira"
+)
+
+// updateStateHistory sets time when trigger and its metrics have switched to their current states
+// and collects closed intervals of previous states to be saved to state history
+func (triggerChecker *TriggerChecker) updateStateHistory(checkData *moira.CheckData) {
+	lastCheck := triggerChecker.lastCheck
+	checkData.StateTimestamp = triggerChecker.switchState("", lastCheck.State, lastCheck.GetStateTimestamp(), checkData.State, checkData.Timestamp)
+	for metric, metricState := range checkData.Metrics {
+		lastMetricState, ok := lastCheck.Metrics[metric]
+		if !ok {
+			metricState.StateTimestamp = metricState.GetEventTimestamp()
+		} else {
+			// Metric state is evaluated for every point, so new state started at the time of the last event
+			changedAt := metricState.GetEventTimestamp()
+			if changedAt <= lastMetricState.GetStateTimestamp() {
+				changedAt = metricState.Timestamp
+			}
+			metricState.StateTimestamp = triggerChecker.switchState(metric, lastMetricState.State, lastMetricState.GetStateTimestamp(), metricState.State, changedAt)
+		}
+		checkData.Metrics[metric] = metricState
+	}
+}
+
+// switchState returns time when current state has started. Closed interval of the last state is collected
+// if the state has changed and it is known when the last state has started
+func (triggerChecker *TriggerChecker) switchState(metric string, lastState string, lastStateTimestamp int64, state string, changedAt int64) int64 {
+	if lastStateTimestamp == 0 {
+		return changedAt
+	}
+	if state == lastState {
+		return lastStateTimestamp
+	}
+	if lastStateTimestamp < changedAt {
+		triggerChecker.stateIntervals = append(triggerChecker.stateIntervals, &moira.StateInterval{
+			Metric: metric,
+			State:  lastState,
+			Start:  lastStateTimestamp,
+			End:    changedAt,
+		})
+	}
+	return changedAt
+}
+
+// saveStateHistory saves collected state intervals, errors are only logged because history is not required to check trigger
+func (triggerChecker *TriggerChecker) saveStateHistory() {
+	retention := triggerChecker.Config.StateHistoryRetentionSeconds
+	if retention == 0 || len(triggerChecker.stateIntervals) == 0 {
+		return
+	}
+	if err := triggerChecker.Database.AddTriggerStateHistory(triggerChecker.TriggerID, triggerChecker.stateIntervals, retention); err != nil {
+		triggerChecker.Logger.Errorf("Failed to save state history of trigger %s: %s", triggerChecker.TriggerID, err.Error())
+	}
+	triggerChecker.stateIntervals = nil
+}
@@ -0,0 +1,150 @@
+package checker
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/mock/moira-alert"
+	"github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUpdateStateHistory(t *testing.T) {
+	logger, _ := logging.GetLogger("Test")
+
+	Convey("Trigger state has not changed", t, func() {
+		triggerChecker := TriggerChecker{
+			Logger:    logger,
+			lastCheck: &moira.CheckData{State: OK, Timestamp: 100, EventTimestamp: 20, StateTimestamp: 10},
+		}
+		checkData := moira.CheckData{State: OK, Timestamp: 160, EventTimestamp: 20}
+		triggerChecker.updateStateHistory(&checkData)
+		So(checkData.StateTimestamp, ShouldEqual, 10)
+		So(triggerChecker.stateIntervals, ShouldBeEmpty)
+	})
+
+	Convey("Trigger state has changed", t, func() {
+		triggerChecker := TriggerChecker{
+			Logger:    logger,
+			lastCheck: &moira.CheckData{State: OK, Timestamp: 100, EventTimestamp: 20, StateTimestamp: 10},
+		}
+		checkData := moira.CheckData{State: ERROR, Timestamp: 160, EventTimestamp: 160}
+		triggerChecker.updateStateHistory(&checkData)
+		So(checkData.StateTimestamp, ShouldEqual, 160)
+		So(triggerChecker.stateIntervals, ShouldResemble, []*moira.StateInterval{
+			{State: OK, Start: 10, End: 160},
+		})
+	})
+
+	Convey("Trigger state start is unknown", t, func() {
+		triggerChecker := TriggerChecker{
+			Logger:    logger,
+			lastCheck: &moira.CheckData{State: NODATA, Timestamp: 100},
+		}
+		checkData := moira.CheckData{State: OK, Timestamp: 160}
+		triggerChecker.updateStateHistory(&checkData)
+		So(checkData.StateTimestamp, ShouldEqual, 160)
+		So(triggerChecker.stateIntervals, ShouldBeEmpty)
+	})
+
+	Convey("Trigger state saved before state history starts at last event", t, func() {
+		triggerChecker := TriggerChecker{
+			Logger:    logger,
+			lastCheck: &moira.CheckData{State: WARN, Timestamp: 100, EventTimestamp: 40},
+		}
+		checkData := moira.CheckData{State: OK, Timestamp: 160}
+		triggerChecker.updateStateHistory(&checkData)
+		So(triggerChecker.stateIntervals, ShouldResemble, []*moira.StateInterval{
+			{State: WARN, Start: 40, End: 160},
+		})
+	})
+
+	Convey("Metrics states", t, func() {
+		triggerChecker := TriggerChecker{
+			Logger: logger,
+			lastCheck: &moira.CheckData{
+				State:          OK,
+				Timestamp:      100,
+				StateTimestamp: 10,
+				Metrics: map[string]moira.MetricState{
+					"same":    {State: OK, Timestamp: 90, EventTimestamp: 30, StateTimestamp: 30},
+					"changed": {State: OK, Timestamp: 90, EventTimestamp: 30, StateTimestamp: 30},
+					"removed": {State: OK, Timestamp: 90, EventTimestamp: 30, StateTimestamp: 30},
+				},
+			},
+		}
+		checkData := moira.CheckData{
+			State:     OK,
+			Timestamp: 160,
+			Metrics: map[string]moira.MetricState{
+				"same":    {State: OK, Timestamp: 150, EventTimestamp: 30},
+				"changed": {State: ERROR, Timestamp: 150, EventTimestamp: 120},
+				"new":     {State: WARN, Timestamp: 150, EventTimestamp: 140},
+			},
+		}
+		triggerChecker.updateStateHistory(&checkData)
+		So(checkData.StateTimestamp, ShouldEqual, 10)
+		So(checkData.Metrics["same"].StateTimestamp, ShouldEqual, 30)
+		So(checkData.Metrics["changed"].StateTimestamp, ShouldEqual, 120)
+		So(checkData.Metrics["new"].StateTimestamp, ShouldEqual, 140)
+		So(triggerChecker.stateIntervals, ShouldResemble, []*moira.StateInterval{
+			{Metric: "changed", State: OK, Start: 30, End: 120},
+		})
+	})
+}
+
+func TestSaveStateHistory(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	logger, _ := logging.GetLogger("Test")
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	intervals := []*moira.StateInterval{{State: OK, Start: 10, End: 160}}
+
+	Convey("History is disabled", t, func() {
+		triggerChecker := TriggerChecker{
+			TriggerID:      "SuperId",
+			Database:       dataBase,
+			Logger:         logger,
+			Config:         &Config{},
+			stateIntervals: intervals,
+		}
+		triggerChecker.saveStateHistory()
+	})
+
+	Convey("Nothing to save", t, func() {
+		triggerChecker := TriggerChecker{
+			TriggerID: "SuperId",
+			Database:  dataBase,
+			Logger:    logger,
+			Config:    &Config{StateHistoryRetentionSeconds: 3600},
+		}
+		triggerChecker.saveStateHistory()
+	})
+
+	Convey("Intervals are saved", t, func() {
+		triggerChecker := TriggerChecker{
+			TriggerID:      "SuperId",
+			Database:       dataBase,
+			Logger:         logger,
+			Config:         &Config{StateHistoryRetentionSeconds: 3600},
+			stateIntervals: intervals,
+		}
+		dataBase.EXPECT().AddTriggerStateHistory("SuperId", intervals, int64(3600)).Return(nil)
+		triggerChecker.saveStateHistory()
+		So(triggerChecker.stateIntervals, ShouldBeEmpty)
+	})
+
+	Convey("Save error is only logged", t, func() {
+		triggerChecker := TriggerChecker{
+			TriggerID:      "SuperId",
+			Database:       dataBase,
+			Logger:         logger,
+			Config:         &Config{StateHistoryRetentionSeconds: 3600},
+			stateIntervals: intervals,
+		}
+		dataBase.EXPECT().AddTriggerStateHistory("SuperId", intervals, int64(3600)).Return(fmt.Errorf("redis is down"))
+		triggerChecker.saveStateHistory()
+	})
+}
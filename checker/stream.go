@@ -0,0 +1,67 @@
+package checker
+
+import (
+	"sort"
+
+	"github.com/moira-alert/moira"
+)
+
+// pushNotificationEvent saves notification event and publishes it to real-time subscribers
+func (triggerChecker *TriggerChecker) pushNotificationEvent(event *moira.NotificationEvent) error {
+	if err := triggerChecker.Database.PushNotificationEvent(event, true); err != nil {
+		return err
+	}
+	triggerChecker.publishStreamEvent(&moira.StreamEvent{
+		Type:      moira.NotificationStreamEvent,
+		TriggerID: triggerChecker.TriggerID,
+		Tags:      triggerChecker.trigger.Tags,
+		Timestamp: event.Timestamp,
+		State:     event.State,
+		OldState:  event.OldState,
+		Event:     event,
+	})
+	return nil
+}
+
+// publishStateChange publishes new trigger state to real-time subscribers
+func (triggerChecker *TriggerChecker) publishStateChange(checkData moira.CheckData) {
+	triggerChecker.publishStreamEvent(&moira.StreamEvent{
+		Type:      moira.StateChangeStreamEvent,
+		TriggerID: triggerChecker.TriggerID,
+		Tags:      triggerChecker.trigger.Tags,
+		Timestamp: checkData.Timestamp,
+		State:     checkData.State,
+		OldState:  triggerChecker.lastCheck.State,
+	})
+}
+
+// publishMetricsStateChanges publishes new states of trigger metrics to real-time subscribers. Metric state changes are
+// published even if they don't create notification events, e.g. while events are suppressed by maintenance
+func (triggerChecker *TriggerChecker) publishMetricsStateChanges(checkData moira.CheckData) {
+	changedMetrics := make([]string, 0)
+	for metric, metricState := range checkData.Metrics {
+		if lastMetricState, ok := triggerChecker.lastCheck.Metrics[metric]; !ok || lastMetricState.State != metricState.State {
+			changedMetrics = append(changedMetrics, metric)
+		}
+	}
+	sort.Strings(changedMetrics)
+	for _, metric := range changedMetrics {
+		triggerChecker.publishStreamEvent(&moira.StreamEvent{
+			Type:      moira.MetricStateChangeStreamEvent,
+			TriggerID: triggerChecker.TriggerID,
+			Tags:      triggerChecker.trigger.Tags,
+			Metric:    metric,
+			Timestamp: checkData.Timestamp,
+			State:     checkData.Metrics[metric].State,
+			OldState:  triggerChecker.lastCheck.Metrics[metric].State,
+		})
+	}
+}
+
+// publishStreamEvent only logs publishing errors, because real-time subscribers are not guaranteed to receive all events
+// and trigger check must not fail because of them
+func (triggerChecker *TriggerChecker) publishStreamEvent(event *moira.StreamEvent) {
+	if err := triggerChecker.Database.PublishStreamEvent(event); err != nil {
+		triggerChecker.Logger.Errorf("Failed to publish stream event of trigger %s: %s", triggerChecker.TriggerID, err.Error())
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"strconv"
+	"strings"
 
 	"github.com/moira-alert/moira/expression"
 	"github.com/moira-alert/moira/remote"
@@ -90,9 +91,22 @@ func (triggerChecker *TriggerChecker) getRemoteTimeSeries(from, until int64) (*t
 		Additional: make([]*target.TimeSeries, 0),
 	}
 
+	remoteConfig, err := triggerChecker.RemoteSources.Get(triggerChecker.trigger.GetRemoteSource())
+	if err != nil {
+		return nil, remote.ErrRemoteTriggerResponse{
+			InternalError: err,
+			Target:        strings.Join(triggerChecker.trigger.Targets, ", "),
+		}
+	}
+
 	isSimpleTrigger := triggerChecker.trigger.IsSimple()
 	for targetIndex, tar := range triggerChecker.trigger.Targets {
-		timeSeries, err := remote.Fetch(triggerChecker.RemoteConfig, tar, from, until, isSimpleTrigger)
+		var timeSeries []*target.TimeSeries
+		if triggerChecker.trigger.IsPromQL() {
+			timeSeries, err = remote.FetchPrometheus(remoteConfig, tar, from, until, isSimpleTrigger)
+		} else {
+			timeSeries, err = remote.Fetch(remoteConfig, tar, from, until, isSimpleTrigger)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -12,18 +12,23 @@ import (
 
 // TriggerChecker represents data, used for handling new trigger state
 type TriggerChecker struct {
-	TriggerID    string
-	Database     moira.Database
-	Logger       moira.Logger
-	Config       *Config
-	RemoteConfig *remote.Config
-	Metrics      *graphite.CheckerMetrics
+	TriggerID     string
+	Database      moira.Database
+	Logger        moira.Logger
+	Config        *Config
+	RemoteSources remote.Sources
+	Metrics       *graphite.CheckerMetrics
+	// Maintenance windows which are not expired, events in scope of active windows are suppressed
+	MaintenanceWindows []*moira.MaintenanceWindow
+	// IDs of triggers with priority tags, they are scheduled to check with high priority
+	PriorityTriggerIDs map[string]bool
 
 	From  int64
 	Until int64
 
-	trigger   *moira.Trigger
-	lastCheck *moira.CheckData
+	trigger        *moira.Trigger
+	lastCheck      *moira.CheckData
+	stateIntervals []*moira.StateInterval
 
 	ttl      int64
 	ttlState string
@@ -46,11 +51,7 @@ func (triggerChecker *TriggerChecker) InitTriggerChecker() error {
 	triggerChecker.trigger = &trigger
 	triggerChecker.ttl = trigger.TTL
 
-	if trigger.TTLState != nil {
-		triggerChecker.ttlState = *trigger.TTLState
-	} else {
-		triggerChecker.ttlState = NODATA
-	}
+	triggerChecker.ttlState = trigger.GetTTLState()
 
 	triggerChecker.lastCheck, err = getLastCheck(triggerChecker.Database, triggerChecker.TriggerID, triggerChecker.Until-3600)
 	if err != nil {
@@ -58,8 +59,8 @@ func (triggerChecker *TriggerChecker) InitTriggerChecker() error {
 	}
 
 	triggerChecker.From = triggerChecker.lastCheck.Timestamp
-	if triggerChecker.ttl != 0 {
-		triggerChecker.From = triggerChecker.From - triggerChecker.ttl
+	if maxTTL := triggerChecker.getMaxTTL(); maxTTL != 0 {
+		triggerChecker.From = triggerChecker.From - maxTTL
 	} else {
 		triggerChecker.From = triggerChecker.From - 600
 	}
@@ -67,6 +68,23 @@ func (triggerChecker *TriggerChecker) InitTriggerChecker() error {
 	return nil
 }
 
+// IsCheckIntervalPassed checks if trigger check interval has passed since its last check
+func (triggerChecker *TriggerChecker) IsCheckIntervalPassed() bool {
+	checkInterval := triggerChecker.trigger.CheckInterval
+	return checkInterval == 0 || triggerChecker.Until-triggerChecker.lastCheck.Timestamp >= checkInterval
+}
+
+// getMaxTTL returns the greatest of trigger TTL and TTL overrides, metrics values are fetched for this interval before the last check
+func (triggerChecker *TriggerChecker) getMaxTTL() int64 {
+	maxTTL := triggerChecker.ttl
+	for _, override := range triggerChecker.trigger.TTLOverrides {
+		if override.TTL > maxTTL {
+			maxTTL = override.TTL
+		}
+	}
+	return maxTTL
+}
+
 func getLastCheck(dataBase moira.Database, triggerID string, emptyLastCheckTimestamp int64) (*moira.CheckData, error) {
 	lastCheck, err := dataBase.GetTriggerLastCheck(triggerID)
 	if err != nil && err != database.ErrNil {
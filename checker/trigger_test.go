@@ -174,4 +174,43 @@ func TestInitTriggerChecker(t *testing.T) {
 		expectedTriggerChecker.From = lastCheck.Timestamp - 600
 		So(triggerChecker, ShouldResemble, expectedTriggerChecker)
 	})
+
+	trigger.TTLOverrides = []moira.TTLOverride{{Pattern: "batch.*", TTL: 7200}}
+
+	Convey("Test trigger checker with lastCheck and ttl overrides", t, func() {
+		dataBase.EXPECT().GetTrigger(triggerChecker.TriggerID).Return(trigger, nil)
+		dataBase.EXPECT().GetTriggerLastCheck(triggerChecker.TriggerID).Return(lastCheck, nil)
+		err := triggerChecker.InitTriggerChecker()
+		So(err, ShouldBeNil)
+
+		expectedTriggerChecker := triggerChecker
+		expectedTriggerChecker.trigger = &trigger
+		expectedTriggerChecker.ttl = 0
+		expectedTriggerChecker.ttlState = ttlStateNoData
+		expectedTriggerChecker.lastCheck = &lastCheck
+		expectedTriggerChecker.From = lastCheck.Timestamp - 7200
+		So(triggerChecker, ShouldResemble, expectedTriggerChecker)
+	})
+}
+
+func TestIsCheckIntervalPassed(t *testing.T) {
+	triggerChecker := TriggerChecker{
+		Until:     1000,
+		trigger:   &moira.Trigger{},
+		lastCheck: &moira.CheckData{Timestamp: 800},
+	}
+
+	Convey("Trigger without check interval should always be checked", t, func() {
+		So(triggerChecker.IsCheckIntervalPassed(), ShouldBeTrue)
+	})
+
+	Convey("Check interval has not passed since last check", t, func() {
+		triggerChecker.trigger.CheckInterval = 300
+		So(triggerChecker.IsCheckIntervalPassed(), ShouldBeFalse)
+	})
+
+	Convey("Check interval has passed since last check", t, func() {
+		triggerChecker.trigger.CheckInterval = 200
+		So(triggerChecker.IsCheckIntervalPassed(), ShouldBeTrue)
+	})
 }
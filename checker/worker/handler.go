@@ -20,13 +20,7 @@ func (worker *Checker) startTriggerHandler(isRemote bool, metrics *graphite.Chec
 		case <-worker.tomb.Dying():
 			return nil
 		default:
-			var triggerID string
-			var err error
-			if isRemote {
-				triggerID, err = worker.Database.GetRemoteTriggerToCheck()
-			} else {
-				triggerID, err = worker.Database.GetTriggerToCheck()
-			}
+			triggerToCheck, err := worker.getTriggerToCheck(isRemote)
 			if err != nil {
 				if err == database.ErrNil {
 					<-time.After(sleepWhenNoTriggerToCheck)
@@ -37,7 +31,9 @@ func (worker *Checker) startTriggerHandler(isRemote bool, metrics *graphite.Chec
 				continue
 			}
 
-			worker.handleTrigger(triggerID, metrics)
+			latency := time.Duration(time.Now().UnixNano()/int64(time.Millisecond)-triggerToCheck.AddedAt) * time.Millisecond
+			metrics.TriggersToCheckLatency.GetOrAdd(string(triggerToCheck.Priority), string(triggerToCheck.Priority)).Update(latency)
+			worker.handleTrigger(triggerToCheck.TriggerID, metrics)
 		}
 	}
 }
@@ -79,12 +75,14 @@ func (worker *Checker) handleTriggerInLock(triggerID string, metrics *graphite.C
 func (worker *Checker) checkTrigger(triggerID string) error {
 	defer worker.Database.DeleteTriggerCheckLock(triggerID)
 	triggerChecker := checker.TriggerChecker{
-		TriggerID:    triggerID,
-		Database:     worker.Database,
-		Logger:       worker.Logger,
-		Config:       worker.Config,
-		RemoteConfig: worker.RemoteConfig,
-		Metrics:      worker.Metrics,
+		TriggerID:          triggerID,
+		Database:           worker.Database,
+		Logger:             worker.Logger,
+		Config:             worker.Config,
+		RemoteSources:      worker.RemoteSources,
+		Metrics:            worker.Metrics,
+		MaintenanceWindows: worker.getMaintenanceWindows(),
+		PriorityTriggerIDs: worker.getPriorityTriggerIDs(),
 	}
 
 	err := triggerChecker.InitTriggerChecker()
@@ -94,5 +92,8 @@ func (worker *Checker) checkTrigger(triggerID string) error {
 		}
 		return err
 	}
+	if !triggerChecker.IsCheckIntervalPassed() {
+		return nil
+	}
 	return triggerChecker.Check()
 }
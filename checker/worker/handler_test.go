@@ -0,0 +1,42 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/checker"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/logging/go-logging"
+	"github.com/moira-alert/moira/metrics/graphite/go-metrics"
+	"github.com/moira-alert/moira/mock/moira-alert"
+)
+
+func TestHandleTriggerInLock(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	logger, _ := logging.ConfigureLog("stdout", "info", "test")
+	checkerMetrics := metrics.ConfigureCheckerMetrics("checker", false)
+	worker := &Checker{
+		Logger:   logger,
+		Database: dataBase,
+		Config:   &checker.Config{ShardingEnabled: true, ShardingInstanceID: "checker-1"},
+		Metrics:  checkerMetrics,
+	}
+	triggerID := "trigger"
+
+	Convey("Trigger should be checked under lock", t, func() {
+		dataBase.EXPECT().SetTriggerCheckLock(triggerID).Return(true, nil)
+		dataBase.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{}, database.ErrNil)
+		dataBase.EXPECT().DeleteTriggerCheckLock(triggerID).Return(nil)
+		So(worker.handleTriggerInLock(triggerID, checkerMetrics.MoiraMetrics), ShouldBeNil)
+	})
+
+	Convey("Trigger locked by other instance should be skipped", t, func() {
+		dataBase.EXPECT().SetTriggerCheckLock(triggerID).Return(false, nil)
+		So(worker.handleTriggerInLock(triggerID, checkerMetrics.MoiraMetrics), ShouldBeNil)
+	})
+}
@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"time"
+
+	"github.com/moira-alert/moira"
+)
+
+func (worker *Checker) maintenanceWindowsUpdater() error {
+	checkTicker := time.NewTicker(worker.Config.CheckInterval)
+	for {
+		select {
+		case <-worker.tomb.Dying():
+			checkTicker.Stop()
+			return nil
+		case <-checkTicker.C:
+			if err := worker.updateMaintenanceWindows(); err != nil {
+				worker.Logger.Errorf("Failed to update maintenance windows: %s", err.Error())
+			}
+		}
+	}
+}
+
+// updateMaintenanceWindows caches maintenance windows which are not expired with parsed recurrence schedules,
+// windows with invalid recurrence are skipped
+func (worker *Checker) updateMaintenanceWindows() error {
+	storedWindows, err := worker.Database.GetMaintenanceWindows(time.Now().Unix())
+	if err != nil {
+		return err
+	}
+	windows := make([]*moira.MaintenanceWindow, 0, len(storedWindows))
+	for _, window := range storedWindows {
+		if err := window.ParseRecurrence(); err != nil {
+			worker.Logger.Warningf("Skip maintenance window %s: %s", window.ID, err.Error())
+			continue
+		}
+		windows = append(windows, window)
+	}
+	worker.maintenanceWindowsLock.Lock()
+	worker.maintenanceWindows = windows
+	worker.maintenanceWindowsLock.Unlock()
+	return nil
+}
+
+// getMaintenanceWindows returns cached maintenance windows, the slice is replaced on update so it can be used without lock
+func (worker *Checker) getMaintenanceWindows() []*moira.MaintenanceWindow {
+	worker.maintenanceWindowsLock.RLock()
+	defer worker.maintenanceWindowsLock.RUnlock()
+	return worker.maintenanceWindows
+}
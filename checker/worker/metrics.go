@@ -46,26 +46,34 @@ func (worker *Checker) handleMetricEvent(pattern string) error {
 }
 
 func (worker *Checker) addTriggerIDsIfNeeded(triggerIDs []string) {
-	needToCheckTriggerIDs := make([]string, len(triggerIDs))
+	needToCheckTriggerIDs := make([]string, 0, len(triggerIDs))
 	for _, triggerID := range triggerIDs {
 		if worker.needHandleTrigger(triggerID) {
 			needToCheckTriggerIDs = append(needToCheckTriggerIDs, triggerID)
 		}
 	}
-	if len(needToCheckTriggerIDs) > 0 {
-		worker.Database.AddTriggersToCheck(needToCheckTriggerIDs)
+	ownedTriggerIDs, commonTriggerIDs := worker.splitTriggerIDsByOwner(needToCheckTriggerIDs)
+	for priority, priorityTriggerIDs := range worker.splitTriggerIDsByPriority(ownedTriggerIDs) {
+		worker.Database.AddShardTriggersToCheck(worker.Config.ShardingInstanceID, priorityTriggerIDs, priority)
+	}
+	for priority, priorityTriggerIDs := range worker.splitTriggerIDsByPriority(commonTriggerIDs) {
+		worker.Database.AddTriggersToCheck(priorityTriggerIDs, priority)
 	}
 }
 
 func (worker *Checker) addRemoteTriggerIDsIfNeeded(triggerIDs []string) {
-	needToCheckRemoteTriggerIDs := make([]string, len(triggerIDs))
+	needToCheckRemoteTriggerIDs := make([]string, 0, len(triggerIDs))
 	for _, triggerID := range triggerIDs {
 		if worker.needHandleTrigger(triggerID) {
 			needToCheckRemoteTriggerIDs = append(needToCheckRemoteTriggerIDs, triggerID)
 		}
 	}
-	if len(needToCheckRemoteTriggerIDs) > 0 {
-		worker.Database.AddRemoteTriggersToCheck(needToCheckRemoteTriggerIDs)
+	ownedRemoteTriggerIDs, commonRemoteTriggerIDs := worker.splitTriggerIDsByOwner(needToCheckRemoteTriggerIDs)
+	for priority, priorityTriggerIDs := range worker.splitTriggerIDsByPriority(ownedRemoteTriggerIDs) {
+		worker.Database.AddShardRemoteTriggersToCheck(worker.Config.ShardingInstanceID, priorityTriggerIDs, priority)
+	}
+	for priority, priorityTriggerIDs := range worker.splitTriggerIDsByPriority(commonRemoteTriggerIDs) {
+		worker.Database.AddRemoteTriggersToCheck(priorityTriggerIDs, priority)
 	}
 }
 
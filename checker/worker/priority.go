@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/checker"
+)
+
+func (worker *Checker) priorityTriggersUpdater() error {
+	checkTicker := time.NewTicker(worker.Config.NoDataCheckInterval)
+	for {
+		select {
+		case <-worker.tomb.Dying():
+			checkTicker.Stop()
+			return nil
+		case <-checkTicker.C:
+			if err := worker.updatePriorityTriggerIDs(); err != nil {
+				worker.Logger.Errorf("Failed to update priority triggers: %s", err.Error())
+			}
+		}
+	}
+}
+
+// updatePriorityTriggerIDs caches IDs of triggers with priority tags
+func (worker *Checker) updatePriorityTriggerIDs() error {
+	priorityTriggerIDs := make(map[string]bool)
+	for _, tag := range worker.Config.PriorityTags {
+		triggerIDs, err := worker.Database.GetTagTriggerIDs(tag)
+		if err != nil {
+			return err
+		}
+		for _, triggerID := range triggerIDs {
+			priorityTriggerIDs[triggerID] = true
+		}
+	}
+	worker.priorityTriggersLock.Lock()
+	worker.priorityTriggerIDs = priorityTriggerIDs
+	worker.priorityTriggersLock.Unlock()
+	return nil
+}
+
+// splitTriggerIDsByPriority splits trigger IDs to high and normal priority ones
+func (worker *Checker) splitTriggerIDsByPriority(triggerIDs []string) map[moira.TriggerCheckPriority][]string {
+	return checker.SplitTriggerIDsByPriority(triggerIDs, worker.getPriorityTriggerIDs())
+}
+
+// getPriorityTriggerIDs returns cached IDs of priority triggers, the map is replaced on update so it can be used without lock
+func (worker *Checker) getPriorityTriggerIDs() map[string]bool {
+	worker.priorityTriggersLock.RLock()
+	defer worker.priorityTriggersLock.RUnlock()
+	return worker.priorityTriggerIDs
+}
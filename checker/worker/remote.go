@@ -6,29 +6,30 @@ import (
 	"github.com/moira-alert/moira/remote"
 )
 
-func (worker *Checker) remoteChecker() error {
-	checkTicker := time.NewTicker(worker.RemoteConfig.CheckInterval)
+func (worker *Checker) remoteChecker(remoteSource string, remoteConfig *remote.Config) error {
+	checkTicker := time.NewTicker(remoteConfig.CheckInterval)
 	for {
 		select {
 		case <-worker.tomb.Dying():
 			checkTicker.Stop()
-			worker.Logger.Info("Remote checker stopped")
+			worker.Logger.Infof("Remote checker of source %s stopped", remoteSource)
 			return nil
 		case <-checkTicker.C:
-			if err := worker.checkRemote(); err != nil {
-				worker.Logger.Errorf("Remote checker failed: %s", err.Error())
+			if err := worker.checkRemote(remoteSource, remoteConfig); err != nil {
+				worker.Logger.Errorf("Remote checker of source %s failed: %s", remoteSource, err.Error())
 			}
 		}
 	}
 }
 
-func (worker *Checker) checkRemote() error {
-	remoteAvailable, err := remote.IsRemoteAvailable(worker.RemoteConfig)
+func (worker *Checker) checkRemote(remoteSource string, remoteConfig *remote.Config) error {
+	defer worker.updateRemoteSourceState(remoteSource, remoteConfig)
+	remoteAvailable, err := remote.IsRemoteAvailable(remoteConfig)
 	if !remoteAvailable {
-		worker.Logger.Infof("Remote API is unavailable. Stop checking remote triggers. Error: %s", err.Error())
+		worker.Logger.Infof("Remote API of source %s is unavailable. Stop checking its remote triggers. Error: %s", remoteSource, err.Error())
 	} else {
-		worker.Logger.Debug("Checking remote triggers")
-		triggerIds, err := worker.Database.GetRemoteTriggerIDs()
+		worker.Logger.Debugf("Checking remote triggers of source %s", remoteSource)
+		triggerIds, err := worker.Database.GetRemoteTriggerIDs(remoteSource)
 		if err != nil {
 			return err
 		}
@@ -36,3 +37,17 @@ func (worker *Checker) checkRemote() error {
 	}
 	return nil
 }
+
+// updateRemoteSourceState reports remote source circuit breaker state, requests in flight and cache usage to metrics
+// and circuit breaker state to self state monitor
+func (worker *Checker) updateRemoteSourceState(remoteSource string, remoteConfig *remote.Config) {
+	breakerState := remoteConfig.GetBreakerState()
+	worker.Metrics.RemoteBreakerState.GetOrAdd(remoteSource, remoteSource).Update(int64(breakerState))
+	worker.Metrics.RemoteRequestsInFlight.GetOrAdd(remoteSource, remoteSource).Update(int64(remoteConfig.GetRequestsInFlight()))
+	cacheHits, cacheMisses := remoteConfig.TakeCacheStats()
+	worker.Metrics.RemoteCacheHits.Mark(cacheHits)
+	worker.Metrics.RemoteCacheMisses.Mark(cacheMisses)
+	if err := worker.Database.SetRemoteBreakerState(remoteSource, breakerState.String()); err != nil {
+		worker.Logger.Errorf("Failed to save remote source %s breaker state: %s", remoteSource, err.Error())
+	}
+}
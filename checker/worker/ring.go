@@ -0,0 +1,73 @@
+package worker
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// ringReplicas is a number of points each checker instance takes on hash ring, it smooths triggers distribution
+const ringReplicas = 128
+
+// hashRing divides trigger IDs between checker instances by consistent hashing,
+// so only triggers of joined or left instance change their owner on rebalance
+type hashRing struct {
+	instances []string
+	points    []uint32
+	owners    map[uint32]string
+}
+
+func newHashRing(instances []string) *hashRing {
+	sortedInstances := make([]string, len(instances))
+	copy(sortedInstances, instances)
+	sort.Strings(sortedInstances)
+	ring := &hashRing{
+		instances: sortedInstances,
+		points:    make([]uint32, 0, len(instances)*ringReplicas),
+		owners:    make(map[uint32]string, len(instances)*ringReplicas),
+	}
+	for _, instance := range sortedInstances {
+		for replica := 0; replica < ringReplicas; replica++ {
+			point := hashKey(instance + "#" + strconv.Itoa(replica))
+			if _, ok := ring.owners[point]; ok {
+				continue
+			}
+			ring.owners[point] = instance
+			ring.points = append(ring.points, point)
+		}
+	}
+	sort.Slice(ring.points, func(i, j int) bool { return ring.points[i] < ring.points[j] })
+	return ring
+}
+
+// getOwner returns checker instance owning trigger, empty string is returned if ring has no instances
+func (ring *hashRing) getOwner(triggerID string) string {
+	if len(ring.points) == 0 {
+		return ""
+	}
+	point := hashKey(triggerID)
+	index := sort.Search(len(ring.points), func(i int) bool { return ring.points[i] >= point })
+	if index == len(ring.points) {
+		index = 0
+	}
+	return ring.owners[ring.points[index]]
+}
+
+// equals checks that rings consist of the same instances
+func (ring *hashRing) equals(other *hashRing) bool {
+	if len(ring.instances) != len(other.instances) {
+		return false
+	}
+	for i := range ring.instances {
+		if ring.instances[i] != other.instances[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func hashKey(key string) uint32 {
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+	return hash.Sum32()
+}
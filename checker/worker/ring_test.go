@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestHashRing(t *testing.T) {
+	triggerIDs := make([]string, 0, 3000)
+	for i := 0; i < 3000; i++ {
+		triggerIDs = append(triggerIDs, fmt.Sprintf("trigger-%d", i))
+	}
+
+	Convey("Empty ring should not have owners", t, func() {
+		So(newHashRing(nil).getOwner("trigger-1"), ShouldBeEmpty)
+	})
+
+	Convey("Triggers should be divided between all instances", t, func() {
+		ring := newHashRing([]string{"checker-1", "checker-2", "checker-3"})
+		triggersCount := make(map[string]int)
+		for _, triggerID := range triggerIDs {
+			triggersCount[ring.getOwner(triggerID)]++
+		}
+		So(triggersCount, ShouldHaveLength, 3)
+		for _, count := range triggersCount {
+			So(count, ShouldBeBetween, 600, 1400)
+		}
+	})
+
+	Convey("Owner should not depend on instances order", t, func() {
+		ring1 := newHashRing([]string{"checker-1", "checker-2", "checker-3"})
+		ring2 := newHashRing([]string{"checker-3", "checker-1", "checker-2"})
+		So(ring1.equals(ring2), ShouldBeTrue)
+		for _, triggerID := range triggerIDs {
+			So(ring1.getOwner(triggerID), ShouldEqual, ring2.getOwner(triggerID))
+		}
+	})
+
+	Convey("Only triggers of left instance should change owner", t, func() {
+		ring := newHashRing([]string{"checker-1", "checker-2", "checker-3"})
+		rebalancedRing := newHashRing([]string{"checker-1", "checker-2"})
+		So(ring.equals(rebalancedRing), ShouldBeFalse)
+		for _, triggerID := range triggerIDs {
+			if owner := ring.getOwner(triggerID); owner != "checker-3" {
+				So(rebalancedRing.getOwner(triggerID), ShouldEqual, owner)
+			}
+		}
+	})
+}
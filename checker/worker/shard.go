@@ -0,0 +1,132 @@
+package worker
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+// checkerShard holds hash ring of alive checker instances. Previous ring is kept during handover period after rebalance
+// to put triggers, which have just changed their owner, to common queue, where they are checked under lock by any instance
+type checkerShard struct {
+	lock          sync.RWMutex
+	ring          *hashRing
+	previousRing  *hashRing
+	handoverUntil time.Time
+}
+
+// setRing replaces hash ring and starts handover period if instances were changed
+func (shard *checkerShard) setRing(ring *hashRing, handoverUntil time.Time) bool {
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+	if shard.ring != nil && shard.ring.equals(ring) {
+		return false
+	}
+	shard.previousRing = shard.ring
+	shard.ring = ring
+	shard.handoverUntil = handoverUntil
+	return true
+}
+
+func (worker *Checker) shardMembershipUpdater() error {
+	checkTicker := time.NewTicker(worker.Config.ShardingHeartbeatInterval)
+	for {
+		select {
+		case <-worker.tomb.Dying():
+			checkTicker.Stop()
+			if err := worker.Database.RemoveCheckerInstance(worker.Config.ShardingInstanceID); err != nil {
+				worker.Logger.Errorf("Failed to remove checker instance %s: %s", worker.Config.ShardingInstanceID, err.Error())
+			}
+			worker.Logger.Info("Checker instance membership stopped")
+			return nil
+		case <-checkTicker.C:
+			if err := worker.updateShardMembership(); err != nil {
+				worker.Logger.Errorf("Failed to update checker instances membership: %s", err.Error())
+			}
+		}
+	}
+}
+
+// updateShardMembership sends heartbeat of current instance, removes instances without heartbeats
+// and rebalances triggers between alive instances
+func (worker *Checker) updateShardMembership() error {
+	instanceID := worker.Config.ShardingInstanceID
+	if err := worker.Database.SetCheckerHeartbeat(instanceID); err != nil {
+		return err
+	}
+	instances, err := worker.Database.GetCheckerInstances()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	aliveInstances := []string{instanceID}
+	for instance, heartbeat := range instances {
+		if instance == instanceID {
+			continue
+		}
+		if now.Unix()-heartbeat > int64(worker.Config.ShardingHeartbeatTTL.Seconds()) {
+			worker.Logger.Infof("Checker instance %s left, last heartbeat was at %s", instance, time.Unix(heartbeat, 0).Format(time.RFC3339))
+			if err := worker.Database.RemoveCheckerInstance(instance); err != nil {
+				return err
+			}
+			continue
+		}
+		aliveInstances = append(aliveInstances, instance)
+	}
+	ring := newHashRing(aliveInstances)
+	if worker.shard.setRing(ring, now.Add(worker.Config.ShardingHeartbeatTTL)) {
+		worker.Logger.Infof("Checker instances changed, triggers are divided between: %s", strings.Join(ring.instances, ", "))
+	}
+	return nil
+}
+
+// splitTriggerIDsByOwner splits trigger IDs to ones owned by current instance and ones which must be put to common queue.
+// Common queue is used for all triggers if sharding is disabled or instances are unknown yet, and for triggers
+// which current instance has just handed over to other instance. Triggers of other instances are skipped
+func (worker *Checker) splitTriggerIDsByOwner(triggerIDs []string) ([]string, []string) {
+	if !worker.Config.ShardingEnabled {
+		return nil, triggerIDs
+	}
+	worker.shard.lock.RLock()
+	defer worker.shard.lock.RUnlock()
+	if worker.shard.ring == nil {
+		return nil, triggerIDs
+	}
+
+	instanceID := worker.Config.ShardingInstanceID
+	isHandover := worker.shard.previousRing != nil && time.Now().Before(worker.shard.handoverUntil)
+	ownedTriggerIDs := make([]string, 0, len(triggerIDs))
+	commonTriggerIDs := make([]string, 0)
+	for _, triggerID := range triggerIDs {
+		switch {
+		case worker.shard.ring.getOwner(triggerID) == instanceID:
+			ownedTriggerIDs = append(ownedTriggerIDs, triggerID)
+		case isHandover && worker.shard.previousRing.getOwner(triggerID) == instanceID:
+			commonTriggerIDs = append(commonTriggerIDs, triggerID)
+		}
+	}
+	return ownedTriggerIDs, commonTriggerIDs
+}
+
+// getTriggerToCheck pops trigger owned by current instance, if there are no such triggers, trigger from common queue is returned
+func (worker *Checker) getTriggerToCheck(isRemote bool) (moira.TriggerToCheck, error) {
+	if worker.Config.ShardingEnabled {
+		var triggerToCheck moira.TriggerToCheck
+		var err error
+		if isRemote {
+			triggerToCheck, err = worker.Database.GetShardRemoteTriggerToCheck(worker.Config.ShardingInstanceID)
+		} else {
+			triggerToCheck, err = worker.Database.GetShardTriggerToCheck(worker.Config.ShardingInstanceID)
+		}
+		if err != database.ErrNil {
+			return triggerToCheck, err
+		}
+	}
+	if isRemote {
+		return worker.Database.GetRemoteTriggerToCheck()
+	}
+	return worker.Database.GetTriggerToCheck()
+}
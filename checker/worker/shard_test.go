@@ -0,0 +1,160 @@
+package worker
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/checker"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/logging/go-logging"
+	"github.com/moira-alert/moira/mock/moira-alert"
+)
+
+func TestUpdateShardMembership(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	logger, _ := logging.ConfigureLog("stdout", "info", "test")
+	worker := &Checker{
+		Logger:   logger,
+		Database: dataBase,
+		Config: &checker.Config{
+			ShardingEnabled:      true,
+			ShardingInstanceID:   "checker-1",
+			ShardingHeartbeatTTL: time.Minute,
+		},
+	}
+	now := time.Now().Unix()
+
+	Convey("Alive instances should divide triggers, left instances should be removed", t, func() {
+		dataBase.EXPECT().SetCheckerHeartbeat("checker-1").Return(nil)
+		dataBase.EXPECT().GetCheckerInstances().Return(map[string]int64{
+			"checker-1": now,
+			"checker-2": now - 30,
+			"checker-3": now - 120,
+		}, nil)
+		dataBase.EXPECT().RemoveCheckerInstance("checker-3").Return(nil)
+
+		So(worker.updateShardMembership(), ShouldBeNil)
+		So(worker.shard.ring.instances, ShouldResemble, []string{"checker-1", "checker-2"})
+		So(worker.shard.previousRing, ShouldBeNil)
+
+		Convey("Instance should be in ring even if its heartbeat is not saved yet", func() {
+			dataBase.EXPECT().SetCheckerHeartbeat("checker-1").Return(nil)
+			dataBase.EXPECT().GetCheckerInstances().Return(map[string]int64{"checker-2": now}, nil)
+
+			So(worker.updateShardMembership(), ShouldBeNil)
+			So(worker.shard.ring.instances, ShouldResemble, []string{"checker-1", "checker-2"})
+		})
+
+		Convey("Rebalance should start handover", func() {
+			dataBase.EXPECT().SetCheckerHeartbeat("checker-1").Return(nil)
+			dataBase.EXPECT().GetCheckerInstances().Return(map[string]int64{"checker-1": now}, nil)
+
+			So(worker.updateShardMembership(), ShouldBeNil)
+			So(worker.shard.ring.instances, ShouldResemble, []string{"checker-1"})
+			So(worker.shard.previousRing.instances, ShouldResemble, []string{"checker-1", "checker-2"})
+			So(worker.shard.handoverUntil, ShouldHappenAfter, time.Now())
+		})
+	})
+
+	Convey("Heartbeat error should be returned", t, func() {
+		dataBase.EXPECT().SetCheckerHeartbeat("checker-1").Return(fmt.Errorf("connection refused"))
+		So(worker.updateShardMembership(), ShouldResemble, fmt.Errorf("connection refused"))
+	})
+}
+
+func TestSplitTriggerIDsByOwner(t *testing.T) {
+	triggerIDs := make([]string, 0, 100)
+	for i := 0; i < 100; i++ {
+		triggerIDs = append(triggerIDs, fmt.Sprintf("trigger-%d", i))
+	}
+	worker := &Checker{Config: &checker.Config{ShardingInstanceID: "checker-1"}}
+
+	Convey("All triggers should be put to common queue if sharding is disabled", t, func() {
+		owned, common := worker.splitTriggerIDsByOwner(triggerIDs)
+		So(owned, ShouldBeEmpty)
+		So(common, ShouldResemble, triggerIDs)
+	})
+
+	worker.Config.ShardingEnabled = true
+	Convey("All triggers should be put to common queue until instances are known", t, func() {
+		owned, common := worker.splitTriggerIDsByOwner(triggerIDs)
+		So(owned, ShouldBeEmpty)
+		So(common, ShouldResemble, triggerIDs)
+	})
+
+	Convey("Given instances ring", t, func() {
+		ring := newHashRing([]string{"checker-1", "checker-2"})
+		worker.shard = checkerShard{ring: ring}
+
+		Convey("only owned triggers should be taken", func() {
+			owned, common := worker.splitTriggerIDsByOwner(triggerIDs)
+			So(common, ShouldBeEmpty)
+			So(owned, ShouldNotBeEmpty)
+			So(len(owned), ShouldBeLessThan, len(triggerIDs))
+			for _, triggerID := range owned {
+				So(ring.getOwner(triggerID), ShouldEqual, "checker-1")
+			}
+		})
+
+		Convey("triggers handed over to other instance should be put to common queue during handover", func() {
+			rebalancedRing := newHashRing([]string{"checker-1", "checker-2", "checker-3"})
+			worker.shard.setRing(rebalancedRing, time.Now().Add(time.Minute))
+
+			owned, common := worker.splitTriggerIDsByOwner(triggerIDs)
+			So(common, ShouldNotBeEmpty)
+			for _, triggerID := range common {
+				So(ring.getOwner(triggerID), ShouldEqual, "checker-1")
+				So(rebalancedRing.getOwner(triggerID), ShouldNotEqual, "checker-1")
+			}
+			for _, triggerID := range owned {
+				So(rebalancedRing.getOwner(triggerID), ShouldEqual, "checker-1")
+			}
+
+			worker.shard.handoverUntil = time.Now()
+			_, common = worker.splitTriggerIDsByOwner(triggerIDs)
+			So(common, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestGetTriggerToCheck(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	worker := &Checker{
+		Database: dataBase,
+		Config:   &checker.Config{ShardingEnabled: true, ShardingInstanceID: "checker-1"},
+	}
+	owned := moira.TriggerToCheck{TriggerID: "owned", Priority: moira.NormalCheckPriority}
+	common := moira.TriggerToCheck{TriggerID: "common", Priority: moira.NormalCheckPriority}
+
+	Convey("Owned trigger should be checked first", t, func() {
+		dataBase.EXPECT().GetShardTriggerToCheck("checker-1").Return(owned, nil)
+		triggerToCheck, err := worker.getTriggerToCheck(false)
+		So(err, ShouldBeNil)
+		So(triggerToCheck, ShouldResemble, owned)
+	})
+
+	Convey("Trigger from common queue should be checked if there are no owned ones", t, func() {
+		dataBase.EXPECT().GetShardRemoteTriggerToCheck("checker-1").Return(moira.TriggerToCheck{}, database.ErrNil)
+		dataBase.EXPECT().GetRemoteTriggerToCheck().Return(common, nil)
+		triggerToCheck, err := worker.getTriggerToCheck(true)
+		So(err, ShouldBeNil)
+		So(triggerToCheck, ShouldResemble, common)
+	})
+
+	Convey("Common queue should be used if sharding is disabled", t, func() {
+		worker.Config.ShardingEnabled = false
+		dataBase.EXPECT().GetTriggerToCheck().Return(common, nil)
+		triggerToCheck, err := worker.getTriggerToCheck(false)
+		So(err, ShouldBeNil)
+		So(triggerToCheck, ShouldResemble, common)
+	})
+}
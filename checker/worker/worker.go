@@ -2,6 +2,7 @@ package worker
 
 import (
 	"runtime"
+	"sync"
 	"time"
 
 	"github.com/moira-alert/moira/remote"
@@ -18,13 +19,21 @@ type Checker struct {
 	Logger        moira.Logger
 	Database      moira.Database
 	Config        *checker.Config
-	RemoteConfig  *remote.Config
+	RemoteSources remote.Sources
 	Metrics       *graphite.CheckerMetrics
 	TriggerCache  *cache.Cache
 	PatternCache  *cache.Cache
 	lastData      int64
 	tomb          tomb.Tomb
 	remoteEnabled bool
+
+	priorityTriggerIDs   map[string]bool
+	priorityTriggersLock sync.RWMutex
+
+	maintenanceWindows     []*moira.MaintenanceWindow
+	maintenanceWindowsLock sync.RWMutex
+
+	shard checkerShard
 }
 
 // Start start schedule new MetricEvents and check for NODATA triggers
@@ -41,10 +50,36 @@ func (worker *Checker) Start() error {
 		return err
 	}
 
+	if err := worker.Database.SetMetricsTTL(worker.Config.MetricsTTLSeconds); err != nil {
+		worker.Logger.Errorf("Failed to save metrics ttl: %s", err.Error())
+	}
+
+	if err := worker.Database.MigrateTriggersToCheck(); err != nil {
+		worker.Logger.Errorf("Failed to migrate triggers to check: %s", err.Error())
+	}
+
+	if err := worker.updatePriorityTriggerIDs(); err != nil {
+		worker.Logger.Errorf("Failed to update priority triggers: %s", err.Error())
+	}
+	worker.tomb.Go(worker.priorityTriggersUpdater)
+
+	if err := worker.updateMaintenanceWindows(); err != nil {
+		worker.Logger.Errorf("Failed to update maintenance windows: %s", err.Error())
+	}
+	worker.tomb.Go(worker.maintenanceWindowsUpdater)
+
+	if worker.Config.ShardingEnabled {
+		if err := worker.updateShardMembership(); err != nil {
+			worker.Logger.Errorf("Failed to update checker instances membership: %s", err.Error())
+		}
+		worker.tomb.Go(worker.shardMembershipUpdater)
+		worker.Logger.Infof("Checker instance %s membership started", worker.Config.ShardingInstanceID)
+	}
+
 	worker.tomb.Go(worker.noDataChecker)
 	worker.Logger.Info("NODATA checker started")
 
-	worker.remoteEnabled = worker.RemoteConfig.IsEnabled()
+	worker.remoteEnabled = worker.RemoteSources.IsEnabled()
 
 	if worker.remoteEnabled && worker.Config.MaxParallelRemoteChecks == 0 {
 		worker.Config.MaxParallelRemoteChecks = runtime.NumCPU()
@@ -52,8 +87,11 @@ func (worker *Checker) Start() error {
 	}
 
 	if worker.remoteEnabled {
-		worker.tomb.Go(worker.remoteChecker)
-		worker.Logger.Info("Remote checker started")
+		for _, remoteSource := range worker.RemoteSources.GetEnabledNames() {
+			remoteSource, remoteConfig := remoteSource, worker.RemoteSources[remoteSource]
+			worker.tomb.Go(func() error { return worker.remoteChecker(remoteSource, remoteConfig) })
+			worker.Logger.Infof("Remote checker of source %s started", remoteSource)
+		}
 	} else {
 		worker.Logger.Info("Remote checker disabled")
 	}
@@ -11,7 +11,11 @@ type config struct {
 	Logger   cmd.LoggerConfig   `yaml:"log"`
 	API      apiConfig          `yaml:"api"`
 	Pprof    cmd.ProfilerConfig `yaml:"pprof"`
-	Remote   cmd.RemoteConfig   `yaml:"remote"`
+	// Trigger states ordered from the least to the most critical one
+	Severities []cmd.SeverityConfig `yaml:"severities"`
+	Remote     cmd.RemoteConfig     `yaml:"remote"`
+	// Named remote graphite storages, triggers refer to them by remote_source. Remote section above is used as "default" source
+	Remotes map[string]cmd.RemoteConfig `yaml:"remotes"`
 }
 
 type apiConfig struct {
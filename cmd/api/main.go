@@ -66,6 +66,10 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = cmd.ConfigureSeverities(config.Severities); err != nil {
+		logger.Fatalf("Can not configure severities: %s", err.Error())
+	}
+
 	configFile, err := getWebConfigBytes(config.API.WebConfigPath)
 	if err != nil {
 		logger.Warningf("Failed to read web config file by path '%s', method 'api/config' will be return 404, error: %s'", config.API.WebConfigPath, err.Error())
@@ -94,8 +98,11 @@ func main() {
 
 	logger.Infof("Start listening by address: [%s]", apiConfig.Listen)
 
-	remoteConfig := config.Remote.GetSettings()
-	httpHandler := handler.NewHandler(database, logger, apiConfig, remoteConfig, configFile)
+	remoteSources, err := cmd.GetRemoteSources(config.Remote, config.Remotes)
+	if err != nil {
+		logger.Fatalf("Can not configure remote sources: %s", err.Error())
+	}
+	httpHandler := handler.NewHandler(database, logger, apiConfig, remoteSources, configFile)
 	server := &http.Server{
 		Handler: httpHandler,
 	}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"os"
+
 	"github.com/gosexy/to"
 	"github.com/moira-alert/moira/checker"
 	"github.com/moira-alert/moira/cmd"
@@ -12,7 +14,11 @@ type config struct {
 	Logger   cmd.LoggerConfig   `yaml:"log"`
 	Checker  checkerConfig      `yaml:"checker"`
 	Pprof    cmd.ProfilerConfig `yaml:"pprof"`
-	Remote   cmd.RemoteConfig   `yaml:"remote"`
+	// Trigger states ordered from the least to the most critical one
+	Severities []cmd.SeverityConfig `yaml:"severities"`
+	Remote     cmd.RemoteConfig     `yaml:"remote"`
+	// Named remote graphite storages, triggers refer to them by remote_source. Remote section above is used as "default" source
+	Remotes map[string]cmd.RemoteConfig `yaml:"remotes"`
 }
 
 type checkerConfig struct {
@@ -23,22 +29,55 @@ type checkerConfig struct {
 	// Min period to perform triggers re-check. Note: Reducing of this value leads to increasing of CPU and memory usage values
 	CheckInterval string `yaml:"check_interval"`
 	// Time interval to store metrics. Note: Increasing of this value leads to increasing of Redis memory consumption value
+	// Note: local slo triggers can use only burn rate windows not longer than this value
 	MetricsTTL string `yaml:"metrics_ttl"`
+	// Time interval to store trigger and metrics state history. History is not stored if value is 0
+	StateHistoryRetention string `yaml:"state_history_retention"`
 	// Max concurrent checkers to run. Equals to the number of processor cores found on Moira host by default or when variable is defined as 0.
 	MaxParallelChecks int `yaml:"max_parallel_checks"`
 	// Max concurrent remote checkers to run. Equals to the number of processor cores found on Moira host by default or when variable is defined as 0.
 	MaxParallelRemoteChecks int `yaml:"max_parallel_remote_checks"`
+	// Triggers with any of these tags are checked first when checker is backlogged
+	PriorityTags []string `yaml:"priority_tags"`
+	// Checker instances membership settings to divide triggers between instances
+	Sharding shardingConfig `yaml:"sharding"`
+}
+
+type shardingConfig struct {
+	// If true, checker instances divide triggers between themselves by consistent hashing of trigger IDs instead of checking them from common queue.
+	// It keeps triggers and patterns caches of every instance warm, but all checker instances must have sharding enabled
+	Enabled bool `yaml:"enabled"`
+	// Unique checker instance name. Host name is used by default
+	InstanceID string `yaml:"instance_id"`
+	// Period to report checker instance is alive and to refresh list of alive instances
+	HeartbeatInterval string `yaml:"heartbeat_interval"`
+	// Instance is considered left if it didn't report during this period. Triggers which changed owner within this period after rebalance are checked from common queue under lock
+	HeartbeatTTL string `yaml:"heartbeat_ttl"`
 }
 
 func (config *checkerConfig) getSettings() *checker.Config {
 	return &checker.Config{
-		MetricsTTLSeconds:           int64(to.Duration(config.MetricsTTL).Seconds()),
-		CheckInterval:               to.Duration(config.CheckInterval),
-		NoDataCheckInterval:         to.Duration(config.NoDataCheckInterval),
-		StopCheckingIntervalSeconds: int64(to.Duration(config.StopCheckingInterval).Seconds()),
-		MaxParallelChecks:           config.MaxParallelChecks,
-		MaxParallelRemoteChecks:     config.MaxParallelRemoteChecks,
+		MetricsTTLSeconds:            int64(to.Duration(config.MetricsTTL).Seconds()),
+		CheckInterval:                to.Duration(config.CheckInterval),
+		NoDataCheckInterval:          to.Duration(config.NoDataCheckInterval),
+		StopCheckingIntervalSeconds:  int64(to.Duration(config.StopCheckingInterval).Seconds()),
+		StateHistoryRetentionSeconds: int64(to.Duration(config.StateHistoryRetention).Seconds()),
+		MaxParallelChecks:            config.MaxParallelChecks,
+		MaxParallelRemoteChecks:      config.MaxParallelRemoteChecks,
+		PriorityTags:                 config.PriorityTags,
+		ShardingEnabled:              config.Sharding.Enabled,
+		ShardingInstanceID:           config.Sharding.getInstanceID(),
+		ShardingHeartbeatInterval:    to.Duration(config.Sharding.HeartbeatInterval),
+		ShardingHeartbeatTTL:         to.Duration(config.Sharding.HeartbeatTTL),
+	}
+}
+
+func (config *shardingConfig) getInstanceID() string {
+	if config.InstanceID != "" {
+		return config.InstanceID
 	}
+	hostname, _ := os.Hostname()
+	return hostname
 }
 
 func getDefault() config {
@@ -56,8 +95,14 @@ func getDefault() config {
 			CheckInterval:           "5s",
 			MetricsTTL:              "1h",
 			StopCheckingInterval:    "30s",
+			StateHistoryRetention:   "0s",
 			MaxParallelChecks:       0,
 			MaxParallelRemoteChecks: 0,
+			PriorityTags:            []string{"critical"},
+			Sharding: shardingConfig{
+				HeartbeatInterval: "5s",
+				HeartbeatTTL:      "15s",
+			},
 		},
 		Graphite: cmd.GraphiteConfig{
 			RuntimeStats: false,
@@ -69,8 +114,12 @@ func getDefault() config {
 			Listen: "",
 		},
 		Remote: cmd.RemoteConfig{
-			CheckInterval: "60s",
-			Timeout:       "60s",
+			CheckInterval:       "60s",
+			Timeout:             "60s",
+			MaxInFlight:         32,
+			BreakerMaxFailures:  5,
+			BreakerOpenInterval: "30s",
+			CacheTTL:            "15s",
 		},
 	}
 }
@@ -66,6 +66,10 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Can not configure log: %s\n", err.Error())
 		os.Exit(1)
 	}
+
+	if err = cmd.ConfigureSeverities(config.Severities); err != nil {
+		logger.Fatalf("Can not configure severities: %s", err.Error())
+	}
 	defer logger.Infof("Moira Checker stopped. Version: %s", MoiraVersion)
 
 	if config.Pprof.Listen != "" {
@@ -76,8 +80,11 @@ func main() {
 	databaseSettings := config.Redis.GetSettings()
 	database := redis.NewDatabase(logger, databaseSettings)
 
-	remoteSettings := config.Remote.GetSettings()
-	checkerMetrics := metrics.ConfigureCheckerMetrics(serviceName, remoteSettings.IsEnabled())
+	remoteSources, err := cmd.GetRemoteSources(config.Remote, config.Remotes)
+	if err != nil {
+		logger.Fatalf("Can not configure remote sources: %s", err.Error())
+	}
+	checkerMetrics := metrics.ConfigureCheckerMetrics(serviceName, remoteSources.IsEnabled())
 	graphiteSettings := config.Graphite.GetSettings()
 	if err = metrics.Init(graphiteSettings, serviceName); err != nil {
 		logger.Error(err)
@@ -85,20 +92,20 @@ func main() {
 
 	checkerSettings := config.Checker.getSettings()
 	if triggerID != nil && *triggerID != "" {
-		checkSingleTrigger(database, checkerMetrics, checkerSettings, remoteSettings)
+		checkSingleTrigger(database, checkerMetrics, checkerSettings, remoteSources)
 	}
 
 	// configure carbon-api functions
 	functions.New(make(map[string]string))
 
 	checkerWorker := &worker.Checker{
-		Logger:       logger,
-		Database:     database,
-		Config:       checkerSettings,
-		RemoteConfig: remoteSettings,
-		Metrics:      checkerMetrics,
-		TriggerCache: cache.New(checkerSettings.CheckInterval, time.Minute*60),
-		PatternCache: cache.New(checkerSettings.CheckInterval, time.Minute*60),
+		Logger:        logger,
+		Database:      database,
+		Config:        checkerSettings,
+		RemoteSources: remoteSources,
+		Metrics:       checkerMetrics,
+		TriggerCache:  cache.New(checkerSettings.CheckInterval, time.Minute*60),
+		PatternCache:  cache.New(checkerSettings.CheckInterval, time.Minute*60),
 	}
 	err = checkerWorker.Start()
 	if err != nil {
@@ -113,14 +120,14 @@ func main() {
 	logger.Infof("Moira Checker shutting down.")
 }
 
-func checkSingleTrigger(database moira.Database, metrics *graphite.CheckerMetrics, settings *checker.Config, remoteSettings *remote.Config) {
+func checkSingleTrigger(database moira.Database, metrics *graphite.CheckerMetrics, settings *checker.Config, remoteSources remote.Sources) {
 	triggerChecker := checker.TriggerChecker{
-		TriggerID:    *triggerID,
-		Database:     database,
-		Logger:       logger,
-		Config:       settings,
-		RemoteConfig: remoteSettings,
-		Metrics:      metrics,
+		TriggerID:     *triggerID,
+		Database:      database,
+		Logger:        logger,
+		Config:        settings,
+		RemoteSources: remoteSources,
+		Metrics:       metrics,
 	}
 
 	err := triggerChecker.InitTriggerChecker()
@@ -9,6 +9,7 @@ import (
 	"github.com/moira-alert/moira/remote"
 	"gopkg.in/yaml.v2"
 
+	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/database/redis"
 	"github.com/moira-alert/moira/metrics/graphite"
 )
@@ -77,9 +78,49 @@ type ProfilerConfig struct {
 	Listen string `yaml:"listen"`
 }
 
+// SeverityConfig is a trigger state settings structure. Severities are configured in order from the least to the most critical one,
+// the same severities must be configured for checker, notifier and api
+type SeverityConfig struct {
+	// State name used in triggers expressions and events, e.g. CRITICAL. OK, WARN, ERROR and NODATA states are required, OK must be the first one
+	Name string `yaml:"name"`
+	// Added to trigger score for trigger and every its metric in this state, triggers with greater score are shown first
+	Score int64 `yaml:"score"`
+	// If true, transitions between OK and this state are not sent to subscriptions ignoring warnings
+	Warning bool `yaml:"warning"`
+	// Color used by senders to highlight state, e.g. '#cc0032'
+	Color string `yaml:"color"`
+	// Emoji used by messenger senders to mark state
+	Emoji string `yaml:"emoji"`
+}
+
+// ConfigureSeverities sets severity model used by moira services, default OK, WARN, ERROR and NODATA severities are used if none are configured
+func ConfigureSeverities(configs []SeverityConfig) error {
+	if len(configs) == 0 {
+		return nil
+	}
+	severities := make([]moira.Severity, 0, len(configs))
+	for _, config := range configs {
+		severities = append(severities, moira.Severity{
+			Name:    config.Name,
+			Score:   config.Score,
+			Warning: config.Warning,
+			Color:   config.Color,
+			Emoji:   config.Emoji,
+		})
+	}
+	model, err := moira.NewSeverityModel(severities)
+	if err != nil {
+		return err
+	}
+	moira.SetSeverityModel(model)
+	return nil
+}
+
 // RemoteConfig is remote graphite settings structure
 type RemoteConfig struct {
-	// graphite url e.g http://graphite/render
+	// Remote storage type: graphite (default) or prometheus. Triggers with promql target language can use only prometheus remote storages
+	Type string `yaml:"type"`
+	// graphite url e.g http://graphite/render, or prometheus query_range API url e.g http://prometheus:9090/api/v1/query_range
 	URL string `yaml:"url"`
 	// Min period to perform triggers re-check. Note: Reducing of this value leads to increasing of CPU and memory usage values
 	CheckInterval string `yaml:"check_interval"`
@@ -91,18 +132,71 @@ type RemoteConfig struct {
 	Password string `yaml:"password"`
 	// If true, remote worker will be enabled.
 	Enabled bool `yaml:"enabled"`
+	// Max number of concurrent requests to remote storage, unlimited if 0
+	MaxInFlight int `yaml:"max_in_flight"`
+	// Number of consecutive failed requests to open circuit breaker, which stops requesting remote storage. Circuit breaker is disabled if 0
+	BreakerMaxFailures int `yaml:"breaker_max_failures"`
+	// Period after which open circuit breaker lets single trial request through to check remote storage recovery
+	BreakerOpenInterval string `yaml:"breaker_open_interval"`
+	// Time to keep successful remote responses in cache, so triggers with the same targets share them. Concurrent identical requests are coalesced even if 0
+	CacheTTL string `yaml:"cache_ttl"`
+	// Graphite render API response format: json (default), protobuf (carbonapi only) or msgpack (graphite-web only). Binary formats are faster to decode for wide wildcard targets. Service fails to start with unknown format
+	Format string `yaml:"format"`
 }
 
 // GetSettings returns remote config parsed from moira config files
 func (config *RemoteConfig) GetSettings() *remote.Config {
 	return &remote.Config{
-		URL:           config.URL,
-		CheckInterval: to.Duration(config.CheckInterval),
-		Timeout:       to.Duration(config.Timeout),
-		User:          config.User,
-		Password:      config.Password,
-		Enabled:       config.Enabled,
+		Type:                config.Type,
+		URL:                 config.URL,
+		CheckInterval:       to.Duration(config.CheckInterval),
+		Timeout:             to.Duration(config.Timeout),
+		User:                config.User,
+		Password:            config.Password,
+		Enabled:             config.Enabled,
+		MaxInFlight:         config.MaxInFlight,
+		BreakerMaxFailures:  config.BreakerMaxFailures,
+		BreakerOpenInterval: to.Duration(config.BreakerOpenInterval),
+		CacheTTL:            to.Duration(config.CacheTTL),
+		Format:              config.Format,
+	}
+}
+
+// GetRemoteSources returns remote sources settings: default remote config is used as default remote source,
+// named remote configs inherit check interval, timeout, requests limits and cache ttl from it if they are not set
+// Error is returned if response format of any remote config is unknown
+func GetRemoteSources(defaultConfig RemoteConfig, configs map[string]RemoteConfig) (remote.Sources, error) {
+	if err := remote.CheckFormat(defaultConfig.Format); err != nil {
+		return nil, fmt.Errorf("remote: %s", err.Error())
+	}
+	sources := remote.Sources{
+		moira.DefaultRemoteSource: defaultConfig.GetSettings(),
+	}
+	for name, config := range configs {
+		if err := remote.CheckFormat(config.Format); err != nil {
+			return nil, fmt.Errorf("remotes.%s: %s", name, err.Error())
+		}
+		if config.CheckInterval == "" {
+			config.CheckInterval = defaultConfig.CheckInterval
+		}
+		if config.Timeout == "" {
+			config.Timeout = defaultConfig.Timeout
+		}
+		if config.MaxInFlight == 0 {
+			config.MaxInFlight = defaultConfig.MaxInFlight
+		}
+		if config.BreakerMaxFailures == 0 {
+			config.BreakerMaxFailures = defaultConfig.BreakerMaxFailures
+		}
+		if config.BreakerOpenInterval == "" {
+			config.BreakerOpenInterval = defaultConfig.BreakerOpenInterval
+		}
+		if config.CacheTTL == "" {
+			config.CacheTTL = defaultConfig.CacheTTL
+		}
+		sources[name] = config.GetSettings()
 	}
+	return sources, nil
 }
 
 // ReadConfig parses config file by the given path into Moira-used type
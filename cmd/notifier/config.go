@@ -18,6 +18,8 @@ type config struct {
 	Logger   cmd.LoggerConfig   `yaml:"log"`
 	Notifier notifierConfig     `yaml:"notifier"`
 	Pprof    cmd.ProfilerConfig `yaml:"pprof"`
+	// Trigger states ordered from the least to the most critical one
+	Severities []cmd.SeverityConfig `yaml:"severities"`
 }
 
 type notifierConfig struct {
@@ -40,8 +42,10 @@ type notifierConfig struct {
 type selfStateConfig struct {
 	// If true, Self state monitor will be enabled
 	Enabled bool `yaml:"enabled"`
-	// If true, Self state monitor will check remote checker status
+	// If true, Self state monitor will check remote checker status of default remote source
 	RemoteTriggersEnabled bool `yaml:"remote_triggers_enabled"`
+	// Names of remote sources, which remote checker status Self state monitor will check additionally to default one
+	RemoteSources []string `yaml:"remote_sources"`
 	// Max Redis disconnect delay to send alert when reached
 	RedisDisconnectDelay string `yaml:"redis_disconect_delay"`
 	// Max Filter metrics receive delay to send alert when reached
@@ -129,9 +133,14 @@ func checkDateTimeFormat(format string) error {
 }
 
 func (config *selfStateConfig) getSettings() selfstate.Config {
+	remoteSources := make([]string, 0, len(config.RemoteSources)+1)
+	if config.RemoteTriggersEnabled {
+		remoteSources = append(remoteSources, moira.DefaultRemoteSource)
+	}
+	remoteSources = append(remoteSources, config.RemoteSources...)
 	return selfstate.Config{
 		Enabled:                        config.Enabled,
-		RemoteTriggersEnabled:          config.RemoteTriggersEnabled,
+		RemoteSources:                  remoteSources,
 		RedisDisconnectDelaySeconds:    int64(to.Duration(config.RedisDisconnectDelay).Seconds()),
 		LastMetricReceivedDelaySeconds: int64(to.Duration(config.LastMetricReceivedDelay).Seconds()),
 		LastCheckDelaySeconds:          int64(to.Duration(config.LastCheckDelay).Seconds()),
@@ -61,6 +61,10 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Can not configure log: %s\n", err.Error())
 		os.Exit(1)
 	}
+
+	if err = cmd.ConfigureSeverities(config.Severities); err != nil {
+		logger.Fatalf("Can not configure severities: %s", err.Error())
+	}
 	defer logger.Infof("Moira Notifier Stopped. Version: %s", MoiraVersion)
 
 	if config.Pprof.Listen != "" {
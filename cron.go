@@ -0,0 +1,138 @@
+package moira
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule is parsed cron expression with fields: minute, hour, day of month, month and day of week.
+// Schedule is evaluated in UTC
+type CronSchedule struct {
+	minutes    uint64
+	hours      uint64
+	days       uint64
+	months     uint64
+	weekdays   uint64
+	anyDay     bool
+	anyWeekday bool
+}
+
+var cronFields = []struct {
+	name string
+	min  int
+	max  int
+}{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day of week", min: 0, max: 7},
+}
+
+// Next occurrence of schedule is searched within this interval
+const cronSearchYears = 5
+
+// ParseCronSchedule parses cron expression. Every field is a list of values, ranges (1-5), steps (*/15, 0-30/10) or *,
+// both 0 and 7 mean Sunday in day of week field
+func ParseCronSchedule(expression string) (*CronSchedule, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("cron expression '%s' must have 5 fields: minute, hour, day of month, month and day of week", expression)
+	}
+	bits := make([]uint64, len(fields))
+	for i, field := range fields {
+		fieldBits, err := parseCronField(field, cronFields[i].min, cronFields[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s in cron expression '%s': %s", cronFields[i].name, expression, err.Error())
+		}
+		bits[i] = fieldBits
+	}
+	weekdays := bits[4]
+	if weekdays&(1<<7) != 0 {
+		weekdays |= 1
+	}
+	return &CronSchedule{
+		minutes:    bits[0],
+		hours:      bits[1],
+		days:       bits[2],
+		months:     bits[3],
+		weekdays:   weekdays,
+		anyDay:     fields[2] == "*",
+		anyWeekday: fields[4] == "*",
+	}, nil
+}
+
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step '%s'", part[i+1:])
+			}
+			rangePart = part[:i]
+		}
+		from, to := min, max
+		if rangePart != "*" {
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if from, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid value '%s'", bounds[0])
+			}
+			switch {
+			case len(bounds) == 2:
+				if to, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("invalid value '%s'", bounds[1])
+				}
+			case step == 1:
+				to = from
+			}
+		}
+		if from < min || to > max || from > to {
+			return 0, fmt.Errorf("'%s' is out of range %d-%d", rangePart, min, max)
+		}
+		for value := from; value <= to; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, nil
+}
+
+// Next returns the first time after timestamp which matches schedule, 0 is returned if there is no such time in 5 years
+func (schedule *CronSchedule) Next(timestamp int64) int64 {
+	next := time.Unix(timestamp, 0).UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := next.AddDate(cronSearchYears, 0, 0)
+	for next.Before(limit) {
+		if schedule.months&(1<<uint(next.Month())) == 0 {
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !schedule.matchesDay(next) {
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if schedule.hours&(1<<uint(next.Hour())) == 0 {
+			next = next.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+		if schedule.minutes&(1<<uint(next.Minute())) == 0 {
+			next = next.Add(time.Minute)
+			continue
+		}
+		return next.Unix()
+	}
+	return 0
+}
+
+// matchesDay checks day of month and day of week like cron does: if both fields are restricted, any of them should match
+func (schedule *CronSchedule) matchesDay(date time.Time) bool {
+	dayMatches := schedule.days&(1<<uint(date.Day())) != 0
+	weekdayMatches := schedule.weekdays&(1<<uint(date.Weekday())) != 0
+	if schedule.anyDay || schedule.anyWeekday {
+		return dayMatches && weekdayMatches
+	}
+	return dayMatches || weekdayMatches
+}
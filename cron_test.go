@@ -0,0 +1,77 @@
+package moira
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseCronSchedule(t *testing.T) {
+	Convey("Valid expressions", t, func() {
+		for _, expression := range []string{"* * * * *", "0 3 * * *", "*/15 9-18 * * 1-5", "0,30 0 1,15 */2 7", "5/10 * * * *"} {
+			_, err := ParseCronSchedule(expression)
+			So(err, ShouldBeNil)
+		}
+	})
+
+	Convey("Invalid expressions", t, func() {
+		_, err := ParseCronSchedule("0 3 * *")
+		So(err, ShouldResemble, fmt.Errorf("cron expression '0 3 * *' must have 5 fields: minute, hour, day of month, month and day of week"))
+
+		_, err = ParseCronSchedule("60 3 * * *")
+		So(err, ShouldResemble, fmt.Errorf("invalid minute in cron expression '60 3 * * *': '60' is out of range 0-59"))
+
+		_, err = ParseCronSchedule("0 3 0 * *")
+		So(err, ShouldResemble, fmt.Errorf("invalid day of month in cron expression '0 3 0 * *': '0' is out of range 1-31"))
+
+		_, err = ParseCronSchedule("0 18-9 * * *")
+		So(err, ShouldResemble, fmt.Errorf("invalid hour in cron expression '0 18-9 * * *': '18-9' is out of range 0-23"))
+
+		_, err = ParseCronSchedule("*/0 * * * *")
+		So(err, ShouldResemble, fmt.Errorf("invalid minute in cron expression '*/0 * * * *': invalid step '0'"))
+
+		_, err = ParseCronSchedule("0 3 * * mon")
+		So(err, ShouldResemble, fmt.Errorf("invalid day of week in cron expression '0 3 * * mon': invalid value 'mon'"))
+	})
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	// Wednesday, 2019-01-02 10:20:30 UTC
+	now := time.Date(2019, 1, 2, 10, 20, 30, 0, time.UTC).Unix()
+	next := func(expression string, timestamp int64) time.Time {
+		schedule, err := ParseCronSchedule(expression)
+		So(err, ShouldBeNil)
+		return time.Unix(schedule.Next(timestamp), 0).UTC()
+	}
+
+	Convey("Every minute", t, func() {
+		So(next("* * * * *", now), ShouldEqual, time.Date(2019, 1, 2, 10, 21, 0, 0, time.UTC))
+	})
+
+	Convey("Every day", t, func() {
+		So(next("0 3 * * *", now), ShouldEqual, time.Date(2019, 1, 3, 3, 0, 0, 0, time.UTC))
+		So(next("30 10 * * *", now), ShouldEqual, time.Date(2019, 1, 2, 10, 30, 0, 0, time.UTC))
+	})
+
+	Convey("Steps and ranges", t, func() {
+		So(next("*/15 9-18 * * *", now), ShouldEqual, time.Date(2019, 1, 2, 10, 30, 0, 0, time.UTC))
+		So(next("*/15 9-10 * * *", time.Date(2019, 1, 2, 10, 45, 0, 0, time.UTC).Unix()), ShouldEqual, time.Date(2019, 1, 3, 9, 0, 0, 0, time.UTC))
+	})
+
+	Convey("Day of week", t, func() {
+		So(next("0 0 * * 6", now), ShouldEqual, time.Date(2019, 1, 5, 0, 0, 0, 0, time.UTC))
+		So(next("0 0 * * 7", now), ShouldEqual, time.Date(2019, 1, 6, 0, 0, 0, 0, time.UTC))
+	})
+
+	Convey("Day of month or day of week", t, func() {
+		So(next("0 0 10 * 5", now), ShouldEqual, time.Date(2019, 1, 4, 0, 0, 0, 0, time.UTC))
+		So(next("0 0 3 * 5", now), ShouldEqual, time.Date(2019, 1, 3, 0, 0, 0, 0, time.UTC))
+	})
+
+	Convey("Month", t, func() {
+		So(next("0 0 1 3 *", now), ShouldEqual, time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC))
+		So(next("0 0 31 2 *", now).Unix(), ShouldEqual, 0)
+	})
+}
@@ -0,0 +1,108 @@
+package redis
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+// SetCheckerHeartbeat saves current time as the last heartbeat of checker instance
+func (connector *DbConnector) SetCheckerHeartbeat(instanceID string) error {
+	c := connector.pool.Get()
+	defer c.Close()
+	if _, err := c.Do("ZADD", checkerInstancesKey, time.Now().Unix(), instanceID); err != nil {
+		return fmt.Errorf("failed to save checker instance %s heartbeat: %s", instanceID, err.Error())
+	}
+	return nil
+}
+
+// GetCheckerInstances returns last heartbeat timestamps of all registered checker instances
+func (connector *DbConnector) GetCheckerInstances() (map[string]int64, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+	values, err := redis.Strings(c.Do("ZRANGE", checkerInstancesKey, 0, -1, "WITHSCORES"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checker instances: %s", err.Error())
+	}
+	instances := make(map[string]int64, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		heartbeat, err := strconv.ParseInt(values[i+1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse checker instance %s heartbeat: %s", values[i], err.Error())
+		}
+		instances[values[i]] = heartbeat
+	}
+	return instances, nil
+}
+
+// RemoveCheckerInstance unregisters checker instance and hands its not yet checked triggers over
+// to the common triggers to check queues, so they are checked by other instances
+func (connector *DbConnector) RemoveCheckerInstance(instanceID string) error {
+	c := connector.pool.Get()
+	defer c.Close()
+	c.Send("MULTI")
+	c.Send("ZREM", checkerInstancesKey, instanceID)
+	for _, priority := range moira.CheckPriorities {
+		c.Send("ZUNIONSTORE", triggersToCheckKey(priority), 2, triggersToCheckKey(priority), shardTriggersToCheckKey(instanceID, priority), "AGGREGATE", "MIN")
+		c.Send("DEL", shardTriggersToCheckKey(instanceID, priority))
+		c.Send("ZUNIONSTORE", remoteTriggersToCheckKey(priority), 2, remoteTriggersToCheckKey(priority), shardRemoteTriggersToCheckKey(instanceID, priority), "AGGREGATE", "MIN")
+		c.Send("DEL", shardRemoteTriggersToCheckKey(instanceID, priority))
+	}
+	if _, err := c.Do("EXEC"); err != nil {
+		return fmt.Errorf("failed to remove checker instance %s: %s", instanceID, err.Error())
+	}
+	return nil
+}
+
+// AddShardTriggersToCheck gets trigger IDs owned by checker instance and save it to instance Redis Sorted Set of given priority
+func (connector *DbConnector) AddShardTriggersToCheck(instanceID string, triggerIDs []string, priority moira.TriggerCheckPriority) error {
+	if err := connector.addTriggersToCheck(shardTriggersToCheckKey(instanceID, priority), triggerIDs); err != nil {
+		return fmt.Errorf("failed to add triggers to check of checker instance %s: %s", instanceID, err.Error())
+	}
+	return nil
+}
+
+// GetShardTriggerToCheck return the earliest added trigger ID from checker instance Redis Sorted Set of the highest non-empty priority
+func (connector *DbConnector) GetShardTriggerToCheck(instanceID string) (moira.TriggerToCheck, error) {
+	triggerToCheck, err := connector.popTriggerToCheck(func(priority moira.TriggerCheckPriority) string {
+		return shardTriggersToCheckKey(instanceID, priority)
+	})
+	if err != nil && err != database.ErrNil {
+		return triggerToCheck, fmt.Errorf("failed to pop trigger to check of checker instance %s: %s", instanceID, err.Error())
+	}
+	return triggerToCheck, err
+}
+
+// AddShardRemoteTriggersToCheck gets remote trigger IDs owned by checker instance and save it to instance Redis Sorted Set of given priority
+func (connector *DbConnector) AddShardRemoteTriggersToCheck(instanceID string, triggerIDs []string, priority moira.TriggerCheckPriority) error {
+	if err := connector.addTriggersToCheck(shardRemoteTriggersToCheckKey(instanceID, priority), triggerIDs); err != nil {
+		return fmt.Errorf("failed to add remote triggers to check of checker instance %s: %s", instanceID, err.Error())
+	}
+	return nil
+}
+
+// GetShardRemoteTriggerToCheck return the earliest added remote trigger ID from checker instance Redis Sorted Set of the highest non-empty priority
+func (connector *DbConnector) GetShardRemoteTriggerToCheck(instanceID string) (moira.TriggerToCheck, error) {
+	triggerToCheck, err := connector.popTriggerToCheck(func(priority moira.TriggerCheckPriority) string {
+		return shardRemoteTriggersToCheckKey(instanceID, priority)
+	})
+	if err != nil && err != database.ErrNil {
+		return triggerToCheck, fmt.Errorf("failed to pop remote trigger to check of checker instance %s: %s", instanceID, err.Error())
+	}
+	return triggerToCheck, err
+}
+
+const checkerInstancesKey = "moira-checker-instances"
+
+func shardTriggersToCheckKey(instanceID string, priority moira.TriggerCheckPriority) string {
+	return fmt.Sprintf("moira-shard-triggers-to-check:%s:%s", instanceID, priority)
+}
+
+func shardRemoteTriggersToCheckKey(instanceID string, priority moira.TriggerCheckPriority) string {
+	return fmt.Sprintf("moira-shard-remote-triggers-to-check:%s:%s", instanceID, priority)
+}
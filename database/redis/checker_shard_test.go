@@ -0,0 +1,120 @@
+package redis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira/logging/go-logging"
+)
+
+func TestCheckerShard(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "info", "test")
+	dataBase := NewDatabase(logger, config)
+	dataBase.flush()
+	defer dataBase.flush()
+
+	Convey("Checker instances heartbeats", t, func() {
+		instances, err := dataBase.GetCheckerInstances()
+		So(err, ShouldBeNil)
+		So(instances, ShouldBeEmpty)
+
+		now := time.Now().Unix()
+		So(dataBase.SetCheckerHeartbeat("checker-1"), ShouldBeNil)
+		So(dataBase.SetCheckerHeartbeat("checker-2"), ShouldBeNil)
+
+		instances, err = dataBase.GetCheckerInstances()
+		So(err, ShouldBeNil)
+		So(instances, ShouldHaveLength, 2)
+		So(instances["checker-1"], ShouldBeGreaterThanOrEqualTo, now)
+		So(instances["checker-2"], ShouldBeGreaterThanOrEqualTo, now)
+
+		So(dataBase.RemoveCheckerInstance("checker-2"), ShouldBeNil)
+		instances, err = dataBase.GetCheckerInstances()
+		So(err, ShouldBeNil)
+		So(instances, ShouldHaveLength, 1)
+		So(instances, ShouldContainKey, "checker-1")
+	})
+
+	Convey("Shard triggers to check", t, func() {
+		_, err := dataBase.GetShardTriggerToCheck("checker-1")
+		So(err, ShouldResemble, database.ErrNil)
+
+		So(dataBase.AddShardTriggersToCheck("checker-1", []string{"trigger-1"}, moira.NormalCheckPriority), ShouldBeNil)
+		So(dataBase.AddShardTriggersToCheck("checker-1", []string{"trigger-2"}, moira.HighCheckPriority), ShouldBeNil)
+
+		Convey("should not be shared with other instances", func() {
+			_, err := dataBase.GetShardTriggerToCheck("checker-2")
+			So(err, ShouldResemble, database.ErrNil)
+			_, err = dataBase.GetTriggerToCheck()
+			So(err, ShouldResemble, database.ErrNil)
+
+			triggerToCheck, err := dataBase.GetShardTriggerToCheck("checker-1")
+			So(err, ShouldBeNil)
+			So(triggerToCheck.TriggerID, ShouldEqual, "trigger-2")
+			So(triggerToCheck.Priority, ShouldEqual, moira.HighCheckPriority)
+			triggerToCheck, err = dataBase.GetShardTriggerToCheck("checker-1")
+			So(err, ShouldBeNil)
+			So(triggerToCheck.TriggerID, ShouldEqual, "trigger-1")
+			_, err = dataBase.GetShardTriggerToCheck("checker-1")
+			So(err, ShouldResemble, database.ErrNil)
+		})
+
+		Convey("should be handed over to common queue on instance removal", func() {
+			So(dataBase.AddTriggersToCheck([]string{"trigger-3"}, moira.NormalCheckPriority), ShouldBeNil)
+			So(dataBase.RemoveCheckerInstance("checker-1"), ShouldBeNil)
+
+			_, err := dataBase.GetShardTriggerToCheck("checker-1")
+			So(err, ShouldResemble, database.ErrNil)
+			count, err := dataBase.GetTriggersToCheckCount()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 3)
+
+			triggerToCheck, err := dataBase.GetTriggerToCheck()
+			So(err, ShouldBeNil)
+			So(triggerToCheck.TriggerID, ShouldEqual, "trigger-2")
+			triggerToCheck, err = dataBase.GetTriggerToCheck()
+			So(err, ShouldBeNil)
+			So(triggerToCheck.TriggerID, ShouldEqual, "trigger-1")
+			triggerToCheck, err = dataBase.GetTriggerToCheck()
+			So(err, ShouldBeNil)
+			So(triggerToCheck.TriggerID, ShouldEqual, "trigger-3")
+		})
+	})
+
+	Convey("Shard remote triggers to check should be handed over to common remote queue", t, func() {
+		So(dataBase.AddShardRemoteTriggersToCheck("checker-1", []string{"trigger-1"}, moira.NormalCheckPriority), ShouldBeNil)
+		_, err := dataBase.GetShardTriggerToCheck("checker-1")
+		So(err, ShouldResemble, database.ErrNil)
+
+		So(dataBase.RemoveCheckerInstance("checker-1"), ShouldBeNil)
+		_, err = dataBase.GetShardRemoteTriggerToCheck("checker-1")
+		So(err, ShouldResemble, database.ErrNil)
+		triggerToCheck, err := dataBase.GetRemoteTriggerToCheck()
+		So(err, ShouldBeNil)
+		So(triggerToCheck.TriggerID, ShouldEqual, "trigger-1")
+	})
+}
+
+func TestCheckerShardConnection(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "info", "test")
+	dataBase := NewDatabase(logger, emptyConfig)
+	dataBase.flush()
+	defer dataBase.flush()
+	Convey("Should throw error when no connection", t, func() {
+		So(dataBase.SetCheckerHeartbeat("checker-1"), ShouldNotBeNil)
+
+		instances, err := dataBase.GetCheckerInstances()
+		So(instances, ShouldBeNil)
+		So(err, ShouldNotBeNil)
+
+		So(dataBase.RemoveCheckerInstance("checker-1"), ShouldNotBeNil)
+		So(dataBase.AddShardTriggersToCheck("checker-1", []string{"123"}, moira.NormalCheckPriority), ShouldNotBeNil)
+
+		_, err = dataBase.GetShardTriggerToCheck("checker-1")
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -154,23 +154,38 @@ func (connector *DbConnector) makePubSubConnection(channel string) (*redis.PubSu
 	return &psc, nil
 }
 
+// manageSubscriptions subscribes to channel and returns channel of its messages, subscription is reconnected on network errors.
+// Returned channel is closed when tomb is dying
 func (connector *DbConnector) manageSubscriptions(tomb *tomb.Tomb, channel string) (<-chan []byte, error) {
 	psc, err := connector.makePubSubConnection(channel)
 	if err != nil {
 		return nil, err
 	}
+	// pscLock guards psc, which is replaced by receiving goroutine on reconnect and unsubscribed by shutdown goroutine
+	var pscLock sync.Mutex
 
 	go func() {
 		<-tomb.Dying()
 		connector.logger.Infof("Calling shutdown, unsubscribe from '%s' redis channels...", channel)
+		pscLock.Lock()
 		psc.Unsubscribe()
+		pscLock.Unlock()
 	}()
 
 	dataChan := make(chan []byte, pubSubWorkerChannelSize)
 	go func() {
-		defer psc.Close()
+		defer close(dataChan)
+		defer func() {
+			pscLock.Lock()
+			psc.Close()
+			pscLock.Unlock()
+		}()
 		for {
-			switch n := psc.Receive().(type) {
+			pscLock.Lock()
+			currentPsc := psc
+			pscLock.Unlock()
+
+			switch n := currentPsc.Receive().(type) {
 			case redis.Message:
 				if len(n.Data) == 0 {
 					continue
@@ -184,21 +199,33 @@ func (connector *DbConnector) manageSubscriptions(tomb *tomb.Tomb, channel strin
 					connector.logger.Infof("Unsubscribe from %s channel, current subscriptions is %v", n.Channel, n.Count)
 					if n.Count == 0 {
 						connector.logger.Infof("No more subscriptions, exit...")
-						close(dataChan)
 						return
 					}
 				}
 			case *net.OpError:
+				if isDying(tomb) {
+					return
+				}
 				connector.logger.Infof("psc.Receive() returned *net.OpError: %s. Reconnecting...", n.Err.Error())
-				newPsc, err := connector.makePubSubConnection(metricEventKey)
+				newPsc, err := connector.makePubSubConnection(channel)
 				if err != nil {
 					connector.logger.Errorf("Failed to reconnect to subscription: %v", err)
 					<-time.After(receiveErrorSleepDuration)
 					continue
 				}
+				pscLock.Lock()
+				currentPsc.Close()
 				psc = newPsc
+				pscLock.Unlock()
+				// Shutdown goroutine could unsubscribe the broken connection, so new one is not waited for unsubscribe
+				if isDying(tomb) {
+					return
+				}
 				<-time.After(receiveErrorSleepDuration)
 			default:
+				if isDying(tomb) {
+					return
+				}
 				connector.logger.Errorf("Can not receive message of type '%T': %v", n, n)
 				<-time.After(receiveErrorSleepDuration)
 			}
@@ -207,6 +234,15 @@ func (connector *DbConnector) manageSubscriptions(tomb *tomb.Tomb, channel strin
 	return dataChan, nil
 }
 
+func isDying(tomb *tomb.Tomb) bool {
+	select {
+	case <-tomb.Dying():
+		return true
+	default:
+		return false
+	}
+}
+
 // CLEAN DATABASE! USE IT ONLY FOR TESTING!!!
 func (connector *DbConnector) flush() {
 	c := connector.pool.Get()
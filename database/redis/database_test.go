@@ -2,9 +2,11 @@ package redis
 
 import (
 	"testing"
+	"time"
 
 	"github.com/moira-alert/moira/logging/go-logging"
 	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/tomb.v2"
 )
 
 func TestInitialization(t *testing.T) {
@@ -16,3 +18,48 @@ func TestInitialization(t *testing.T) {
 		So(err, ShouldNotBeNil)
 	})
 }
+
+func TestManageSubscriptions(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "info", "test")
+	dataBase := NewDatabase(logger, config)
+
+	Convey("Subscription channel is closed when tomb is dying", t, func() {
+		Convey("Subscription connection is alive", func() {
+			var subscription tomb.Tomb
+			dataChan, err := dataBase.manageSubscriptions(&subscription, "test-subscription")
+			So(err, ShouldBeNil)
+
+			subscription.Kill(nil)
+			So(isChannelClosed(dataChan), ShouldBeTrue)
+		})
+
+		Convey("Subscription connection is broken", func() {
+			var subscription tomb.Tomb
+			dataChan, err := dataBase.manageSubscriptions(&subscription, "test-subscription")
+			So(err, ShouldBeNil)
+
+			c := dataBase.pool.Get()
+			_, err = c.Do("CLIENT", "KILL", "TYPE", "pubsub")
+			c.Close()
+			So(err, ShouldBeNil)
+
+			subscription.Kill(nil)
+			So(isChannelClosed(dataChan), ShouldBeTrue)
+		})
+	})
+}
+
+// isChannelClosed drains channel and checks that it is closed in 5 seconds
+func isChannelClosed(dataChan <-chan []byte) bool {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case _, ok := <-dataChan:
+			if !ok {
+				return true
+			}
+		case <-timeout:
+			return false
+		}
+	}
+}
@@ -15,13 +15,30 @@ import (
 func (connector *DbConnector) GetTriggerLastCheck(triggerID string) (moira.CheckData, error) {
 	c := connector.pool.Get()
 	defer c.Close()
-	return reply.Check(c.Do("GET", metricLastCheckKey(triggerID)))
+	c.Send("MULTI")
+	c.Send("GET", metricLastCheckKey(triggerID))
+	c.Send("HGETALL", triggerAcknowledgementsKey(triggerID))
+	rawResponse, err := redis.Values(c.Do("EXEC"))
+	if err != nil {
+		return moira.CheckData{}, fmt.Errorf("Failed to EXEC: %s", err.Error())
+	}
+	lastCheck, err := reply.Check(rawResponse[0], nil)
+	if err != nil {
+		return lastCheck, err
+	}
+	acknowledgements, err := reply.Acknowledgements(rawResponse[1], nil)
+	if err != nil {
+		return lastCheck, err
+	}
+	applyAcknowledgements(&lastCheck, acknowledgements)
+	return lastCheck, nil
 }
 
 // SetTriggerLastCheck sets trigger last check data
-func (connector *DbConnector) SetTriggerLastCheck(triggerID string, checkData *moira.CheckData, isRemote bool) error {
-	if isRemote {
-		return connector.setTriggerLastCheckAndUpdateProperCounter(triggerID, checkData, selfStateRemoteChecksCounterKey)
+// Remote triggers checks are counted separately for every remote source
+func (connector *DbConnector) SetTriggerLastCheck(triggerID string, checkData *moira.CheckData, remoteSource string) error {
+	if remoteSource != "" {
+		return connector.setTriggerLastCheckAndUpdateProperCounter(triggerID, checkData, selfStateRemoteChecksCounterKey(remoteSource))
 	}
 	return connector.setTriggerLastCheckAndUpdateProperCounter(triggerID, checkData, selfStateChecksCounterKey)
 }
@@ -49,12 +66,32 @@ func (connector *DbConnector) setTriggerLastCheckAndUpdateProperCounter(triggerI
 	return nil
 }
 
+// applyAcknowledgements sets stored acknowledgements to trigger and metrics of check which are still in acknowledged states
+func applyAcknowledgements(lastCheck *moira.CheckData, acknowledgements map[string]*moira.Acknowledgement) {
+	lastCheck.Acknowledgement = getStateAcknowledgement(acknowledgements[""], lastCheck.State, lastCheck.GetStateTimestamp())
+	for metric, metricState := range lastCheck.Metrics {
+		metricState.Acknowledgement = getStateAcknowledgement(acknowledgements[metric], metricState.State, metricState.GetStateTimestamp())
+		lastCheck.Metrics[metric] = metricState
+	}
+}
+
+// getStateAcknowledgement returns acknowledgement if it is made after switching to the current state,
+// so acknowledgement of the state is not restored when the state returns later
+func getStateAcknowledgement(acknowledgement *moira.Acknowledgement, state string, stateTimestamp int64) *moira.Acknowledgement {
+	acknowledgement = moira.GetAcknowledgement(acknowledgement, state)
+	if acknowledgement == nil || acknowledgement.Timestamp < stateTimestamp {
+		return nil
+	}
+	return acknowledgement
+}
+
 // RemoveTriggerLastCheck removes trigger last check data
 func (connector *DbConnector) RemoveTriggerLastCheck(triggerID string) error {
 	c := connector.pool.Get()
 	defer c.Close()
 	c.Send("MULTI")
 	c.Send("DEL", metricLastCheckKey(triggerID))
+	c.Send("DEL", triggerAcknowledgementsKey(triggerID))
 	c.Send("ZREM", triggersChecksKey, triggerID)
 	c.Send("SREM", badStateTriggersKey, triggerID)
 	_, err := c.Do("EXEC")
@@ -68,20 +105,7 @@ func (connector *DbConnector) RemoveTriggerLastCheck(triggerID string) error {
 // If during the update lastCheck was updated from another place, try update again
 // If CheckData does not contain one of given metrics it will ignore this metric
 func (connector *DbConnector) SetTriggerCheckMetricsMaintenance(triggerID string, metrics map[string]int64) error {
-	c := connector.pool.Get()
-	defer c.Close()
-	var readingErr error
-
-	lastCheckString, readingErr := redis.String(c.Do("GET", metricLastCheckKey(triggerID)))
-	if readingErr != nil && readingErr != redis.ErrNil {
-		return readingErr
-	}
-	for readingErr != redis.ErrNil {
-		var lastCheck = moira.CheckData{}
-		err := json.Unmarshal([]byte(lastCheckString), &lastCheck)
-		if err != nil {
-			return fmt.Errorf("Failed to parse lastCheck json %s: %s", lastCheckString, err.Error())
-		}
+	return connector.updateTriggerLastCheck(triggerID, func(lastCheck *moira.CheckData) {
 		metricsCheck := lastCheck.Metrics
 		if len(metricsCheck) > 0 {
 			for metric, value := range metrics {
@@ -93,22 +117,87 @@ func (connector *DbConnector) SetTriggerCheckMetricsMaintenance(triggerID string
 				metricsCheck[metric] = data
 			}
 		}
+	})
+}
+
+// SetTriggerMaintenance sets maintenance timestamp of the whole trigger, events of trigger and all its metrics are suppressed until it
+// If during the update lastCheck was updated from another place, try update again
+func (connector *DbConnector) SetTriggerMaintenance(triggerID string, maintenance int64) error {
+	return connector.updateTriggerLastCheck(triggerID, func(lastCheck *moira.CheckData) {
+		lastCheck.Maintenance = maintenance
+	})
+}
+
+// SetTriggerCheckAcknowledgement sets acknowledgement to given metric or to the whole trigger if metric is empty,
+// nil acknowledgement removes existing one
+// If CheckData does not contain given metric or its state is not the acknowledged one, database.ErrNil is returned
+func (connector *DbConnector) SetTriggerCheckAcknowledgement(triggerID string, metric string, acknowledgement *moira.Acknowledgement) error {
+	c := connector.pool.Get()
+	defer c.Close()
+	if acknowledgement == nil {
+		if _, err := c.Do("HDEL", triggerAcknowledgementsKey(triggerID), metric); err != nil {
+			return fmt.Errorf("Failed to HDEL: %s", err.Error())
+		}
+		return nil
+	}
+	lastCheck, err := reply.Check(c.Do("GET", metricLastCheckKey(triggerID)))
+	if err != nil {
+		return err
+	}
+	state := lastCheck.State
+	if metric != "" {
+		metricState, ok := lastCheck.Metrics[metric]
+		if !ok {
+			return database.ErrNil
+		}
+		state = metricState.State
+	}
+	if acknowledgement.State != state {
+		return database.ErrNil
+	}
+	bytes, err := json.Marshal(acknowledgement)
+	if err != nil {
+		return err
+	}
+	if _, err := c.Do("HSET", triggerAcknowledgementsKey(triggerID), metric, bytes); err != nil {
+		return fmt.Errorf("Failed to HSET: %s", err.Error())
+	}
+	return nil
+}
+
+// updateTriggerLastCheck applies update to lastCheck if it exists
+// If during the update lastCheck was updated from another place, transaction fails and update is applied again
+func (connector *DbConnector) updateTriggerLastCheck(triggerID string, update func(lastCheck *moira.CheckData)) error {
+	c := connector.pool.Get()
+	defer c.Close()
+	for {
+		if _, err := c.Do("WATCH", metricLastCheckKey(triggerID)); err != nil {
+			return err
+		}
+		lastCheck, err := reply.Check(c.Do("GET", metricLastCheckKey(triggerID)))
+		if err != nil {
+			c.Do("UNWATCH")
+			if err == database.ErrNil {
+				return nil
+			}
+			return err
+		}
+		update(&lastCheck)
 		newLastCheck, err := json.Marshal(lastCheck)
 		if err != nil {
+			c.Do("UNWATCH")
 			return err
 		}
-
-		var prev string
-		prev, readingErr = redis.String(c.Do("GETSET", metricLastCheckKey(triggerID), newLastCheck))
-		if readingErr != nil && readingErr != redis.ErrNil {
-			return readingErr
+		c.Send("MULTI")
+		c.Send("SET", metricLastCheckKey(triggerID), newLastCheck)
+		result, err := c.Do("EXEC")
+		if err != nil {
+			return fmt.Errorf("Failed to EXEC: %s", err.Error())
 		}
-		if prev == lastCheckString {
-			break
+		if result != nil {
+			return nil
 		}
-		lastCheckString = prev
 	}
-	return nil
 }
 
 // GetTriggerCheckIDs gets checked triggerIDs, sorted from max to min check score and filtered by given tags
@@ -172,3 +261,7 @@ var triggersChecksKey = "moira-triggers-checks"
 func metricLastCheckKey(triggerID string) string {
 	return fmt.Sprintf("moira-metric-last-check:%s", triggerID)
 }
+
+func triggerAcknowledgementsKey(triggerID string) string {
+	return fmt.Sprintf("moira-trigger-acknowledgements:%s", triggerID)
+}
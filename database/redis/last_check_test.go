@@ -20,7 +20,7 @@ func TestLastCheck(t *testing.T) {
 	Convey("LastCheck manipulation", t, func() {
 		Convey("Test read write delete", func() {
 			triggerID := uuid.NewV4().String()
-			err := dataBase.SetTriggerLastCheck(triggerID, &lastCheckTest, false)
+			err := dataBase.SetTriggerLastCheck(triggerID, &lastCheckTest, "")
 			So(err, ShouldBeNil)
 
 			actual, err := dataBase.GetTriggerLastCheck(triggerID)
@@ -52,7 +52,7 @@ func TestLastCheck(t *testing.T) {
 
 			Convey("While no metrics", func() {
 				triggerID := uuid.NewV4().String()
-				err := dataBase.SetTriggerLastCheck(triggerID, &lastCheckWithNoMetrics, false)
+				err := dataBase.SetTriggerLastCheck(triggerID, &lastCheckWithNoMetrics, "")
 				So(err, ShouldBeNil)
 
 				err = dataBase.SetTriggerCheckMetricsMaintenance(triggerID, map[string]int64{"metric1": 1, "metric5": 5})
@@ -65,7 +65,7 @@ func TestLastCheck(t *testing.T) {
 
 			Convey("While no metrics to change", func() {
 				triggerID := uuid.NewV4().String()
-				err := dataBase.SetTriggerLastCheck(triggerID, &lastCheckTest, false)
+				err := dataBase.SetTriggerLastCheck(triggerID, &lastCheckTest, "")
 				So(err, ShouldBeNil)
 
 				err = dataBase.SetTriggerCheckMetricsMaintenance(triggerID, map[string]int64{"metric11": 1, "metric55": 5})
@@ -79,7 +79,7 @@ func TestLastCheck(t *testing.T) {
 			Convey("Has metrics to change", func() {
 				checkData := lastCheckTest
 				triggerID := uuid.NewV4().String()
-				err := dataBase.SetTriggerLastCheck(triggerID, &checkData, false)
+				err := dataBase.SetTriggerLastCheck(triggerID, &checkData, "")
 				So(err, ShouldBeNil)
 
 				err = dataBase.SetTriggerCheckMetricsMaintenance(triggerID, map[string]int64{"metric1": 1, "metric5": 5})
@@ -95,15 +95,153 @@ func TestLastCheck(t *testing.T) {
 				So(err, ShouldBeNil)
 				So(actual, ShouldResemble, checkData)
 			})
+
+			Convey("Set trigger maintenance", func() {
+				checkData := lastCheckWithNoMetrics
+				triggerID := uuid.NewV4().String()
+				err := dataBase.SetTriggerLastCheck(triggerID, &checkData, "")
+				So(err, ShouldBeNil)
+
+				triggerMaintenance := int64(1000)
+				err = dataBase.SetTriggerMaintenance(triggerID, triggerMaintenance)
+				So(err, ShouldBeNil)
+				checkData.Maintenance = 1000
+
+				actual, err := dataBase.GetTriggerLastCheck(triggerID)
+				So(err, ShouldBeNil)
+				So(actual, ShouldResemble, checkData)
+			})
+		})
+
+		Convey("Test set trigger check acknowledgement", func() {
+			acknowledgement := &moira.Acknowledgement{User: "user", State: "ERROR", Timestamp: 1504509981}
+
+			Convey("While no check", func() {
+				triggerID := uuid.NewV4().String()
+				err := dataBase.SetTriggerCheckAcknowledgement(triggerID, "", acknowledgement)
+				So(err, ShouldResemble, database.ErrNil)
+				err = dataBase.SetTriggerCheckAcknowledgement(triggerID, "", nil)
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Set and remove trigger and metric acknowledgement", func() {
+				checkData := lastCheckTest
+				checkData.State = "ERROR"
+				checkData.Metrics = make(map[string]moira.MetricState, len(lastCheckTest.Metrics))
+				for metric, state := range lastCheckTest.Metrics {
+					checkData.Metrics[metric] = state
+				}
+				metricState := checkData.Metrics["metric1"]
+				metricState.State = "ERROR"
+				checkData.Metrics["metric1"] = metricState
+				triggerID := uuid.NewV4().String()
+				err := dataBase.SetTriggerLastCheck(triggerID, &checkData, "")
+				So(err, ShouldBeNil)
+
+				err = dataBase.SetTriggerCheckAcknowledgement(triggerID, "", acknowledgement)
+				So(err, ShouldBeNil)
+				err = dataBase.SetTriggerCheckAcknowledgement(triggerID, "metric1", acknowledgement)
+				So(err, ShouldBeNil)
+				err = dataBase.SetTriggerCheckAcknowledgement(triggerID, "metric11", acknowledgement)
+				So(err, ShouldResemble, database.ErrNil)
+
+				actual, err := dataBase.GetTriggerLastCheck(triggerID)
+				So(err, ShouldBeNil)
+				So(actual.Acknowledgement, ShouldResemble, acknowledgement)
+				So(actual.Metrics["metric1"].Acknowledgement, ShouldResemble, acknowledgement)
+				So(actual.Metrics, ShouldNotContainKey, "metric11")
+
+				err = dataBase.SetTriggerCheckAcknowledgement(triggerID, "", nil)
+				So(err, ShouldBeNil)
+				err = dataBase.SetTriggerCheckAcknowledgement(triggerID, "metric1", nil)
+				So(err, ShouldBeNil)
+
+				actual, err = dataBase.GetTriggerLastCheck(triggerID)
+				So(err, ShouldBeNil)
+				So(actual, ShouldResemble, checkData)
+			})
+
+			Convey("Acknowledgement of other state is not set", func() {
+				checkData := lastCheckTest
+				checkData.State = "WARN"
+				triggerID := uuid.NewV4().String()
+				err := dataBase.SetTriggerLastCheck(triggerID, &checkData, "")
+				So(err, ShouldBeNil)
+
+				err = dataBase.SetTriggerCheckAcknowledgement(triggerID, "", acknowledgement)
+				So(err, ShouldResemble, database.ErrNil)
+				err = dataBase.SetTriggerCheckAcknowledgement(triggerID, "metric11", acknowledgement)
+				So(err, ShouldResemble, database.ErrNil)
+
+				actual, err := dataBase.GetTriggerLastCheck(triggerID)
+				So(err, ShouldBeNil)
+				So(actual.Acknowledgement, ShouldBeNil)
+			})
+
+			Convey("Stored acknowledgement is kept by new check while state is not changed", func() {
+				checkData := lastCheckTest
+				checkData.State = "ERROR"
+				checkData.Metrics = map[string]moira.MetricState{"metric1": {State: "ERROR"}}
+				triggerID := uuid.NewV4().String()
+				err := dataBase.SetTriggerLastCheck(triggerID, &checkData, "")
+				So(err, ShouldBeNil)
+				err = dataBase.SetTriggerCheckAcknowledgement(triggerID, "", acknowledgement)
+				So(err, ShouldBeNil)
+				err = dataBase.SetTriggerCheckAcknowledgement(triggerID, "metric1", acknowledgement)
+				So(err, ShouldBeNil)
+
+				newCheckData := checkData
+				newCheckData.Metrics = map[string]moira.MetricState{"metric1": {State: "ERROR"}}
+				err = dataBase.SetTriggerLastCheck(triggerID, &newCheckData, "")
+				So(err, ShouldBeNil)
+				actual, err := dataBase.GetTriggerLastCheck(triggerID)
+				So(err, ShouldBeNil)
+				So(actual.Acknowledgement, ShouldResemble, acknowledgement)
+				So(actual.Metrics["metric1"].Acknowledgement, ShouldResemble, acknowledgement)
+
+				newCheckData.Metrics = map[string]moira.MetricState{"metric1": {State: "OK"}}
+				err = dataBase.SetTriggerLastCheck(triggerID, &newCheckData, "")
+				So(err, ShouldBeNil)
+				actual, err = dataBase.GetTriggerLastCheck(triggerID)
+				So(err, ShouldBeNil)
+				So(actual.Acknowledgement, ShouldResemble, acknowledgement)
+				So(actual.Metrics["metric1"].Acknowledgement, ShouldBeNil)
+
+				newCheckData.Metrics = map[string]moira.MetricState{"metric1": {State: "ERROR", StateTimestamp: acknowledgement.Timestamp + 60}}
+				err = dataBase.SetTriggerLastCheck(triggerID, &newCheckData, "")
+				So(err, ShouldBeNil)
+				actual, err = dataBase.GetTriggerLastCheck(triggerID)
+				So(err, ShouldBeNil)
+				So(actual.Metrics["metric1"].Acknowledgement, ShouldBeNil)
+			})
+
+			Convey("Stored acknowledgements are removed with last check", func() {
+				checkData := lastCheckTest
+				checkData.State = "ERROR"
+				checkData.Metrics = map[string]moira.MetricState{"metric1": {State: "ERROR"}}
+				triggerID := uuid.NewV4().String()
+				err := dataBase.SetTriggerLastCheck(triggerID, &checkData, "")
+				So(err, ShouldBeNil)
+				err = dataBase.SetTriggerCheckAcknowledgement(triggerID, "", acknowledgement)
+				So(err, ShouldBeNil)
+
+				err = dataBase.RemoveTriggerLastCheck(triggerID)
+				So(err, ShouldBeNil)
+				err = dataBase.SetTriggerLastCheck(triggerID, &checkData, "")
+				So(err, ShouldBeNil)
+				actual, err := dataBase.GetTriggerLastCheck(triggerID)
+				So(err, ShouldBeNil)
+				So(actual.Acknowledgement, ShouldBeNil)
+			})
 		})
 
 		Convey("Test get trigger check ids", func() {
 			dataBase.flush()
 			okTriggerID := uuid.NewV4().String()
 			badTriggerID := uuid.NewV4().String()
-			err := dataBase.SetTriggerLastCheck(okTriggerID, &lastCheckWithNoMetrics, false)
+			err := dataBase.SetTriggerLastCheck(okTriggerID, &lastCheckWithNoMetrics, "")
 			So(err, ShouldBeNil)
-			err = dataBase.SetTriggerLastCheck(badTriggerID, &lastCheckTest, false)
+			err = dataBase.SetTriggerLastCheck(badTriggerID, &lastCheckTest, "")
 			So(err, ShouldBeNil)
 
 			actual, err := dataBase.GetTriggerCheckIDs(make([]string, 0), true)
@@ -126,7 +264,7 @@ func TestRemoteLastCheck(t *testing.T) {
 	Convey("LastCheck manipulation", t, func() {
 		Convey("Test read write delete", func() {
 			triggerID := uuid.NewV4().String()
-			err := dataBase.SetTriggerLastCheck(triggerID, &lastCheckTest, true)
+			err := dataBase.SetTriggerLastCheck(triggerID, &lastCheckTest, moira.DefaultRemoteSource)
 			So(err, ShouldBeNil)
 
 			actual, err := dataBase.GetTriggerLastCheck(triggerID)
@@ -158,7 +296,7 @@ func TestRemoteLastCheck(t *testing.T) {
 
 			Convey("While no metrics", func() {
 				triggerID := uuid.NewV4().String()
-				err := dataBase.SetTriggerLastCheck(triggerID, &lastCheckWithNoMetrics, true)
+				err := dataBase.SetTriggerLastCheck(triggerID, &lastCheckWithNoMetrics, moira.DefaultRemoteSource)
 				So(err, ShouldBeNil)
 
 				err = dataBase.SetTriggerCheckMetricsMaintenance(triggerID, map[string]int64{"metric1": 1, "metric5": 5})
@@ -171,7 +309,7 @@ func TestRemoteLastCheck(t *testing.T) {
 
 			Convey("While no metrics to change", func() {
 				triggerID := uuid.NewV4().String()
-				err := dataBase.SetTriggerLastCheck(triggerID, &lastCheckTest, true)
+				err := dataBase.SetTriggerLastCheck(triggerID, &lastCheckTest, moira.DefaultRemoteSource)
 				So(err, ShouldBeNil)
 
 				err = dataBase.SetTriggerCheckMetricsMaintenance(triggerID, map[string]int64{"metric11": 1, "metric55": 5})
@@ -185,7 +323,7 @@ func TestRemoteLastCheck(t *testing.T) {
 			Convey("Has metrics to change", func() {
 				checkData := lastCheckTest
 				triggerID := uuid.NewV4().String()
-				err := dataBase.SetTriggerLastCheck(triggerID, &checkData, true)
+				err := dataBase.SetTriggerLastCheck(triggerID, &checkData, moira.DefaultRemoteSource)
 				So(err, ShouldBeNil)
 
 				err = dataBase.SetTriggerCheckMetricsMaintenance(triggerID, map[string]int64{"metric1": 1, "metric5": 5})
@@ -207,9 +345,9 @@ func TestRemoteLastCheck(t *testing.T) {
 			dataBase.flush()
 			okTriggerID := uuid.NewV4().String()
 			badTriggerID := uuid.NewV4().String()
-			err := dataBase.SetTriggerLastCheck(okTriggerID, &lastCheckWithNoMetrics, true)
+			err := dataBase.SetTriggerLastCheck(okTriggerID, &lastCheckWithNoMetrics, moira.DefaultRemoteSource)
 			So(err, ShouldBeNil)
-			err = dataBase.SetTriggerLastCheck(badTriggerID, &lastCheckTest, true)
+			err = dataBase.SetTriggerLastCheck(badTriggerID, &lastCheckTest, moira.DefaultRemoteSource)
 			So(err, ShouldBeNil)
 
 			actual, err := dataBase.GetTriggerCheckIDs(make([]string, 0), true)
@@ -233,7 +371,7 @@ func TestLastCheckErrorConnection(t *testing.T) {
 		So(actual1, ShouldResemble, moira.CheckData{})
 		So(err, ShouldNotBeNil)
 
-		err = dataBase.SetTriggerLastCheck("123", &lastCheckTest, false)
+		err = dataBase.SetTriggerLastCheck("123", &lastCheckTest, "")
 		So(err, ShouldNotBeNil)
 
 		err = dataBase.RemoveTriggerLastCheck("123")
@@ -242,6 +380,12 @@ func TestLastCheckErrorConnection(t *testing.T) {
 		err = dataBase.SetTriggerCheckMetricsMaintenance("123", map[string]int64{})
 		So(err, ShouldNotBeNil)
 
+		err = dataBase.SetTriggerMaintenance("123", 1000)
+		So(err, ShouldNotBeNil)
+
+		err = dataBase.SetTriggerCheckAcknowledgement("123", "", nil)
+		So(err, ShouldNotBeNil)
+
 		actual2, err := dataBase.GetTriggerCheckIDs(make([]string, 0), true)
 		So(actual2, ShouldResemble, []string(nil))
 		So(err, ShouldNotBeNil)
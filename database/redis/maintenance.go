@@ -0,0 +1,110 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+// GetMaintenanceWindow returns maintenance window by given id, if no value, return database.ErrNil error
+func (connector *DbConnector) GetMaintenanceWindow(windowID string) (moira.MaintenanceWindow, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+
+	window := moira.MaintenanceWindow{}
+	value, err := redis.Bytes(c.Do("HGET", maintenanceWindowsKey, windowID))
+	if err != nil {
+		if err == redis.ErrNil {
+			return window, database.ErrNil
+		}
+		return window, fmt.Errorf("failed to get maintenance window %s: %s", windowID, err.Error())
+	}
+	if err := json.Unmarshal(value, &window); err != nil {
+		return window, fmt.Errorf("failed to parse maintenance window json %s: %s", string(value), err.Error())
+	}
+	return window, nil
+}
+
+// GetMaintenanceWindows returns maintenance windows which have occurrences ending after given time, ordered by expiration
+func (connector *DbConnector) GetMaintenanceWindows(from int64) ([]*moira.MaintenanceWindow, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+
+	windowIDs, err := redis.Values(c.Do("ZRANGEBYSCORE", maintenanceWindowsExpirationKey, fmt.Sprintf("(%d", from), "+inf"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance windows: %s", err.Error())
+	}
+	windows := make([]*moira.MaintenanceWindow, 0, len(windowIDs))
+	if len(windowIDs) == 0 {
+		return windows, nil
+	}
+	values, err := redis.Values(c.Do("HMGET", append([]interface{}{maintenanceWindowsKey}, windowIDs...)...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance windows: %s", err.Error())
+	}
+	for _, value := range values {
+		if value == nil {
+			continue
+		}
+		window := &moira.MaintenanceWindow{}
+		if err := json.Unmarshal(value.([]byte), window); err != nil {
+			return nil, fmt.Errorf("failed to parse maintenance window json %s: %s", value, err.Error())
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+// SaveMaintenanceWindow writes maintenance window and removes windows which have expired
+func (connector *DbConnector) SaveMaintenanceWindow(window *moira.MaintenanceWindow) error {
+	windowBytes, err := json.Marshal(window)
+	if err != nil {
+		return err
+	}
+	c := connector.pool.Get()
+	defer c.Close()
+
+	expiredIDs, err := redis.Values(c.Do("ZRANGEBYSCORE", maintenanceWindowsExpirationKey, "-inf", fmt.Sprintf("(%d", time.Now().Unix())))
+	if err != nil {
+		return fmt.Errorf("failed to get expired maintenance windows: %s", err.Error())
+	}
+
+	var expiration interface{} = "+inf"
+	if windowExpiration := window.GetExpiration(); windowExpiration != 0 {
+		expiration = windowExpiration
+	}
+
+	c.Send("MULTI")
+	if len(expiredIDs) > 0 {
+		c.Send("HDEL", append([]interface{}{maintenanceWindowsKey}, expiredIDs...)...)
+		c.Send("ZREM", append([]interface{}{maintenanceWindowsExpirationKey}, expiredIDs...)...)
+	}
+	c.Send("HSET", maintenanceWindowsKey, window.ID, windowBytes)
+	c.Send("ZADD", maintenanceWindowsExpirationKey, expiration, window.ID)
+	if _, err := c.Do("EXEC"); err != nil {
+		return fmt.Errorf("failed to EXEC: %s", err.Error())
+	}
+	return nil
+}
+
+// RemoveMaintenanceWindow deletes maintenance window
+func (connector *DbConnector) RemoveMaintenanceWindow(windowID string) error {
+	c := connector.pool.Get()
+	defer c.Close()
+
+	c.Send("MULTI")
+	c.Send("HDEL", maintenanceWindowsKey, windowID)
+	c.Send("ZREM", maintenanceWindowsExpirationKey, windowID)
+	if _, err := c.Do("EXEC"); err != nil {
+		return fmt.Errorf("failed to EXEC: %s", err.Error())
+	}
+	return nil
+}
+
+var maintenanceWindowsKey = "moira-maintenance-windows"
+var maintenanceWindowsExpirationKey = "moira-maintenance-windows-expiration"
@@ -0,0 +1,100 @@
+package redis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira/logging/go-logging"
+)
+
+func TestMaintenanceWindows(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "info", "test")
+	dataBase := NewDatabase(logger, config)
+	dataBase.flush()
+	defer dataBase.flush()
+
+	now := time.Now().Unix()
+	window1 := &moira.MaintenanceWindow{
+		ID:     "window1",
+		Start:  now - 60,
+		End:    now + 3600,
+		Scope:  moira.MaintenanceScope{TriggerID: "trigger1"},
+		Author: "user",
+		Reason: "deploy",
+	}
+	window2 := &moira.MaintenanceWindow{
+		ID:         "window2",
+		Start:      now - 7200,
+		End:        now - 3600,
+		Recurrence: "0 3 * * *",
+		Scope:      moira.MaintenanceScope{Tags: []string{"db"}, Metric: "db.*.replication"},
+		Author:     "user",
+		Reason:     "nightly backup",
+	}
+	expiredWindow := &moira.MaintenanceWindow{
+		ID:    "expired",
+		Start: now - 7200,
+		End:   now - 3600,
+		Scope: moira.MaintenanceScope{TriggerID: "trigger1"},
+	}
+
+	Convey("Empty maintenance windows", t, func() {
+		windows, err := dataBase.GetMaintenanceWindows(now)
+		So(err, ShouldBeNil)
+		So(windows, ShouldBeEmpty)
+
+		_, err = dataBase.GetMaintenanceWindow(window1.ID)
+		So(err, ShouldResemble, database.ErrNil)
+	})
+
+	Convey("Save and get maintenance windows", t, func() {
+		So(dataBase.SaveMaintenanceWindow(expiredWindow), ShouldBeNil)
+		So(dataBase.SaveMaintenanceWindow(window1), ShouldBeNil)
+		So(dataBase.SaveMaintenanceWindow(window2), ShouldBeNil)
+
+		window, err := dataBase.GetMaintenanceWindow(window1.ID)
+		So(err, ShouldBeNil)
+		So(window, ShouldResemble, *window1)
+
+		windows, err := dataBase.GetMaintenanceWindows(now)
+		So(err, ShouldBeNil)
+		So(windows, ShouldResemble, []*moira.MaintenanceWindow{window1, window2})
+
+		Convey("Expired windows are removed", func() {
+			_, err := dataBase.GetMaintenanceWindow(expiredWindow.ID)
+			So(err, ShouldResemble, database.ErrNil)
+		})
+
+		Convey("Remove maintenance window", func() {
+			So(dataBase.RemoveMaintenanceWindow(window1.ID), ShouldBeNil)
+			windows, err := dataBase.GetMaintenanceWindows(now)
+			So(err, ShouldBeNil)
+			So(windows, ShouldResemble, []*moira.MaintenanceWindow{window2})
+		})
+	})
+}
+
+func TestMaintenanceWindowsConnection(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "info", "test")
+	dataBase := NewDatabase(logger, emptyConfig)
+	dataBase.flush()
+	defer dataBase.flush()
+	Convey("Should throw error when no connection", t, func() {
+		_, err := dataBase.GetMaintenanceWindow("123")
+		So(err, ShouldNotBeNil)
+
+		windows, err := dataBase.GetMaintenanceWindows(0)
+		So(windows, ShouldBeNil)
+		So(err, ShouldNotBeNil)
+
+		err = dataBase.SaveMaintenanceWindow(&moira.MaintenanceWindow{ID: "123"})
+		So(err, ShouldNotBeNil)
+
+		err = dataBase.RemoveMaintenanceWindow("123")
+		So(err, ShouldNotBeNil)
+	})
+}
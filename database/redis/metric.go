@@ -249,7 +249,32 @@ func (connector *DbConnector) needRemoveMetrics(metric string) bool {
 	return err == nil
 }
 
+// SetMetricsTTL saves time to live of metrics values in seconds, it is set by checker and used by other services
+func (connector *DbConnector) SetMetricsTTL(ttl int64) error {
+	c := connector.pool.Get()
+	defer c.Close()
+	if _, err := c.Do("SET", metricsTTLKey, ttl); err != nil {
+		return fmt.Errorf("Failed to set metrics ttl: %v", err)
+	}
+	return nil
+}
+
+// GetMetricsTTL gets time to live of metrics values in seconds, database.ErrNil is returned if checker has not set it yet
+func (connector *DbConnector) GetMetricsTTL() (int64, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+	ttl, err := redis.Int64(c.Do("GET", metricsTTLKey))
+	if err != nil {
+		if err == redis.ErrNil {
+			return 0, database.ErrNil
+		}
+		return 0, fmt.Errorf("Failed to get metrics ttl: %v", err)
+	}
+	return ttl, nil
+}
+
 var patternsListKey = "moira-pattern-list"
+var metricsTTLKey = "moira-metrics-ttl"
 var metricEventKey = "metric-event"
 
 func patternMetricsKey(pattern string) string {
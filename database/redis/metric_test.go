@@ -10,6 +10,7 @@ import (
 	"gopkg.in/tomb.v2"
 
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
 )
 
 func TestMetricsStoring(t *testing.T) {
@@ -400,6 +401,25 @@ func TestMetricSubscription(t *testing.T) {
 	})
 }
 
+func TestMetricsTTL(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewDatabase(logger, config)
+	dataBase.flush()
+	defer dataBase.flush()
+	Convey("Metrics ttl is not set until checker saves it", t, func() {
+		ttl, err := dataBase.GetMetricsTTL()
+		So(err, ShouldResemble, database.ErrNil)
+		So(ttl, ShouldEqual, 0)
+
+		err = dataBase.SetMetricsTTL(3600)
+		So(err, ShouldBeNil)
+
+		ttl, err = dataBase.GetMetricsTTL()
+		So(err, ShouldBeNil)
+		So(ttl, ShouldEqual, 3600)
+	})
+}
+
 func TestMetricsStoringErrorConnection(t *testing.T) {
 	logger, _ := logging.GetLogger("dataBase")
 	dataBase := NewDatabase(logger, emptyConfig)
@@ -421,6 +441,13 @@ func TestMetricsStoringErrorConnection(t *testing.T) {
 		So(actual2, ShouldEqual, 0)
 		So(err, ShouldNotBeNil)
 
+		err = dataBase.SetMetricsTTL(3600)
+		So(err, ShouldNotBeNil)
+
+		actual3, err := dataBase.GetMetricsTTL()
+		So(actual3, ShouldEqual, 0)
+		So(err, ShouldNotBeNil)
+
 		err = dataBase.AddPatternMetric("123", "123234")
 		So(err, ShouldNotBeNil)
 
@@ -37,12 +37,24 @@ func (connector *DbConnector) GetNotifications(start, end int64) ([]*moira.Sched
 	return notifications, total, nil
 }
 
+// GetTriggerEscalations gets scheduled escalation notifications of trigger alerts ordered by time of sending
+func (connector *DbConnector) GetTriggerEscalations(triggerID string) ([]*moira.ScheduledNotification, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+	return reply.Notifications(c.Do("ZRANGE", triggerEscalationsKey(triggerID), 0, -1))
+}
+
 // RemoveAllNotifications delete all notifications
 func (connector *DbConnector) RemoveAllNotifications() error {
 	c := connector.pool.Get()
 	defer c.Close()
 
-	if _, err := c.Do("DEL", notifierNotificationsKey); err != nil {
+	escalationsKeys, err := redis.Strings(c.Do("KEYS", triggerEscalationsKey("*")))
+	if err != nil {
+		return fmt.Errorf("failed to get trigger escalations keys: %s", err.Error())
+	}
+	keys := append([]interface{}{notifierNotificationsKey}, redis.Args{}.AddFlat(escalationsKeys)...)
+	if _, err := c.Do("DEL", keys...); err != nil {
 		return fmt.Errorf("failed to remove %s: %s", notifierNotificationsKey, err.Error())
 	}
 
@@ -61,6 +73,8 @@ func (connector *DbConnector) RemoveNotification(notificationKey string) (int64,
 
 	c.Send("MULTI")
 
+	// Replies of notifications removal are counted, replies of trigger escalations removal are not
+	countedReplies := make([]bool, 0)
 	for _, notification := range notifications {
 		timestamp := strconv.FormatInt(notification.Timestamp, 10)
 		contactID := notification.Contact.ID
@@ -72,6 +86,11 @@ func (connector *DbConnector) RemoveNotification(notificationKey string) (int64,
 				return 0, err2
 			}
 			c.Send("ZREM", notifierNotificationsKey, notificationString)
+			countedReplies = append(countedReplies, true)
+			if notification.EscalationStep > 0 {
+				c.Send("ZREM", triggerEscalationsKey(notification.Event.TriggerID), notificationString)
+				countedReplies = append(countedReplies, false)
+			}
 		}
 	}
 	response, err := redis.Ints(c.Do("EXEC"))
@@ -79,8 +98,10 @@ func (connector *DbConnector) RemoveNotification(notificationKey string) (int64,
 		return 0, fmt.Errorf("Failed to remove notifier-notification: %s", err.Error())
 	}
 	total := 0
-	for _, val := range response {
-		total += val
+	for i, val := range response {
+		if countedReplies[i] {
+			total += val
+		}
 	}
 	return int64(total), nil
 }
@@ -100,7 +121,27 @@ func (connector *DbConnector) FetchNotifications(to int64) ([]*moira.ScheduledNo
 	if len(response) == 0 {
 		return make([]*moira.ScheduledNotification, 0), nil
 	}
-	return reply.Notifications(response[0], nil)
+	notifications, err := reply.Notifications(response[0], nil)
+	if err != nil {
+		return nil, err
+	}
+
+	escalationTriggerIDs := make(map[string]bool)
+	for _, notification := range notifications {
+		if notification.EscalationStep > 0 {
+			escalationTriggerIDs[notification.Event.TriggerID] = true
+		}
+	}
+	if len(escalationTriggerIDs) > 0 {
+		c.Send("MULTI")
+		for triggerID := range escalationTriggerIDs {
+			c.Send("ZREMRANGEBYSCORE", triggerEscalationsKey(triggerID), "-inf", to)
+		}
+		if _, err := c.Do("EXEC"); err != nil {
+			return nil, fmt.Errorf("Failed to remove fetched trigger escalations: %s", err.Error())
+		}
+	}
+	return notifications, nil
 }
 
 // AddNotification store notification at given timestamp
@@ -111,7 +152,12 @@ func (connector *DbConnector) AddNotification(notification *moira.ScheduledNotif
 	}
 	c := connector.pool.Get()
 	defer c.Close()
-	_, err = c.Do("ZADD", notifierNotificationsKey, notification.Timestamp, bytes)
+	c.Send("MULTI")
+	c.Send("ZADD", notifierNotificationsKey, notification.Timestamp, bytes)
+	if notification.EscalationStep > 0 {
+		c.Send("ZADD", triggerEscalationsKey(notification.Event.TriggerID), notification.Timestamp, bytes)
+	}
+	_, err = c.Do("EXEC")
 	if err != nil {
 		return fmt.Errorf("Failed to add scheduled notification: %s, error: %s", string(bytes), err.Error())
 	}
@@ -129,6 +175,9 @@ func (connector *DbConnector) AddNotifications(notifications []*moira.ScheduledN
 			return err
 		}
 		c.Send("ZADD", notifierNotificationsKey, timestamp, bytes)
+		if notification.EscalationStep > 0 {
+			c.Send("ZADD", triggerEscalationsKey(notification.Event.TriggerID), timestamp, bytes)
+		}
 	}
 	_, err := c.Do("EXEC")
 	if err != nil {
@@ -138,3 +187,7 @@ func (connector *DbConnector) AddNotifications(notifications []*moira.ScheduledN
 }
 
 var notifierNotificationsKey = "moira-notifier-notifications"
+
+func triggerEscalationsKey(triggerID string) string {
+	return fmt.Sprintf("moira-trigger-escalations:%s", triggerID)
+}
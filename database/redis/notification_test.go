@@ -160,6 +160,61 @@ func TestScheduledNotification(t *testing.T) {
 			So(err, ShouldBeNil)
 			So(actual, ShouldResemble, []*moira.ScheduledNotification{})
 		})
+
+		Convey("Test trigger escalations", func() {
+			now := time.Now().Unix()
+			id1 := "id1"
+			event := moira.NotificationEvent{TriggerID: "trigger1", SubscriptionID: &id1}
+			notification1 := moira.ScheduledNotification{
+				Contact:   moira.ContactData{ID: id1},
+				Event:     event,
+				Timestamp: now,
+			}
+			escalation1 := moira.ScheduledNotification{
+				Contact:        moira.ContactData{ID: id1},
+				Event:          event,
+				Timestamp:      now + 600,
+				EscalationStep: 1,
+			}
+			escalation2 := moira.ScheduledNotification{
+				Contact:        moira.ContactData{ID: id1},
+				Event:          event,
+				Timestamp:      now + 1200,
+				EscalationStep: 2,
+			}
+			addNotifications(dataBase, []moira.ScheduledNotification{escalation2, notification1, escalation1})
+			actual, err := dataBase.GetTriggerEscalations("trigger1")
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, []*moira.ScheduledNotification{&escalation1, &escalation2})
+
+			actual, err = dataBase.GetTriggerEscalations("trigger2")
+			So(err, ShouldBeNil)
+			So(actual, ShouldBeEmpty)
+
+			total, err := dataBase.RemoveNotification(strings.Join([]string{fmt.Sprintf("%v", now+1200), id1, id1}, ""))
+			So(err, ShouldBeNil)
+			So(total, ShouldEqual, 1)
+
+			actual, err = dataBase.GetTriggerEscalations("trigger1")
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, []*moira.ScheduledNotification{&escalation1})
+
+			actual, err = dataBase.FetchNotifications(now + 600)
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, []*moira.ScheduledNotification{&notification1, &escalation1})
+
+			actual, err = dataBase.GetTriggerEscalations("trigger1")
+			So(err, ShouldBeNil)
+			So(actual, ShouldBeEmpty)
+
+			addNotifications(dataBase, []moira.ScheduledNotification{escalation2})
+			err = dataBase.RemoveAllNotifications()
+			So(err, ShouldBeNil)
+
+			actual, err = dataBase.GetTriggerEscalations("trigger1")
+			So(err, ShouldBeNil)
+			So(actual, ShouldBeEmpty)
+		})
 	})
 }
 
@@ -199,5 +254,9 @@ func TestScheduledNotificationErrorConnection(t *testing.T) {
 
 		err = dataBase.RemoveAllNotifications()
 		So(err, ShouldNotBeNil)
+
+		actual3, err := dataBase.GetTriggerEscalations("trigger1")
+		So(err, ShouldNotBeNil)
+		So(actual3, ShouldBeNil)
 	})
 }
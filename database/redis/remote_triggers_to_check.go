@@ -3,52 +3,36 @@ package redis
 import (
 	"fmt"
 
-	"github.com/garyburd/redigo/redis"
+	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/database"
 )
 
-// AddRemoteTriggersToCheck gets remote trigger IDs and save it to Redis Set
-func (connector *DbConnector) AddRemoteTriggersToCheck(triggerIDs []string) error {
-	c := connector.pool.Get()
-	defer c.Close()
-
-	c.Send("MULTI")
-	for _, triggerID := range triggerIDs {
-		c.Send("SADD", remoteTriggersToCheckKey, triggerID)
-	}
-	_, err := redis.Values(c.Do("EXEC"))
-	if err != nil {
+// AddRemoteTriggersToCheck gets remote trigger IDs and save it to Redis Sorted Set of given priority
+func (connector *DbConnector) AddRemoteTriggersToCheck(triggerIDs []string, priority moira.TriggerCheckPriority) error {
+	if err := connector.addTriggersToCheck(remoteTriggersToCheckKey(priority), triggerIDs); err != nil {
 		return fmt.Errorf("failed to add remote triggers to check: %s", err.Error())
 	}
 	return nil
 }
 
-// GetRemoteTriggerToCheck return random remote trigger ID from Redis Set
-func (connector *DbConnector) GetRemoteTriggerToCheck() (string, error) {
-	c := connector.pool.Get()
-	defer c.Close()
-	triggerID, err := redis.String(c.Do("SPOP", remoteTriggersToCheckKey))
-	if err != nil {
-		if err == redis.ErrNil {
-			return "", database.ErrNil
-		}
-		return "", fmt.Errorf("failed to pop remote trigger to check: %s", err.Error())
+// GetRemoteTriggerToCheck return the earliest added remote trigger ID from Redis Sorted Set of the highest non-empty priority
+func (connector *DbConnector) GetRemoteTriggerToCheck() (moira.TriggerToCheck, error) {
+	triggerToCheck, err := connector.popTriggerToCheck(remoteTriggersToCheckKey)
+	if err != nil && err != database.ErrNil {
+		return triggerToCheck, fmt.Errorf("failed to pop remote trigger to check: %s", err.Error())
 	}
-	return triggerID, err
+	return triggerToCheck, err
 }
 
-// GetRemoteTriggersToCheckCount return number of remote triggers ID to check from Redis Set
+// GetRemoteTriggersToCheckCount return number of remote triggers ID to check from Redis Sorted Sets of all priorities
 func (connector *DbConnector) GetRemoteTriggersToCheckCount() (int64, error) {
-	c := connector.pool.Get()
-	defer c.Close()
-	triggersToCheckCount, err := redis.Int64(c.Do("SCARD", remoteTriggersToCheckKey))
+	triggersToCheckCount, err := connector.getTriggersToCheckCount(remoteTriggersToCheckKey)
 	if err != nil {
-		if err == redis.ErrNil {
-			return 0, nil
-		}
-		return 0, fmt.Errorf("failed to get trigger to check count: %s", err.Error())
+		return 0, fmt.Errorf("failed to get remote trigger to check count: %s", err.Error())
 	}
 	return triggersToCheckCount, nil
 }
 
-var remoteTriggersToCheckKey = "moira-remote-triggers-to-check"
+func remoteTriggersToCheckKey(priority moira.TriggerCheckPriority) string {
+	return fmt.Sprintf("moira-remote-triggers-to-check:%s", priority)
+}
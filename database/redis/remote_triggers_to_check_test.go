@@ -3,6 +3,7 @@ package redis
 import (
 	"testing"
 
+	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/database"
 	"github.com/satori/go.uuid"
 	. "github.com/smartystreets/goconvey/convey"
@@ -22,13 +23,13 @@ func TestRemoteTriggerToCheck(t *testing.T) {
 
 		actual, err := dataBase.GetRemoteTriggerToCheck()
 		So(err, ShouldResemble, database.ErrNil)
-		So(actual, ShouldBeEmpty)
+		So(actual, ShouldResemble, moira.TriggerToCheck{})
 
 		count, err := dataBase.GetRemoteTriggersToCheckCount()
 		So(err, ShouldBeNil)
 		So(count, ShouldEqual, 0)
 
-		err = dataBase.AddRemoteTriggersToCheck([]string{triggerID1})
+		err = dataBase.AddRemoteTriggersToCheck([]string{triggerID1}, moira.NormalCheckPriority)
 		So(err, ShouldBeNil)
 
 		count, err = dataBase.GetRemoteTriggersToCheckCount()
@@ -37,16 +38,16 @@ func TestRemoteTriggerToCheck(t *testing.T) {
 
 		actual, err = dataBase.GetRemoteTriggerToCheck()
 		So(err, ShouldBeNil)
-		So(actual, ShouldResemble, triggerID1)
+		So(actual.TriggerID, ShouldResemble, triggerID1)
 
 		count, err = dataBase.GetRemoteTriggersToCheckCount()
 		So(err, ShouldBeNil)
 		So(count, ShouldEqual, 0)
 
-		err = dataBase.AddRemoteTriggersToCheck([]string{triggerID1})
+		err = dataBase.AddRemoteTriggersToCheck([]string{triggerID1}, moira.NormalCheckPriority)
 		So(err, ShouldBeNil)
 
-		err = dataBase.AddRemoteTriggersToCheck([]string{triggerID1})
+		err = dataBase.AddRemoteTriggersToCheck([]string{triggerID1}, moira.NormalCheckPriority)
 		So(err, ShouldBeNil)
 
 		count, err = dataBase.GetRemoteTriggersToCheckCount()
@@ -55,14 +56,14 @@ func TestRemoteTriggerToCheck(t *testing.T) {
 
 		actual, err = dataBase.GetRemoteTriggerToCheck()
 		So(err, ShouldBeNil)
-		So(actual, ShouldResemble, triggerID1)
+		So(actual.TriggerID, ShouldResemble, triggerID1)
 
 		actual, err = dataBase.GetRemoteTriggerToCheck()
 		So(err, ShouldResemble, database.ErrNil)
-		So(actual, ShouldBeEmpty)
+		So(actual, ShouldResemble, moira.TriggerToCheck{})
 
 		triggerArr := []string{triggerID1, triggerID2, triggerID3}
-		err = dataBase.AddRemoteTriggersToCheck(triggerArr)
+		err = dataBase.AddRemoteTriggersToCheck(triggerArr, moira.NormalCheckPriority)
 		So(err, ShouldBeNil)
 
 		count, err = dataBase.GetRemoteTriggersToCheckCount()
@@ -71,21 +72,21 @@ func TestRemoteTriggerToCheck(t *testing.T) {
 
 		actual, err = dataBase.GetRemoteTriggerToCheck()
 		So(err, ShouldBeNil)
-		So(actual, ShouldBeIn, triggerArr)
-		triggerArr = removeValue(triggerArr, actual)
+		So(actual.TriggerID, ShouldBeIn, triggerArr)
+		triggerArr = removeValue(triggerArr, actual.TriggerID)
 
 		actual, err = dataBase.GetRemoteTriggerToCheck()
 		So(err, ShouldBeNil)
-		So(actual, ShouldBeIn, triggerArr)
-		triggerArr = removeValue(triggerArr, actual)
+		So(actual.TriggerID, ShouldBeIn, triggerArr)
+		triggerArr = removeValue(triggerArr, actual.TriggerID)
 
 		actual, err = dataBase.GetRemoteTriggerToCheck()
 		So(err, ShouldBeNil)
-		So(actual, ShouldBeIn, triggerArr)
+		So(actual.TriggerID, ShouldBeIn, triggerArr)
 
 		actual, err = dataBase.GetRemoteTriggerToCheck()
 		So(err, ShouldResemble, database.ErrNil)
-		So(actual, ShouldBeEmpty)
+		So(actual, ShouldResemble, moira.TriggerToCheck{})
 
 		count, err = dataBase.GetRemoteTriggersToCheckCount()
 		So(err, ShouldBeNil)
@@ -99,11 +100,11 @@ func TestRemoteTriggerToCheckConnection(t *testing.T) {
 	dataBase.flush()
 	defer dataBase.flush()
 	Convey("Should throw error when no connection", t, func() {
-		err := dataBase.AddRemoteTriggersToCheck([]string{"123"})
+		err := dataBase.AddRemoteTriggersToCheck([]string{"123"}, moira.NormalCheckPriority)
 		So(err, ShouldNotBeNil)
 
 		triggerID, err := dataBase.GetRemoteTriggerToCheck()
-		So(triggerID, ShouldBeEmpty)
+		So(triggerID, ShouldResemble, moira.TriggerToCheck{})
 		So(err, ShouldNotBeNil)
 	})
 }
@@ -0,0 +1,25 @@
+package reply
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/garyburd/redigo/redis"
+	"github.com/moira-alert/moira"
+)
+
+// Acknowledgements converts redis DB reply to map of acknowledgements by metric name, acknowledgement of the whole trigger has empty name
+func Acknowledgements(rep interface{}, err error) (map[string]*moira.Acknowledgement, error) {
+	values, err := redis.StringMap(rep, err)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read acknowledgements: %s", err.Error())
+	}
+	acknowledgements := make(map[string]*moira.Acknowledgement, len(values))
+	for metric, value := range values {
+		acknowledgement := &moira.Acknowledgement{}
+		if err := json.Unmarshal([]byte(value), acknowledgement); err != nil {
+			return nil, fmt.Errorf("Failed to parse acknowledgement json %s: %s", value, err.Error())
+		}
+		acknowledgements[metric] = acknowledgement
+	}
+	return acknowledgements, nil
+}
@@ -27,6 +27,14 @@ type triggerStorageElement struct {
 	Patterns         []string            `json:"patterns"`
 	TTL              string              `json:"ttl,omitempty"`
 	IsRemote         bool                `json:"is_remote"`
+	RemoteSource     string              `json:"remote_source,omitempty"`
+	TargetLanguage   string              `json:"target_language,omitempty"`
+	Aggregation      *moira.Aggregation  `json:"aggregation,omitempty"`
+	Inputs           []string            `json:"inputs,omitempty"`
+	SLO              *moira.SLO          `json:"slo,omitempty"`
+	CheckInterval    int64               `json:"check_interval,omitempty"`
+	Reminder         *moira.Reminder     `json:"reminder,omitempty"`
+	TTLOverrides     []moira.TTLOverride `json:"ttl_overrides,omitempty"`
 }
 
 func (storageElement *triggerStorageElement) toTrigger() moira.Trigger {
@@ -46,6 +54,14 @@ func (storageElement *triggerStorageElement) toTrigger() moira.Trigger {
 		Patterns:         storageElement.Patterns,
 		TTL:              getTriggerTTL(storageElement.TTL),
 		IsRemote:         storageElement.IsRemote,
+		RemoteSource:     storageElement.RemoteSource,
+		TargetLanguage:   storageElement.TargetLanguage,
+		Aggregation:      storageElement.Aggregation,
+		Inputs:           storageElement.Inputs,
+		SLO:              storageElement.SLO,
+		CheckInterval:    storageElement.CheckInterval,
+		Reminder:         storageElement.Reminder,
+		TTLOverrides:     storageElement.TTLOverrides,
 	}
 }
 
@@ -66,6 +82,14 @@ func toTriggerStorageElement(trigger *moira.Trigger, triggerID string) *triggerS
 		Patterns:         trigger.Patterns,
 		TTL:              getTriggerTTLString(trigger.TTL),
 		IsRemote:         trigger.IsRemote,
+		RemoteSource:     trigger.RemoteSource,
+		TargetLanguage:   trigger.TargetLanguage,
+		Aggregation:      trigger.Aggregation,
+		Inputs:           trigger.Inputs,
+		SLO:              trigger.SLO,
+		CheckInterval:    trigger.CheckInterval,
+		Reminder:         trigger.Reminder,
+		TTLOverrides:     trigger.TTLOverrides,
 	}
 }
 
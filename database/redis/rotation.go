@@ -0,0 +1,117 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/garyburd/redigo/redis"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+// GetRotation returns rotation by given id, if no value, return database.ErrNil error
+func (connector *DbConnector) GetRotation(rotationID string) (moira.Rotation, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+
+	rotation := moira.Rotation{}
+	value, err := redis.Bytes(c.Do("GET", rotationKey(rotationID)))
+	if err != nil {
+		if err == redis.ErrNil {
+			return rotation, database.ErrNil
+		}
+		return rotation, fmt.Errorf("failed to get rotation %s: %s", rotationID, err.Error())
+	}
+	if err := json.Unmarshal(value, &rotation); err != nil {
+		return rotation, fmt.Errorf("failed to parse rotation json %s: %s", string(value), err.Error())
+	}
+	return rotation, nil
+}
+
+// GetUserRotations returns rotations of given user
+func (connector *DbConnector) GetUserRotations(login string) ([]*moira.Rotation, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+
+	rotationIDs, err := redis.Strings(c.Do("SMEMBERS", userRotationsKey(login)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rotations for user login %s: %s", login, err.Error())
+	}
+	rotations := make([]*moira.Rotation, 0, len(rotationIDs))
+	if len(rotationIDs) == 0 {
+		return rotations, nil
+	}
+	keys := make([]interface{}, 0, len(rotationIDs))
+	for _, rotationID := range rotationIDs {
+		keys = append(keys, rotationKey(rotationID))
+	}
+	values, err := redis.Values(c.Do("MGET", keys...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rotations for user login %s: %s", login, err.Error())
+	}
+	for _, value := range values {
+		if value == nil {
+			continue
+		}
+		rotation := &moira.Rotation{}
+		if err := json.Unmarshal(value.([]byte), rotation); err != nil {
+			return nil, fmt.Errorf("failed to parse rotation json %s: %s", value, err.Error())
+		}
+		rotations = append(rotations, rotation)
+	}
+	return rotations, nil
+}
+
+// SaveRotation writes rotation and adds it to user rotations
+func (connector *DbConnector) SaveRotation(rotation *moira.Rotation) error {
+	existing, getRotationErr := connector.GetRotation(rotation.ID)
+	if getRotationErr != nil && getRotationErr != database.ErrNil {
+		return getRotationErr
+	}
+	rotationString, err := json.Marshal(rotation)
+	if err != nil {
+		return err
+	}
+
+	c := connector.pool.Get()
+	defer c.Close()
+
+	c.Send("MULTI")
+	c.Send("SET", rotationKey(rotation.ID), rotationString)
+	if getRotationErr != database.ErrNil && rotation.User != existing.User {
+		c.Send("SREM", userRotationsKey(existing.User), rotation.ID)
+	}
+	c.Send("SADD", userRotationsKey(rotation.User), rotation.ID)
+	if _, err = c.Do("EXEC"); err != nil {
+		return fmt.Errorf("failed to EXEC: %s", err.Error())
+	}
+	return nil
+}
+
+// RemoveRotation deletes rotation and removes it from user rotations
+func (connector *DbConnector) RemoveRotation(rotationID string) error {
+	existing, err := connector.GetRotation(rotationID)
+	if err != nil && err != database.ErrNil {
+		return err
+	}
+
+	c := connector.pool.Get()
+	defer c.Close()
+
+	c.Send("MULTI")
+	c.Send("DEL", rotationKey(rotationID))
+	c.Send("SREM", userRotationsKey(existing.User), rotationID)
+	if _, err = c.Do("EXEC"); err != nil {
+		return fmt.Errorf("failed to EXEC: %s", err.Error())
+	}
+	return nil
+}
+
+func rotationKey(rotationID string) string {
+	return fmt.Sprintf("moira-rotation:%s", rotationID)
+}
+
+func userRotationsKey(login string) string {
+	return fmt.Sprintf("moira-user-rotations:%s", login)
+}
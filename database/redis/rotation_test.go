@@ -0,0 +1,95 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira/logging/go-logging"
+)
+
+func TestRotations(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "info", "test")
+	dataBase := NewDatabase(logger, config)
+	dataBase.flush()
+	defer dataBase.flush()
+
+	rotation := &moira.Rotation{
+		ID:           "rotation1",
+		Name:         "Backend on-call",
+		User:         "user1",
+		Participants: []string{"contact1", "contact2"},
+		Handoff:      1546387200,
+		Length:       604800,
+		Overrides:    []moira.RotationOverride{{Contact: "contact3", Start: 1546387200, End: 1546473600}},
+	}
+
+	Convey("Empty rotations", t, func() {
+		_, err := dataBase.GetRotation(rotation.ID)
+		So(err, ShouldResemble, database.ErrNil)
+
+		rotations, err := dataBase.GetUserRotations(rotation.User)
+		So(err, ShouldBeNil)
+		So(rotations, ShouldBeEmpty)
+	})
+
+	Convey("Save and get rotation", t, func() {
+		So(dataBase.SaveRotation(rotation), ShouldBeNil)
+
+		actual, err := dataBase.GetRotation(rotation.ID)
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, *rotation)
+
+		rotations, err := dataBase.GetUserRotations(rotation.User)
+		So(err, ShouldBeNil)
+		So(rotations, ShouldResemble, []*moira.Rotation{rotation})
+
+		Convey("Change rotation user", func() {
+			changed := *rotation
+			changed.User = "user2"
+			So(dataBase.SaveRotation(&changed), ShouldBeNil)
+
+			rotations, err := dataBase.GetUserRotations(rotation.User)
+			So(err, ShouldBeNil)
+			So(rotations, ShouldBeEmpty)
+
+			rotations, err = dataBase.GetUserRotations(changed.User)
+			So(err, ShouldBeNil)
+			So(rotations, ShouldResemble, []*moira.Rotation{&changed})
+		})
+
+		Convey("Remove rotation", func() {
+			So(dataBase.RemoveRotation(rotation.ID), ShouldBeNil)
+
+			_, err := dataBase.GetRotation(rotation.ID)
+			So(err, ShouldResemble, database.ErrNil)
+
+			rotations, err := dataBase.GetUserRotations(rotation.User)
+			So(err, ShouldBeNil)
+			So(rotations, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestRotationsConnection(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "info", "test")
+	dataBase := NewDatabase(logger, emptyConfig)
+	dataBase.flush()
+	defer dataBase.flush()
+	Convey("Should throw error when no connection", t, func() {
+		_, err := dataBase.GetRotation("123")
+		So(err, ShouldNotBeNil)
+
+		rotations, err := dataBase.GetUserRotations("user")
+		So(rotations, ShouldBeNil)
+		So(err, ShouldNotBeNil)
+
+		err = dataBase.SaveRotation(&moira.Rotation{ID: "123"})
+		So(err, ShouldNotBeNil)
+
+		err = dataBase.RemoveRotation("123")
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -1,7 +1,11 @@
 package redis
 
 import (
+	"fmt"
+
 	"github.com/garyburd/redigo/redis"
+
+	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/notifier/selfstate"
 )
 
@@ -35,17 +39,36 @@ func (connector *DbConnector) GetChecksUpdatesCount() (int64, error) {
 	return ts, err
 }
 
-// GetRemoteChecksUpdatesCount return remote checks count by Moira-Checker
-func (connector *DbConnector) GetRemoteChecksUpdatesCount() (int64, error) {
+// GetRemoteChecksUpdatesCount return remote checks count of given remote source by Moira-Checker
+func (connector *DbConnector) GetRemoteChecksUpdatesCount(remoteSource string) (int64, error) {
 	c := connector.pool.Get()
 	defer c.Close()
-	ts, err := redis.Int64(c.Do("GET", selfStateRemoteChecksCounterKey))
+	ts, err := redis.Int64(c.Do("GET", selfStateRemoteChecksCounterKey(remoteSource)))
 	if err == redis.ErrNil {
 		return 0, nil
 	}
 	return ts, err
 }
 
+// SetRemoteBreakerState saves remote source circuit breaker state reported by Moira-Checker
+func (connector *DbConnector) SetRemoteBreakerState(remoteSource string, state string) error {
+	c := connector.pool.Get()
+	defer c.Close()
+	_, err := c.Do("SET", selfStateRemoteBreakerStateKey(remoteSource), state)
+	return err
+}
+
+// GetRemoteBreakerState return remote source circuit breaker state, empty string is returned if it was never reported
+func (connector *DbConnector) GetRemoteBreakerState(remoteSource string) (string, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+	state, err := redis.String(c.Do("GET", selfStateRemoteBreakerStateKey(remoteSource)))
+	if err == redis.ErrNil {
+		return "", nil
+	}
+	return state, err
+}
+
 // GetNotifierState return current notifier state: <OK|ERROR>
 func (connector *DbConnector) GetNotifierState() (string, error) {
 	c := connector.pool.Get()
@@ -70,5 +93,15 @@ func (connector *DbConnector) SetNotifierState(health string) error {
 
 var selfStateMetricsHeartbeatKey = "moira-selfstate:metrics-heartbeat"
 var selfStateChecksCounterKey = "moira-selfstate:checks-counter"
-var selfStateRemoteChecksCounterKey = "moira-selfstate:remote-checks-counter"
 var selfStateNotifierHealth = "moira-selfstate:notifier-health"
+
+func selfStateRemoteChecksCounterKey(remoteSource string) string {
+	if remoteSource == moira.DefaultRemoteSource {
+		return "moira-selfstate:remote-checks-counter"
+	}
+	return fmt.Sprintf("moira-selfstate:remote-checks-counter:%s", remoteSource)
+}
+
+func selfStateRemoteBreakerStateKey(remoteSource string) string {
+	return fmt.Sprintf("moira-selfstate:remote-breaker-state:%s", remoteSource)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/notifier/selfstate"
 	"github.com/op/go-logging"
 	. "github.com/smartystreets/goconvey/convey"
@@ -24,7 +25,7 @@ func TestSelfCheck(t *testing.T) {
 			So(count, ShouldEqual, 0)
 			So(err, ShouldBeNil)
 
-			count, err = dataBase.GetRemoteChecksUpdatesCount()
+			count, err = dataBase.GetRemoteChecksUpdatesCount(moira.DefaultRemoteSource)
 			So(count, ShouldEqual, 0)
 			So(err, ShouldBeNil)
 		})
@@ -39,19 +40,36 @@ func TestSelfCheck(t *testing.T) {
 		})
 
 		Convey("Update metrics checks updates count", func() {
-			err := dataBase.SetTriggerLastCheck("123", &lastCheckTest, false)
+			err := dataBase.SetTriggerLastCheck("123", &lastCheckTest, "")
 			So(err, ShouldBeNil)
 
 			count, err := dataBase.GetChecksUpdatesCount()
 			So(count, ShouldEqual, 1)
 			So(err, ShouldBeNil)
 
-			err = dataBase.SetTriggerLastCheck("12345", &lastCheckTest, true)
+			err = dataBase.SetTriggerLastCheck("12345", &lastCheckTest, moira.DefaultRemoteSource)
 			So(err, ShouldBeNil)
 
-			count, err = dataBase.GetRemoteChecksUpdatesCount()
+			count, err = dataBase.GetRemoteChecksUpdatesCount(moira.DefaultRemoteSource)
 			So(count, ShouldEqual, 1)
 			So(err, ShouldBeNil)
+
+			count, err = dataBase.GetRemoteChecksUpdatesCount("graphite-2")
+			So(count, ShouldEqual, 0)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Update remote breaker state", func() {
+			state, err := dataBase.GetRemoteBreakerState(moira.DefaultRemoteSource)
+			So(state, ShouldBeEmpty)
+			So(err, ShouldBeNil)
+
+			err = dataBase.SetRemoteBreakerState(moira.DefaultRemoteSource, "open")
+			So(err, ShouldBeNil)
+
+			state, err = dataBase.GetRemoteBreakerState(moira.DefaultRemoteSource)
+			So(state, ShouldEqual, "open")
+			So(err, ShouldBeNil)
 		})
 	})
 }
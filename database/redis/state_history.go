@@ -0,0 +1,94 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/garyburd/redigo/redis"
+
+	"github.com/moira-alert/moira"
+)
+
+// stateIntervalPartLength is the max length of stored state interval part. Intervals are scored by end time,
+// so limited length allows to read only intervals ended not later than this length after requested range
+const stateIntervalPartLength int64 = 24 * 60 * 60
+
+// AddTriggerStateHistory saves closed state intervals of trigger and its metrics. Intervals ended more than retention seconds
+// before the latest of given intervals are removed, whole history expires if trigger state doesn't change during retention
+func (connector *DbConnector) AddTriggerStateHistory(triggerID string, intervals []*moira.StateInterval, retention int64) error {
+	if len(intervals) == 0 {
+		return nil
+	}
+	var lastEnd int64
+	c := connector.pool.Get()
+	defer c.Close()
+	c.Send("MULTI")
+	for _, interval := range intervals {
+		for _, part := range splitStateInterval(interval) {
+			partBytes, err := json.Marshal(part)
+			if err != nil {
+				return err
+			}
+			c.Send("ZADD", triggerStateHistoryKey(triggerID), part.End, partBytes)
+		}
+		if interval.End > lastEnd {
+			lastEnd = interval.End
+		}
+	}
+	if retention > 0 {
+		c.Send("ZREMRANGEBYSCORE", triggerStateHistoryKey(triggerID), "-inf", fmt.Sprintf("(%d", lastEnd-retention))
+		c.Send("EXPIRE", triggerStateHistoryKey(triggerID), retention)
+	}
+	if _, err := c.Do("EXEC"); err != nil {
+		return fmt.Errorf("failed to save trigger %s state history: %s", triggerID, err.Error())
+	}
+	return nil
+}
+
+// GetTriggerStateHistory returns closed state intervals of trigger and its metrics which intersect with given time range, ordered by start time
+func (connector *DbConnector) GetTriggerStateHistory(triggerID string, from int64, to int64) ([]*moira.StateInterval, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+	values, err := redis.ByteSlices(c.Do("ZRANGEBYSCORE", triggerStateHistoryKey(triggerID), from, to+stateIntervalPartLength))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trigger %s state history: %s", triggerID, err.Error())
+	}
+	intervals := make([]*moira.StateInterval, 0, len(values))
+	lastIntervals := make(map[string]*moira.StateInterval)
+	for _, value := range values {
+		interval := &moira.StateInterval{}
+		if err := json.Unmarshal(value, interval); err != nil {
+			return nil, fmt.Errorf("failed to parse trigger %s state interval json %s: %s", triggerID, string(value), err.Error())
+		}
+		if interval.Start > to {
+			continue
+		}
+		// Parts of the same interval follow each other, because state intervals of trigger or metric don't overlap
+		if last, ok := lastIntervals[interval.Metric]; ok && last.End == interval.Start && last.State == interval.State {
+			last.End = interval.End
+			continue
+		}
+		lastIntervals[interval.Metric] = interval
+		intervals = append(intervals, interval)
+	}
+	sort.SliceStable(intervals, func(i, j int) bool { return intervals[i].Start < intervals[j].Start })
+	return intervals, nil
+}
+
+// splitStateInterval splits state interval into parts not longer than stateIntervalPartLength
+func splitStateInterval(interval *moira.StateInterval) []*moira.StateInterval {
+	parts := make([]*moira.StateInterval, 0, (interval.End-interval.Start)/stateIntervalPartLength+1)
+	for start := interval.Start; start < interval.End || len(parts) == 0; start += stateIntervalPartLength {
+		end := start + stateIntervalPartLength
+		if end > interval.End {
+			end = interval.End
+		}
+		parts = append(parts, &moira.StateInterval{Metric: interval.Metric, State: interval.State, Start: start, End: end})
+	}
+	return parts
+}
+
+func triggerStateHistoryKey(triggerID string) string {
+	return fmt.Sprintf("moira-trigger-state-history:%s", triggerID)
+}
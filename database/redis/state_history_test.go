@@ -0,0 +1,94 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/moira-alert/moira"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira/logging/go-logging"
+)
+
+func TestTriggerStateHistory(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "info", "test")
+	dataBase := NewDatabase(logger, config)
+	dataBase.flush()
+	defer dataBase.flush()
+
+	triggerID := "trigger-with-history"
+	triggerInterval1 := &moira.StateInterval{State: "OK", Start: 100, End: 200}
+	triggerInterval2 := &moira.StateInterval{State: "ERROR", Start: 200, End: 500}
+	metricInterval := &moira.StateInterval{Metric: "my.metric", State: "WARN", Start: 150, End: 300}
+
+	Convey("Empty history", t, func() {
+		intervals, err := dataBase.GetTriggerStateHistory(triggerID, 0, 1000)
+		So(err, ShouldBeNil)
+		So(intervals, ShouldBeEmpty)
+		So(dataBase.AddTriggerStateHistory(triggerID, nil, 1000), ShouldBeNil)
+	})
+
+	Convey("Save and get history", t, func() {
+		So(dataBase.AddTriggerStateHistory(triggerID, []*moira.StateInterval{triggerInterval1, metricInterval}, 1000), ShouldBeNil)
+		So(dataBase.AddTriggerStateHistory(triggerID, []*moira.StateInterval{triggerInterval2}, 1000), ShouldBeNil)
+
+		intervals, err := dataBase.GetTriggerStateHistory(triggerID, 0, 1000)
+		So(err, ShouldBeNil)
+		So(intervals, ShouldResemble, []*moira.StateInterval{triggerInterval1, metricInterval, triggerInterval2})
+
+		Convey("Only intervals intersecting with range are returned", func() {
+			intervals, err := dataBase.GetTriggerStateHistory(triggerID, 250, 400)
+			So(err, ShouldBeNil)
+			So(intervals, ShouldResemble, []*moira.StateInterval{metricInterval, triggerInterval2})
+
+			intervals, err = dataBase.GetTriggerStateHistory(triggerID, 0, 120)
+			So(err, ShouldBeNil)
+			So(intervals, ShouldResemble, []*moira.StateInterval{triggerInterval1})
+		})
+
+		Convey("Intervals out of retention are removed", func() {
+			triggerInterval3 := &moira.StateInterval{State: "OK", Start: 500, End: 1250}
+			So(dataBase.AddTriggerStateHistory(triggerID, []*moira.StateInterval{triggerInterval3}, 1000), ShouldBeNil)
+			intervals, err := dataBase.GetTriggerStateHistory(triggerID, 0, 2000)
+			So(err, ShouldBeNil)
+			So(intervals, ShouldResemble, []*moira.StateInterval{metricInterval, triggerInterval2, triggerInterval3})
+		})
+
+		Convey("History is removed with trigger", func() {
+			So(dataBase.SaveTrigger(triggerID, &moira.Trigger{ID: triggerID}), ShouldBeNil)
+			So(dataBase.RemoveTrigger(triggerID), ShouldBeNil)
+			intervals, err := dataBase.GetTriggerStateHistory(triggerID, 0, 2000)
+			So(err, ShouldBeNil)
+			So(intervals, ShouldBeEmpty)
+		})
+
+		Convey("Long interval is stored in parts and returned whole", func() {
+			day := int64(24 * 60 * 60)
+			longInterval := &moira.StateInterval{Metric: "my.metric", State: "ERROR", Start: 300, End: 300 + 3*day + 100}
+			So(dataBase.AddTriggerStateHistory(triggerID, []*moira.StateInterval{longInterval}, 10*day), ShouldBeNil)
+
+			intervals, err := dataBase.GetTriggerStateHistory(triggerID, 0, 4*day)
+			So(err, ShouldBeNil)
+			So(intervals, ShouldResemble, []*moira.StateInterval{triggerInterval1, metricInterval, triggerInterval2, longInterval})
+
+			// Only parts intersecting with range are read
+			intervals, err = dataBase.GetTriggerStateHistory(triggerID, day, day+100)
+			So(err, ShouldBeNil)
+			So(intervals, ShouldResemble, []*moira.StateInterval{{Metric: "my.metric", State: "ERROR", Start: 300, End: 300 + day}})
+		})
+	})
+}
+
+func TestTriggerStateHistoryConnection(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "info", "test")
+	dataBase := NewDatabase(logger, emptyConfig)
+	dataBase.flush()
+	defer dataBase.flush()
+	Convey("Should throw error when no connection", t, func() {
+		err := dataBase.AddTriggerStateHistory("123", []*moira.StateInterval{{State: "OK", Start: 1, End: 2}}, 1000)
+		So(err, ShouldNotBeNil)
+
+		intervals, err := dataBase.GetTriggerStateHistory("123", 0, 2)
+		So(intervals, ShouldBeNil)
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,53 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/tomb.v2"
+
+	"github.com/moira-alert/moira"
+)
+
+// PublishStreamEvent publishes trigger state change or notification event to real-time subscribers
+func (connector *DbConnector) PublishStreamEvent(event *moira.StreamEvent) error {
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	c := connector.pool.Get()
+	defer c.Close()
+	if _, err := c.Do("PUBLISH", streamEventKey, eventBytes); err != nil {
+		return fmt.Errorf("failed to publish stream event: %s", err.Error())
+	}
+	return nil
+}
+
+// SubscribeStreamEvents creates subscription for trigger state changes and notification events and return channel for this events
+func (connector *DbConnector) SubscribeStreamEvents(tomb *tomb.Tomb) (<-chan *moira.StreamEvent, error) {
+	eventsChannel := make(chan *moira.StreamEvent, pubSubWorkerChannelSize)
+	dataChannel, err := connector.manageSubscriptions(tomb, streamEventKey)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		for {
+			data, ok := <-dataChannel
+			if !ok {
+				close(eventsChannel)
+				return
+			}
+			event := &moira.StreamEvent{}
+			if err := json.Unmarshal(data, event); err != nil {
+				connector.logger.Errorf("Failed to parse StreamEvent: %s, error : %v", string(data), err)
+				continue
+			}
+			eventsChannel <- event
+		}
+	}()
+
+	return eventsChannel, nil
+}
+
+var streamEventKey = "moira-stream-event"
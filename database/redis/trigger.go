@@ -33,17 +33,38 @@ func (connector *DbConnector) GetTriggerIDs() ([]string, error) {
 	return triggerIds, nil
 }
 
-// GetRemoteTriggerIDs gets moira remote triggerIDs
-func (connector *DbConnector) GetRemoteTriggerIDs() ([]string, error) {
+// GetRemoteTriggerIDs gets moira remote triggerIDs of given remote source
+func (connector *DbConnector) GetRemoteTriggerIDs(remoteSource string) ([]string, error) {
 	c := connector.pool.Get()
 	defer c.Close()
-	triggerIds, err := redis.Strings(c.Do("SMEMBERS", remoteTriggersListKey))
+	var triggerIds []string
+	var err error
+	if remoteSource == moira.DefaultRemoteSource {
+		triggerIds, err = getDefaultRemoteSourceTriggerIDs(c)
+	} else {
+		triggerIds, err = redis.Strings(c.Do("SMEMBERS", remoteSourceTriggersListKey(remoteSource)))
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get remote triggers-list: %s", err.Error())
 	}
 	return triggerIds, nil
 }
 
+// getDefaultRemoteSourceTriggerIDs returns all remote triggers except triggers of named remote sources,
+// so remote triggers saved without remote source are checked by default remote source
+func getDefaultRemoteSourceTriggerIDs(c redis.Conn) ([]string, error) {
+	remoteSources, err := redis.Strings(c.Do("SMEMBERS", remoteSourcesKey))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]interface{}, 0, len(remoteSources)+1)
+	args = append(args, remoteTriggersListKey)
+	for _, remoteSource := range remoteSources {
+		args = append(args, remoteSourceTriggersListKey(remoteSource))
+	}
+	return redis.Strings(c.Do("SDIFF", args...))
+}
+
 // GetTrigger gets trigger and trigger tags by given ID and return it in merged object
 func (connector *DbConnector) GetTrigger(triggerID string) (moira.Trigger, error) {
 	c := connector.pool.Get()
@@ -114,6 +135,18 @@ func (connector *DbConnector) RemovePatternTriggerIDs(pattern string) error {
 	return nil
 }
 
+// GetCompositeTriggerIDs gets composite triggers which use given trigger as input
+func (connector *DbConnector) GetCompositeTriggerIDs(inputTriggerID string) ([]string, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+
+	triggerIds, err := redis.Strings(c.Do("SMEMBERS", compositeTriggersKey(inputTriggerID)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve composite triggers for trigger: %s, error: %s", inputTriggerID, err.Error())
+	}
+	return triggerIds, nil
+}
+
 // SaveTrigger sets trigger data by given trigger and triggerID
 // If trigger already exists, then merge old and new trigger patterns and tags list
 // and cleanup not used tags and patterns from lists
@@ -139,16 +172,26 @@ func (connector *DbConnector) SaveTrigger(triggerID string, trigger *moira.Trigg
 		if existing.IsRemote && !trigger.IsRemote {
 			c.Send("SREM", remoteTriggersListKey, triggerID)
 		}
+		if existingSource := existing.GetRemoteSource(); existingSource != trigger.GetRemoteSource() && isNamedRemoteSource(existingSource) {
+			c.Send("SREM", remoteSourceTriggersListKey(existingSource), triggerID)
+		}
 
 		for _, tag := range leftJoin(existing.Tags, trigger.Tags) {
 			c.Send("SREM", triggerTagsKey(triggerID), tag)
 			c.Send("SREM", tagTriggersKey(tag), triggerID)
 		}
+		for _, inputTriggerID := range leftJoin(existing.Inputs, trigger.Inputs) {
+			c.Send("SREM", compositeTriggersKey(inputTriggerID), triggerID)
+		}
 	}
 	c.Send("SET", triggerKey(triggerID), bytes)
 	c.Send("SADD", triggersListKey, triggerID)
 	if trigger.IsRemote {
 		c.Send("SADD", remoteTriggersListKey, triggerID)
+		if remoteSource := trigger.GetRemoteSource(); isNamedRemoteSource(remoteSource) {
+			c.Send("SADD", remoteSourcesKey, remoteSource)
+			c.Send("SADD", remoteSourceTriggersListKey(remoteSource), triggerID)
+		}
 	} else {
 		for _, pattern := range trigger.Patterns {
 			c.Send("SADD", patternsListKey, pattern)
@@ -160,6 +203,9 @@ func (connector *DbConnector) SaveTrigger(triggerID string, trigger *moira.Trigg
 		c.Send("SADD", tagTriggersKey(tag), triggerID)
 		c.Send("SADD", tagsKey, tag)
 	}
+	for _, inputTriggerID := range trigger.Inputs {
+		c.Send("SADD", compositeTriggersKey(inputTriggerID), triggerID)
+	}
 	_, err = c.Do("EXEC")
 	if err != nil {
 		return fmt.Errorf("failed to EXEC: %s", err.Error())
@@ -196,14 +242,21 @@ func (connector *DbConnector) RemoveTrigger(triggerID string) error {
 	c.Send("MULTI")
 	c.Send("DEL", triggerKey(triggerID))
 	c.Send("DEL", triggerTagsKey(triggerID))
+	c.Send("DEL", triggerStateHistoryKey(triggerID))
 	c.Send("SREM", triggersListKey, triggerID)
 	c.Send("SREM", remoteTriggersListKey, triggerID)
+	if remoteSource := trigger.GetRemoteSource(); isNamedRemoteSource(remoteSource) {
+		c.Send("SREM", remoteSourceTriggersListKey(remoteSource), triggerID)
+	}
 	for _, tag := range trigger.Tags {
 		c.Send("SREM", tagTriggersKey(tag), triggerID)
 	}
 	for _, pattern := range trigger.Patterns {
 		c.Send("SREM", patternTriggersKey(pattern), triggerID)
 	}
+	for _, inputTriggerID := range trigger.Inputs {
+		c.Send("SREM", compositeTriggersKey(inputTriggerID), triggerID)
+	}
 	_, err = c.Do("EXEC")
 	if err != nil {
 		return fmt.Errorf("failed to EXEC: %s", err.Error())
@@ -236,16 +289,17 @@ func (connector *DbConnector) GetTriggerChecks(triggerIDs []string) ([]*moira.Tr
 		c.Send("SMEMBERS", triggerTagsKey(triggerID))
 		c.Send("GET", metricLastCheckKey(triggerID))
 		c.Send("GET", notifierNextKey(triggerID))
+		c.Send("HGETALL", triggerAcknowledgementsKey(triggerID))
 	}
 	rawResponse, err := redis.Values(c.Do("EXEC"))
 	if err != nil {
 		return nil, fmt.Errorf("Failed to EXEC: %s", err)
 	}
 	var slices [][]interface{}
-	for i := 0; i < len(rawResponse); i += 4 {
-		arr := make([]interface{}, 0, 5)
-		arr = append(arr, triggerIDs[i/4])
-		arr = append(arr, rawResponse[i:i+4]...)
+	for i := 0; i < len(rawResponse); i += 5 {
+		arr := make([]interface{}, 0, 6)
+		arr = append(arr, triggerIDs[i/5])
+		arr = append(arr, rawResponse[i:i+5]...)
 		slices = append(slices, arr)
 	}
 	triggerChecks := make([]*moira.TriggerCheck, len(slices))
@@ -262,6 +316,11 @@ func (connector *DbConnector) GetTriggerChecks(triggerIDs []string) ([]*moira.Tr
 		if err != nil && err != database.ErrNil {
 			return nil, err
 		}
+		acknowledgements, err := reply.Acknowledgements(slice[5], nil)
+		if err != nil {
+			return nil, err
+		}
+		applyAcknowledgements(&lastCheck, acknowledgements)
 		throttling, _ := redis.Int64(slice[4], nil)
 		if time.Now().Unix() >= throttling {
 			throttling = 0
@@ -307,6 +366,16 @@ func leftJoin(left, right []string) []string {
 
 var triggersListKey = "moira-triggers-list"
 var remoteTriggersListKey = "moira-remote-triggers-list"
+var remoteSourcesKey = "moira-remote-sources"
+
+// isNamedRemoteSource checks if remote triggers of given source are stored in separate remote source triggers list
+func isNamedRemoteSource(remoteSource string) bool {
+	return remoteSource != "" && remoteSource != moira.DefaultRemoteSource
+}
+
+func remoteSourceTriggersListKey(remoteSource string) string {
+	return fmt.Sprintf("moira-remote-source-triggers-list:%s", remoteSource)
+}
 
 func triggerKey(triggerID string) string {
 	return fmt.Sprintf("moira-trigger:%s", triggerID)
@@ -319,3 +388,7 @@ func triggerTagsKey(triggerID string) string {
 func patternTriggersKey(pattern string) string {
 	return fmt.Sprintf("moira-pattern-triggers:%s", pattern)
 }
+
+func compositeTriggersKey(inputTriggerID string) string {
+	return fmt.Sprintf("moira-composite-triggers:%s", inputTriggerID)
+}
@@ -212,7 +212,7 @@ func TestTriggerStoring(t *testing.T) {
 			So(actualTriggerChecks, ShouldResemble, []*moira.TriggerCheck{triggerCheck})
 
 			//Add check data
-			err = dataBase.SetTriggerLastCheck(trigger.ID, &lastCheckTest, false)
+			err = dataBase.SetTriggerLastCheck(trigger.ID, &lastCheckTest, "")
 			So(err, ShouldBeNil)
 
 			triggerCheck.LastCheck = lastCheckTest
@@ -293,7 +293,7 @@ func TestRemoteTrigger(t *testing.T) {
 			So(ids, ShouldResemble, []string{})
 		})
 		Convey("Trigger should be added to remote triggers collection", func() {
-			ids, err := dataBase.GetRemoteTriggerIDs()
+			ids, err := dataBase.GetRemoteTriggerIDs(moira.DefaultRemoteSource)
 			So(err, ShouldBeNil)
 			So(ids, ShouldResemble, []string{trigger.ID})
 		})
@@ -324,7 +324,7 @@ func TestRemoteTrigger(t *testing.T) {
 			So(ids, ShouldResemble, []string{trigger.ID})
 		})
 		Convey("Trigger shouldn't be added to remote triggers collection", func() {
-			ids, err := dataBase.GetRemoteTriggerIDs()
+			ids, err := dataBase.GetRemoteTriggerIDs(moira.DefaultRemoteSource)
 			So(err, ShouldBeNil)
 			So(ids, ShouldResemble, []string{})
 		})
@@ -341,6 +341,128 @@ func TestRemoteTrigger(t *testing.T) {
 	})
 }
 
+func TestRemoteSourceTrigger(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewDatabase(logger, config)
+	defaultSourceTrigger := &moira.Trigger{
+		ID:          "triggerID-0000000000011",
+		Name:        "remote",
+		Targets:     []string{"test.target.remote1"},
+		IsRemote:    true,
+		TriggerType: moira.RisingTrigger,
+	}
+	trigger := &moira.Trigger{
+		ID:           "triggerID-0000000000012",
+		Name:         "remote-source",
+		Targets:      []string{"test.target.remote2"},
+		IsRemote:     true,
+		RemoteSource: "graphite-2",
+		TriggerType:  moira.RisingTrigger,
+	}
+	dataBase.flush()
+	defer dataBase.flush()
+
+	Convey("Saving triggers of different remote sources", t, func() {
+		err := dataBase.SaveTrigger(defaultSourceTrigger.ID, defaultSourceTrigger)
+		So(err, ShouldBeNil)
+		err = dataBase.SaveTrigger(trigger.ID, trigger)
+		So(err, ShouldBeNil)
+		actual, err := dataBase.GetTrigger(trigger.ID)
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, *trigger)
+
+		ids, err := dataBase.GetRemoteTriggerIDs(moira.DefaultRemoteSource)
+		So(err, ShouldBeNil)
+		So(ids, ShouldResemble, []string{defaultSourceTrigger.ID})
+
+		ids, err = dataBase.GetRemoteTriggerIDs(trigger.RemoteSource)
+		So(err, ShouldBeNil)
+		So(ids, ShouldResemble, []string{trigger.ID})
+	})
+
+	Convey("Resaving trigger with default remote source", t, func() {
+		trigger.RemoteSource = ""
+		err := dataBase.SaveTrigger(trigger.ID, trigger)
+		So(err, ShouldBeNil)
+
+		ids, err := dataBase.GetRemoteTriggerIDs("graphite-2")
+		So(err, ShouldBeNil)
+		So(ids, ShouldResemble, []string{})
+
+		ids, err = dataBase.GetRemoteTriggerIDs(moira.DefaultRemoteSource)
+		So(err, ShouldBeNil)
+		So(ids, ShouldHaveLength, 2)
+	})
+
+	Convey("Removing remote source trigger", t, func() {
+		trigger.RemoteSource = "graphite-2"
+		err := dataBase.SaveTrigger(trigger.ID, trigger)
+		So(err, ShouldBeNil)
+		err = dataBase.RemoveTrigger(trigger.ID)
+		So(err, ShouldBeNil)
+
+		ids, err := dataBase.GetRemoteTriggerIDs(trigger.RemoteSource)
+		So(err, ShouldBeNil)
+		So(ids, ShouldResemble, []string{})
+	})
+}
+
+func TestCompositeTrigger(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := NewDatabase(logger, config)
+	trigger := &moira.Trigger{
+		ID:          "triggerID-0000000000020",
+		Name:        "composite",
+		Tags:        []string{"test-tag-composite"},
+		Inputs:      []string{"triggerID-0000000000021", "triggerID-0000000000022"},
+		TriggerType: moira.CompositeTrigger,
+	}
+	dataBase.flush()
+	defer dataBase.flush()
+
+	Convey("Saving composite trigger", t, func() {
+		err := dataBase.SaveTrigger(trigger.ID, trigger)
+		So(err, ShouldBeNil)
+		actual, err := dataBase.GetTrigger(trigger.ID)
+		So(err, ShouldBeNil)
+		So(actual, ShouldResemble, *trigger)
+
+		Convey("Trigger should be added to its inputs composite triggers", func() {
+			for _, inputTriggerID := range trigger.Inputs {
+				ids, err := dataBase.GetCompositeTriggerIDs(inputTriggerID)
+				So(err, ShouldBeNil)
+				So(ids, ShouldResemble, []string{trigger.ID})
+			}
+		})
+	})
+
+	Convey("Resaving composite trigger with changed inputs", t, func() {
+		trigger.Inputs = []string{"triggerID-0000000000022", "triggerID-0000000000023"}
+		err := dataBase.SaveTrigger(trigger.ID, trigger)
+		So(err, ShouldBeNil)
+
+		ids, err := dataBase.GetCompositeTriggerIDs("triggerID-0000000000021")
+		So(err, ShouldBeNil)
+		So(ids, ShouldResemble, []string{})
+
+		for _, inputTriggerID := range trigger.Inputs {
+			ids, err = dataBase.GetCompositeTriggerIDs(inputTriggerID)
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []string{trigger.ID})
+		}
+	})
+
+	Convey("Removing composite trigger", t, func() {
+		err := dataBase.RemoveTrigger(trigger.ID)
+		So(err, ShouldBeNil)
+		for _, inputTriggerID := range trigger.Inputs {
+			ids, err := dataBase.GetCompositeTriggerIDs(inputTriggerID)
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []string{})
+		}
+	})
+}
+
 func TestTriggerErrorConnection(t *testing.T) {
 	logger, _ := logging.GetLogger("dataBase")
 	dataBase := NewDatabase(logger, emptyConfig)
@@ -375,6 +497,10 @@ func TestTriggerErrorConnection(t *testing.T) {
 
 		err = dataBase.RemovePatternTriggerIDs("")
 		So(err, ShouldNotBeNil)
+
+		actual5, err := dataBase.GetCompositeTriggerIDs("")
+		So(err, ShouldNotBeNil)
+		So(actual5, ShouldBeNil)
 	})
 }
 
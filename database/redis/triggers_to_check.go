@@ -2,53 +2,142 @@ package redis
 
 import (
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/garyburd/redigo/redis"
+
+	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/database"
 )
 
-// AddTriggersToCheck gets trigger IDs and save it to Redis Set
-func (connector *DbConnector) AddTriggersToCheck(triggerIDs []string) error {
+// AddTriggersToCheck gets trigger IDs and save it to Redis Sorted Set of given priority
+// Time when trigger was added is used as score, so it is not updated if trigger is already in the queue
+func (connector *DbConnector) AddTriggersToCheck(triggerIDs []string, priority moira.TriggerCheckPriority) error {
+	if err := connector.addTriggersToCheck(triggersToCheckKey(priority), triggerIDs); err != nil {
+		return fmt.Errorf("failed to add triggers to check: %s", err.Error())
+	}
+	return nil
+}
+
+// GetTriggerToCheck return the earliest added trigger ID from Redis Sorted Set of the highest non-empty priority
+func (connector *DbConnector) GetTriggerToCheck() (moira.TriggerToCheck, error) {
+	triggerToCheck, err := connector.popTriggerToCheck(triggersToCheckKey)
+	if err != nil && err != database.ErrNil {
+		return triggerToCheck, fmt.Errorf("failed to pop trigger to check: %s", err.Error())
+	}
+	return triggerToCheck, err
+}
+
+// GetTriggersToCheckCount return number of triggers ID to check from Redis Sorted Sets of all priorities
+func (connector *DbConnector) GetTriggersToCheckCount() (int64, error) {
+	triggersToCheckCount, err := connector.getTriggersToCheckCount(triggersToCheckKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get trigger to check count: %s", err.Error())
+	}
+	return triggersToCheckCount, nil
+}
+
+// MigrateTriggersToCheck moves trigger IDs from unprioritized Redis Sets of triggers to check, used by previous versions,
+// to Redis Sorted Sets of normal priority, so triggers scheduled before upgrade are checked
+func (connector *DbConnector) MigrateTriggersToCheck() error {
+	if err := connector.migrateTriggersToCheck(legacyTriggersToCheckKey, triggersToCheckKey(moira.NormalCheckPriority)); err != nil {
+		return fmt.Errorf("failed to migrate triggers to check: %s", err.Error())
+	}
+	if err := connector.migrateTriggersToCheck(legacyRemoteTriggersToCheckKey, remoteTriggersToCheckKey(moira.NormalCheckPriority)); err != nil {
+		return fmt.Errorf("failed to migrate remote triggers to check: %s", err.Error())
+	}
+	return nil
+}
+
+func (connector *DbConnector) migrateTriggersToCheck(legacyKey string, key string) error {
 	c := connector.pool.Get()
 	defer c.Close()
 
 	c.Send("MULTI")
-	for _, triggerID := range triggerIDs {
-		c.Send("SADD", triggersToCheckKey, triggerID)
+	c.Send("SMEMBERS", legacyKey)
+	c.Send("DEL", legacyKey)
+	rawResponse, err := redis.Values(c.Do("EXEC"))
+	if err != nil {
+		return err
 	}
-	_, err := redis.Values(c.Do("EXEC"))
+	triggerIDs, err := redis.Strings(rawResponse[0], nil)
 	if err != nil {
-		return fmt.Errorf("failed to add triggers to check: %s", err.Error())
+		return err
 	}
-	return nil
+	if len(triggerIDs) == 0 {
+		return nil
+	}
+	return connector.addTriggersToCheck(key, triggerIDs)
 }
 
-// GetTriggerToCheck return random trigger ID from Redis Set
-func (connector *DbConnector) GetTriggerToCheck() (string, error) {
+func (connector *DbConnector) addTriggersToCheck(key string, triggerIDs []string) error {
 	c := connector.pool.Get()
 	defer c.Close()
-	triggerID, err := redis.String(c.Do("SPOP", triggersToCheckKey))
-	if err != nil {
-		if err == redis.ErrNil {
-			return "", database.ErrNil
+
+	addedAt := time.Now().UnixNano() / int64(time.Millisecond)
+	c.Send("MULTI")
+	for _, triggerID := range triggerIDs {
+		c.Send("ZADD", key, "NX", addedAt, triggerID)
+	}
+	_, err := redis.Values(c.Do("EXEC"))
+	return err
+}
+
+func (connector *DbConnector) popTriggerToCheck(keyFunc func(moira.TriggerCheckPriority) string) (moira.TriggerToCheck, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+
+	for _, priority := range moira.CheckPriorities {
+		c.Send("MULTI")
+		c.Send("ZRANGE", keyFunc(priority), 0, 0, "WITHSCORES")
+		c.Send("ZREMRANGEBYRANK", keyFunc(priority), 0, 0)
+		rawResponse, err := redis.Values(c.Do("EXEC"))
+		if err != nil {
+			return moira.TriggerToCheck{}, err
 		}
-		return "", fmt.Errorf("failed to pop trigger to check: %s", err.Error())
+		values, err := redis.Strings(rawResponse[0], nil)
+		if err != nil {
+			return moira.TriggerToCheck{}, err
+		}
+		if len(values) < 2 {
+			continue
+		}
+		addedAt, err := strconv.ParseFloat(values[1], 64)
+		if err != nil {
+			return moira.TriggerToCheck{}, err
+		}
+		return moira.TriggerToCheck{
+			TriggerID: values[0],
+			Priority:  priority,
+			AddedAt:   int64(addedAt),
+		}, nil
 	}
-	return triggerID, err
+	return moira.TriggerToCheck{}, database.ErrNil
 }
 
-// GetTriggersToCheckCount return number of triggers ID to check from Redis Set
-func (connector *DbConnector) GetTriggersToCheckCount() (int64, error) {
+func (connector *DbConnector) getTriggersToCheckCount(keyFunc func(moira.TriggerCheckPriority) string) (int64, error) {
 	c := connector.pool.Get()
 	defer c.Close()
-	triggersToCheckCount, err := redis.Int64(c.Do("SCARD", triggersToCheckKey))
+
+	c.Send("MULTI")
+	for _, priority := range moira.CheckPriorities {
+		c.Send("ZCARD", keyFunc(priority))
+	}
+	counts, err := redis.Int64s(c.Do("EXEC"))
 	if err != nil {
-		if err == redis.ErrNil {
-			return 0, nil
-		}
-		return 0, fmt.Errorf("failed to get trigger to check count: %s", err.Error())
+		return 0, err
+	}
+	var triggersToCheckCount int64
+	for _, count := range counts {
+		triggersToCheckCount += count
 	}
 	return triggersToCheckCount, nil
 }
 
-var triggersToCheckKey = "moira-triggers-to-check"
+var legacyTriggersToCheckKey = "moira-triggers-to-check"
+var legacyRemoteTriggersToCheckKey = "moira-remote-triggers-to-check"
+
+func triggersToCheckKey(priority moira.TriggerCheckPriority) string {
+	return fmt.Sprintf("moira-triggers-to-check:%s", priority)
+}
@@ -3,6 +3,8 @@ package redis
 import (
 	"testing"
 
+	"github.com/garyburd/redigo/redis"
+	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/database"
 	"github.com/satori/go.uuid"
 	. "github.com/smartystreets/goconvey/convey"
@@ -22,13 +24,13 @@ func TestTriggerToCheck(t *testing.T) {
 
 		actual, err := dataBase.GetTriggerToCheck()
 		So(err, ShouldResemble, database.ErrNil)
-		So(actual, ShouldBeEmpty)
+		So(actual, ShouldResemble, moira.TriggerToCheck{})
 
 		count, err := dataBase.GetTriggersToCheckCount()
 		So(err, ShouldBeNil)
 		So(count, ShouldEqual, 0)
 
-		err = dataBase.AddTriggersToCheck([]string{triggerID1})
+		err = dataBase.AddTriggersToCheck([]string{triggerID1}, moira.NormalCheckPriority)
 		So(err, ShouldBeNil)
 
 		count, err = dataBase.GetTriggersToCheckCount()
@@ -37,16 +39,16 @@ func TestTriggerToCheck(t *testing.T) {
 
 		actual, err = dataBase.GetTriggerToCheck()
 		So(err, ShouldBeNil)
-		So(actual, ShouldResemble, triggerID1)
+		So(actual.TriggerID, ShouldResemble, triggerID1)
 
 		count, err = dataBase.GetTriggersToCheckCount()
 		So(err, ShouldBeNil)
 		So(count, ShouldEqual, 0)
 
-		err = dataBase.AddTriggersToCheck([]string{triggerID1})
+		err = dataBase.AddTriggersToCheck([]string{triggerID1}, moira.NormalCheckPriority)
 		So(err, ShouldBeNil)
 
-		err = dataBase.AddTriggersToCheck([]string{triggerID1})
+		err = dataBase.AddTriggersToCheck([]string{triggerID1}, moira.NormalCheckPriority)
 		So(err, ShouldBeNil)
 
 		count, err = dataBase.GetTriggersToCheckCount()
@@ -55,14 +57,14 @@ func TestTriggerToCheck(t *testing.T) {
 
 		actual, err = dataBase.GetTriggerToCheck()
 		So(err, ShouldBeNil)
-		So(actual, ShouldResemble, triggerID1)
+		So(actual.TriggerID, ShouldResemble, triggerID1)
 
 		actual, err = dataBase.GetTriggerToCheck()
 		So(err, ShouldResemble, database.ErrNil)
-		So(actual, ShouldBeEmpty)
+		So(actual, ShouldResemble, moira.TriggerToCheck{})
 
 		triggerArr := []string{triggerID1, triggerID2, triggerID3}
-		err = dataBase.AddTriggersToCheck(triggerArr)
+		err = dataBase.AddTriggersToCheck(triggerArr, moira.NormalCheckPriority)
 		So(err, ShouldBeNil)
 
 		count, err = dataBase.GetTriggersToCheckCount()
@@ -71,21 +73,21 @@ func TestTriggerToCheck(t *testing.T) {
 
 		actual, err = dataBase.GetTriggerToCheck()
 		So(err, ShouldBeNil)
-		So(actual, ShouldBeIn, triggerArr)
-		triggerArr = removeValue(triggerArr, actual)
+		So(actual.TriggerID, ShouldBeIn, triggerArr)
+		triggerArr = removeValue(triggerArr, actual.TriggerID)
 
 		actual, err = dataBase.GetTriggerToCheck()
 		So(err, ShouldBeNil)
-		So(actual, ShouldBeIn, triggerArr)
-		triggerArr = removeValue(triggerArr, actual)
+		So(actual.TriggerID, ShouldBeIn, triggerArr)
+		triggerArr = removeValue(triggerArr, actual.TriggerID)
 
 		actual, err = dataBase.GetTriggerToCheck()
 		So(err, ShouldBeNil)
-		So(actual, ShouldBeIn, triggerArr)
+		So(actual.TriggerID, ShouldBeIn, triggerArr)
 
 		actual, err = dataBase.GetTriggerToCheck()
 		So(err, ShouldResemble, database.ErrNil)
-		So(actual, ShouldBeEmpty)
+		So(actual, ShouldResemble, moira.TriggerToCheck{})
 
 		count, err = dataBase.GetTriggersToCheckCount()
 		So(err, ShouldBeNil)
@@ -93,17 +95,95 @@ func TestTriggerToCheck(t *testing.T) {
 	})
 }
 
+func TestTriggerToCheckPriority(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "info", "test")
+	dataBase := NewDatabase(logger, config)
+	dataBase.flush()
+	defer dataBase.flush()
+	Convey("High priority triggers should be returned first", t, func() {
+		normalTriggerID := uuid.NewV4().String()
+		highTriggerID := uuid.NewV4().String()
+
+		err := dataBase.AddTriggersToCheck([]string{normalTriggerID}, moira.NormalCheckPriority)
+		So(err, ShouldBeNil)
+		err = dataBase.AddTriggersToCheck([]string{highTriggerID}, moira.HighCheckPriority)
+		So(err, ShouldBeNil)
+
+		count, err := dataBase.GetTriggersToCheckCount()
+		So(err, ShouldBeNil)
+		So(count, ShouldEqual, 2)
+
+		actual, err := dataBase.GetTriggerToCheck()
+		So(err, ShouldBeNil)
+		So(actual.TriggerID, ShouldResemble, highTriggerID)
+		So(actual.Priority, ShouldResemble, moira.HighCheckPriority)
+		So(actual.AddedAt, ShouldBeGreaterThan, 0)
+
+		actual, err = dataBase.GetTriggerToCheck()
+		So(err, ShouldBeNil)
+		So(actual.TriggerID, ShouldResemble, normalTriggerID)
+		So(actual.Priority, ShouldResemble, moira.NormalCheckPriority)
+
+		_, err = dataBase.GetTriggerToCheck()
+		So(err, ShouldResemble, database.ErrNil)
+	})
+}
+
+func TestMigrateTriggersToCheck(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "info", "test")
+	dataBase := NewDatabase(logger, config)
+	dataBase.flush()
+	defer dataBase.flush()
+	Convey("Triggers from legacy sets should be moved to normal priority queues", t, func() {
+		c := dataBase.pool.Get()
+		defer c.Close()
+		triggerID := uuid.NewV4().String()
+		remoteTriggerID := uuid.NewV4().String()
+		_, err := c.Do("SADD", legacyTriggersToCheckKey, triggerID)
+		So(err, ShouldBeNil)
+		_, err = c.Do("SADD", legacyRemoteTriggersToCheckKey, remoteTriggerID)
+		So(err, ShouldBeNil)
+
+		err = dataBase.MigrateTriggersToCheck()
+		So(err, ShouldBeNil)
+
+		actual, err := dataBase.GetTriggerToCheck()
+		So(err, ShouldBeNil)
+		So(actual.TriggerID, ShouldResemble, triggerID)
+		So(actual.Priority, ShouldResemble, moira.NormalCheckPriority)
+		actual, err = dataBase.GetRemoteTriggerToCheck()
+		So(err, ShouldBeNil)
+		So(actual.TriggerID, ShouldResemble, remoteTriggerID)
+		So(actual.Priority, ShouldResemble, moira.NormalCheckPriority)
+
+		exists, err := redis.Bool(c.Do("EXISTS", legacyTriggersToCheckKey))
+		So(err, ShouldBeNil)
+		So(exists, ShouldBeFalse)
+
+		Convey("Migration without legacy sets should do nothing", func() {
+			err = dataBase.MigrateTriggersToCheck()
+			So(err, ShouldBeNil)
+			count, err := dataBase.GetTriggersToCheckCount()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 0)
+		})
+	})
+}
+
 func TestTriggerToCheckConnection(t *testing.T) {
 	logger, _ := logging.ConfigureLog("stdout", "info", "test")
 	dataBase := NewDatabase(logger, emptyConfig)
 	dataBase.flush()
 	defer dataBase.flush()
 	Convey("Should throw error when no connection", t, func() {
-		err := dataBase.AddTriggersToCheck([]string{"123"})
+		err := dataBase.AddTriggersToCheck([]string{"123"}, moira.NormalCheckPriority)
 		So(err, ShouldNotBeNil)
 
 		triggerID, err := dataBase.GetTriggerToCheck()
-		So(triggerID, ShouldBeEmpty)
+		So(triggerID, ShouldResemble, moira.TriggerToCheck{})
+		So(err, ShouldNotBeNil)
+
+		err = dataBase.MigrateTriggersToCheck()
 		So(err, ShouldNotBeNil)
 	})
 }
@@ -4,30 +4,11 @@ import (
 	"bytes"
 	"fmt"
 	"math"
+	"strconv"
 	"strings"
 	"time"
 )
 
-var (
-	eventStates = [...]string{"OK", "WARN", "ERROR", "NODATA", "TEST"}
-)
-
-var scores = map[string]int64{
-	"OK":        0,
-	"DEL":       0,
-	"WARN":      1,
-	"ERROR":     100,
-	"NODATA":    1000,
-	"EXCEPTION": 100000,
-}
-
-var eventStateWeight = map[string]int{
-	"OK":     0,
-	"WARN":   1,
-	"ERROR":  100,
-	"NODATA": 10000,
-}
-
 // NotificationEvent represents trigger state changes event
 type NotificationEvent struct {
 	IsTriggerEvent bool     `json:"trigger_event,omitempty"`
@@ -75,6 +56,8 @@ type SubscriptionData struct {
 	IgnoreRecoverings bool         `json:"ignore_recoverings,omitempty"`
 	ThrottlingEnabled bool         `json:"throttling"`
 	User              string       `json:"user"`
+	// Steps of escalation after notification of Contacts, ordered by delay
+	Escalations []EscalationStep `json:"escalations,omitempty"`
 }
 
 // ScheduleData represent subscription schedule
@@ -99,6 +82,8 @@ type ScheduledNotification struct {
 	Throttled bool              `json:"throttled"`
 	SendFail  int               `json:"send_fail"`
 	Timestamp int64             `json:"timestamp"`
+	// Number of subscription escalation step, 0 for notification of subscription contacts
+	EscalationStep int `json:"escalation_step,omitempty"`
 }
 
 // MatchedMetric represent parsed and matched metric data
@@ -125,8 +110,22 @@ const (
 	RisingTrigger = "rising"
 	// ExpressionTrigger represents trigger type with custom user expression
 	ExpressionTrigger = "expression"
+	// CompositeTrigger represents trigger type with custom user expression over states of other triggers
+	CompositeTrigger = "composite"
+	// SLOTrigger represents trigger type, in which state depends on error budget burn rate of good and total events targets
+	SLOTrigger = "slo"
+)
+
+const (
+	// GraphiteTargetLanguage represents graphite targets, which are used by default
+	GraphiteTargetLanguage = "graphite"
+	// PromQLTargetLanguage represents PromQL targets, which are fetched from prometheus remote sources
+	PromQLTargetLanguage = "promql"
 )
 
+// DefaultRemoteSource is a name of remote source, which is used by remote triggers without remote source specified
+const DefaultRemoteSource = "default"
+
 // Trigger represents trigger data object
 type Trigger struct {
 	ID               string        `json:"id"`
@@ -144,6 +143,158 @@ type Trigger struct {
 	PythonExpression *string       `json:"python_expression,omitempty"`
 	Patterns         []string      `json:"patterns"`
 	IsRemote         bool          `json:"is_remote"`
+	RemoteSource     string        `json:"remote_source,omitempty"`
+	TargetLanguage   string        `json:"target_language,omitempty"`
+	Aggregation      *Aggregation  `json:"aggregation,omitempty"`
+	Inputs           []string      `json:"inputs,omitempty"`
+	SLO              *SLO          `json:"slo,omitempty"`
+	CheckInterval    int64         `json:"check_interval,omitempty"`
+	Reminder         *Reminder     `json:"reminder,omitempty"`
+	TTLOverrides     []TTLOverride `json:"ttl_overrides,omitempty"`
+}
+
+// SLO represents service level objective settings of slo trigger
+// Trigger targets are: t1 - good events count, t2 - total events count
+type SLO struct {
+	// Target share of good events (in percents), e.g. 99.9
+	Objective float64 `json:"objective"`
+	// SLO period in seconds, which error budget is calculated for, 30 days by default
+	Period int64 `json:"period,omitempty"`
+	// Burn rate alerting windows, 1h/5m with burn rates 14.4 (ERROR) and 6 (WARN) are used by default,
+	// so default windows fit default checker metrics_ttl. Longer windows require remote trigger or longer metrics_ttl
+	Windows []BurnRateWindow `json:"windows,omitempty"`
+}
+
+// BurnRateWindow represents multi-window burn rate alert condition:
+// trigger switches to State if error budget burn rates in both long and short windows reach BurnRate
+type BurnRateWindow struct {
+	// Long window in seconds
+	Long int64 `json:"long"`
+	// Short window in seconds
+	Short int64 `json:"short"`
+	// Minimal burn rate, where 1 means that error budget is consumed exactly in SLO period
+	BurnRate float64 `json:"burn_rate"`
+	State    string  `json:"state"`
+}
+
+// TTLOverride represents TTL and TTL state of trigger metrics which names match the pattern
+type TTLOverride struct {
+	// Graphite glob pattern of metric names, see MatchGraphitePattern for syntax, e.g. batch.*.duration
+	Pattern string `json:"pattern"`
+	// Metric is switched to TTLState if it has no values for TTL seconds, 0 disables switching
+	TTL int64 `json:"ttl"`
+	// Trigger TTL state is used if it is not set
+	TTLState *string `json:"ttl_state,omitempty"`
+}
+
+// Reminder represents settings of repeated notifications about trigger or metric staying in the same bad state
+type Reminder struct {
+	// Interval in seconds between reminders about bad states which are not warnings, 24 hours by default
+	Interval int64 `json:"interval,omitempty"`
+	// Intervals in seconds between reminders about given states, 0 disables reminders about state, e.g. {"WARN": 3600, "NODATA": 0}
+	StateIntervals map[string]int64 `json:"state_intervals,omitempty"`
+	// Maximum number of reminders about the same bad state, reminders are not limited if it is not set
+	MaxCount int64 `json:"max_count,omitempty"`
+}
+
+var defaultSLOPeriod int64 = 30 * 24 * 60 * 60
+
+var defaultBurnRateWindows = []BurnRateWindow{
+	{Long: 60 * 60, Short: 5 * 60, BurnRate: 14.4, State: "ERROR"},
+	{Long: 60 * 60, Short: 5 * 60, BurnRate: 6, State: "WARN"},
+}
+
+// GetPeriod returns SLO period or default one if it is not set
+func (slo *SLO) GetPeriod() int64 {
+	if slo.Period == 0 {
+		return defaultSLOPeriod
+	}
+	return slo.Period
+}
+
+// GetWindows returns SLO burn rate windows or default ones if they are not set
+func (slo *SLO) GetWindows() []BurnRateWindow {
+	if len(slo.Windows) == 0 {
+		return defaultBurnRateWindows
+	}
+	return slo.Windows
+}
+
+// GetLongestWindow returns the longest of SLO burn rate windows, it is the interval of data required to check SLO
+func (slo *SLO) GetLongestWindow() int64 {
+	var longest int64
+	for _, window := range slo.GetWindows() {
+		if window.Long > longest {
+			longest = window.Long
+		}
+	}
+	return longest
+}
+
+// Aggregation represents trigger aggregation settings
+// If it is set, trigger state is calculated from the share of trigger metrics in bad states,
+// and notifications are sent only on trigger state changes
+type Aggregation struct {
+	// Share of metrics in WARN or ERROR states (in percents) from which trigger state becomes WARN
+	WarnPercent *float64 `json:"warn_percent,omitempty"`
+	// Share of metrics in ERROR state (in percents) from which trigger state becomes ERROR
+	ErrorPercent *float64 `json:"error_percent,omitempty"`
+	// Minimum number of metrics in bad states required to change trigger state
+	MinCount int64 `json:"min_count,omitempty"`
+}
+
+// TriggerCheckPriority represents priority of trigger in triggers to check queue
+type TriggerCheckPriority string
+
+const (
+	// HighCheckPriority is used for triggers, which must be checked first when checker is backlogged
+	HighCheckPriority TriggerCheckPriority = "high"
+	// NormalCheckPriority is used for all other triggers
+	NormalCheckPriority TriggerCheckPriority = "normal"
+)
+
+// CheckPriorities contains all triggers to check priorities in order of checking
+var CheckPriorities = []TriggerCheckPriority{HighCheckPriority, NormalCheckPriority}
+
+// TriggerToCheck represents trigger taken from triggers to check queue
+type TriggerToCheck struct {
+	TriggerID string
+	Priority  TriggerCheckPriority
+	// Time when trigger was added to the queue, in milliseconds
+	AddedAt int64
+}
+
+// StreamEventType represents kind of event published to real-time subscribers
+type StreamEventType string
+
+const (
+	// StateChangeStreamEvent is published when trigger state has changed
+	StateChangeStreamEvent StreamEventType = "state"
+	// MetricStateChangeStreamEvent is published when trigger metric state has changed, even if no notification event is created
+	MetricStateChangeStreamEvent StreamEventType = "metric_state"
+	// NotificationStreamEvent is published for every notification event of trigger
+	NotificationStreamEvent StreamEventType = "event"
+)
+
+// StreamEvent represents trigger or metric state change or notification event published to real-time subscribers
+type StreamEvent struct {
+	Type      StreamEventType    `json:"type"`
+	TriggerID string             `json:"trigger_id"`
+	Tags      []string           `json:"tags"`
+	Metric    string             `json:"metric,omitempty"`
+	Timestamp int64              `json:"timestamp"`
+	State     string             `json:"state,omitempty"`
+	OldState  string             `json:"old_state,omitempty"`
+	Event     *NotificationEvent `json:"event,omitempty"`
+}
+
+// StateInterval represents time interval during which trigger or its metric was in given state.
+// Metric is empty for trigger state intervals
+type StateInterval struct {
+	Metric string `json:"metric,omitempty"`
+	State  string `json:"state"`
+	Start  int64  `json:"start"`
+	End    int64  `json:"end"`
 }
 
 // TriggerCheck represent trigger data with last check data and check timestamp
@@ -160,20 +311,46 @@ type CheckData struct {
 	State           string                 `json:"state"`
 	Timestamp       int64                  `json:"timestamp,omitempty"`
 	EventTimestamp  int64                  `json:"event_timestamp,omitempty"`
+	StateTimestamp  int64                  `json:"state_timestamp,omitempty"`
+	RemindersCount  int64                  `json:"reminders_count,omitempty"`
 	Suppressed      bool                   `json:"suppressed,omitempty"`
 	SuppressedState string                 `json:"suppressed_state,omitempty"`
 	Message         string                 `json:"msg,omitempty"`
+	// Timestamp until which events of the trigger and all its metrics are suppressed
+	Maintenance     int64            `json:"maintenance,omitempty"`
+	Acknowledgement *Acknowledgement `json:"acknowledgement,omitempty"`
+	// Shares of SLO error budget consumed in parts of SLO period, keyed by part start timestamp
+	SLOBudgetConsumption map[int64]float64 `json:"slo_budget_consumption,omitempty"`
 }
 
 // MetricState represent metric state data for given timestamp
 type MetricState struct {
-	EventTimestamp  int64    `json:"event_timestamp"`
-	State           string   `json:"state"`
-	Suppressed      bool     `json:"suppressed"`
-	SuppressedState string   `json:"suppressed_state,omitempty"`
-	Timestamp       int64    `json:"timestamp"`
-	Value           *float64 `json:"value,omitempty"`
-	Maintenance     int64    `json:"maintenance,omitempty"`
+	EventTimestamp  int64            `json:"event_timestamp"`
+	StateTimestamp  int64            `json:"state_timestamp,omitempty"`
+	RemindersCount  int64            `json:"reminders_count,omitempty"`
+	State           string           `json:"state"`
+	Suppressed      bool             `json:"suppressed"`
+	SuppressedState string           `json:"suppressed_state,omitempty"`
+	Timestamp       int64            `json:"timestamp"`
+	Value           *float64         `json:"value,omitempty"`
+	Maintenance     int64            `json:"maintenance,omitempty"`
+	Acknowledgement *Acknowledgement `json:"acknowledgement,omitempty"`
+}
+
+// Acknowledgement is confirmation by user that the bad state of trigger or metric is being handled,
+// it stops reminders about the state and is reset when the state changes
+type Acknowledgement struct {
+	User      string `json:"user"`
+	State     string `json:"state"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// GetAcknowledgement returns acknowledgement of the state, nil is returned if other state is acknowledged
+func GetAcknowledgement(acknowledgement *Acknowledgement, state string) *Acknowledgement {
+	if acknowledgement == nil || acknowledgement.State != state {
+		return nil
+	}
+	return acknowledgement
 }
 
 // MetricEvent represent filter metric event
@@ -189,7 +366,7 @@ func (events NotificationEvents) GetSubjectState() string {
 	for _, event := range events {
 		states[event.State] = true
 	}
-	for _, state := range eventStates {
+	for _, state := range append(GetSeverityModel().Names(), "TEST") {
 		if states[state] {
 			result = state
 		}
@@ -206,6 +383,45 @@ func (trigger *TriggerData) GetTags() string {
 	return buffer.String()
 }
 
+// GetMessageLine returns "15:04: metric = value (OLD_STATE to STATE). message" string describing single event
+func (eventData *NotificationEvent) GetMessageLine(location *time.Location) string {
+	value := strconv.FormatFloat(UseFloat64(eventData.Value), 'f', -1, 64)
+	line := fmt.Sprintf("%s: %s = %s (%s to %s)", time.Unix(eventData.Timestamp, 0).In(location).Format("15:04"), eventData.Metric, value, eventData.OldState, eventData.State)
+	if message := UseString(eventData.Message); len(message) > 0 {
+		line += fmt.Sprintf(". %s", message)
+	}
+	return line
+}
+
+// MessageMarkup describes markup language of messenger, which notification messages are formatted with
+type MessageMarkup struct {
+	// Bold is put around text to make it bold, e.g. "*"
+	Bold string
+	// LinkFormat formats link from text %[1]s and url %[2]s, e.g. "[%[1]s](%[2]s)"
+	LinkFormat string
+}
+
+// GetMessageHeader returns "STATE [tag1][tag2] trigger link" string, which starts messenger notification
+func (events NotificationEvents) GetMessageHeader(trigger TriggerData, frontURI string, markup MessageMarkup) string {
+	link := fmt.Sprintf(markup.LinkFormat, trigger.Name, fmt.Sprintf("%s/trigger/%s", frontURI, events[0].TriggerID))
+	return fmt.Sprintf("%[1]s%[2]s%[1]s %[3]s %[4]s", markup.Bold, events.GetSubjectState(), trigger.GetTags(), link)
+}
+
+// GetIconURL returns url of Moira OK icon if all events are OK, otherwise url of error icon
+func (events NotificationEvents) GetIconURL(frontURI string) string {
+	for _, event := range events {
+		if !GetSeverityModel().IsOK(event.State) {
+			return fmt.Sprintf("%s/public/fav72_error.png", frontURI)
+		}
+	}
+	return fmt.Sprintf("%s/public/fav72_ok.png", frontURI)
+}
+
+// GetThrottledWarning returns warning, which ends notification about events of throttled trigger
+func (markup MessageMarkup) GetThrottledWarning() string {
+	return fmt.Sprintf("Please, %[1]sfix your system or tune this trigger%[1]s to generate less events.", markup.Bold)
+}
+
 // GetKey return notification key to prevent duplication to the same contact
 func (notification *ScheduledNotification) GetKey() string {
 	return fmt.Sprintf("%s:%s:%s:%s:%s:%d:%f:%d:%t:%d",
@@ -286,6 +502,24 @@ func (checkData CheckData) GetEventTimestamp() int64 {
 	return checkData.EventTimestamp
 }
 
+// GetStateTimestamp gets time when metric has switched to its current state, 0 is returned if it is unknown.
+// States saved before state history was introduced start at the last event time
+func (metricState MetricState) GetStateTimestamp() int64 {
+	if metricState.StateTimestamp == 0 {
+		return metricState.EventTimestamp
+	}
+	return metricState.StateTimestamp
+}
+
+// GetStateTimestamp gets time when trigger has switched to its current state, 0 is returned if it is unknown.
+// States saved before state history was introduced start at the last event time
+func (checkData CheckData) GetStateTimestamp() int64 {
+	if checkData.StateTimestamp == 0 {
+		return checkData.EventTimestamp
+	}
+	return checkData.StateTimestamp
+}
+
 // IsSimple checks triggers patterns
 // If patterns more than one or it contains standard graphite wildcard symbols,
 // when this target can contain more then one metrics, and is it not simple trigger
@@ -301,30 +535,107 @@ func (trigger *Trigger) IsSimple() bool {
 	return true
 }
 
+// IsAggregated checks if trigger state must be calculated from the share of its metrics states
+func (trigger *Trigger) IsAggregated() bool {
+	return trigger.Aggregation != nil
+}
+
+// IsComposite checks if trigger state must be calculated from the states of its input triggers
+func (trigger *Trigger) IsComposite() bool {
+	return trigger.TriggerType == CompositeTrigger
+}
+
+// IsSLO checks if trigger state must be calculated from error budget burn rate
+func (trigger *Trigger) IsSLO() bool {
+	return trigger.TriggerType == SLOTrigger && trigger.SLO != nil
+}
+
+// IsPromQL checks if trigger targets are PromQL queries
+func (trigger *Trigger) IsPromQL() bool {
+	return trigger.TargetLanguage == PromQLTargetLanguage
+}
+
+// GetRemoteSource returns name of remote source trigger metrics are fetched from, or empty string for local triggers
+func (trigger *Trigger) GetRemoteSource() string {
+	if !trigger.IsRemote {
+		return ""
+	}
+	if trigger.RemoteSource == "" {
+		return DefaultRemoteSource
+	}
+	return trigger.RemoteSource
+}
+
+// GetTTLOverride returns the first TTL override which pattern matches metric name, nil is returned if there is no such override
+func (trigger *Trigger) GetTTLOverride(metric string) *TTLOverride {
+	for i := range trigger.TTLOverrides {
+		if MatchGraphitePattern(trigger.TTLOverrides[i].Pattern, metric) {
+			return &trigger.TTLOverrides[i]
+		}
+	}
+	return nil
+}
+
+// GetTTLState returns trigger TTL state, NODATA is used if it is not set
+func (trigger *Trigger) GetTTLState() string {
+	if trigger.TTLState == nil {
+		return "NODATA"
+	}
+	return *trigger.TTLState
+}
+
+// GetMetricTTL returns TTL and TTL state applied to metric
+func (trigger *Trigger) GetMetricTTL(metric string) (int64, string) {
+	return trigger.GetTTLOverride(metric).Apply(trigger.TTL, trigger.GetTTLState())
+}
+
+// Apply returns TTL and TTL state of override, given TTL state is used if override does not set it.
+// Given TTL and TTL state are returned if override is nil
+func (override *TTLOverride) Apply(ttl int64, ttlState string) (int64, string) {
+	if override == nil {
+		return ttl, ttlState
+	}
+	if override.TTLState != nil {
+		return override.TTL, *override.TTLState
+	}
+	return override.TTL, ttlState
+}
+
+// GetMetricsStatesCount returns number of metrics in each state
+func (checkData *CheckData) GetMetricsStatesCount() map[string]int64 {
+	statesCount := make(map[string]int64)
+	for _, metricState := range checkData.Metrics {
+		statesCount[metricState.State]++
+	}
+	return statesCount
+}
+
 // UpdateScore update and return checkData score, based on metric states and checkData state
 func (checkData *CheckData) UpdateScore() int64 {
-	checkData.Score = scores[checkData.State]
+	severityModel := GetSeverityModel()
+	checkData.Score = severityModel.GetScore(checkData.State)
 	for _, metricData := range checkData.Metrics {
-		checkData.Score += scores[metricData.State]
+		checkData.Score += severityModel.GetScore(metricData.State)
 	}
 	return checkData.Score
 }
 
 // MustIgnore returns true if given state transition must be ignored
 func (subscription *SubscriptionData) MustIgnore(eventData *NotificationEvent) bool {
-	if oldStateWeight, ok := eventStateWeight[eventData.OldState]; ok {
-		if newStateWeight, ok := eventStateWeight[eventData.State]; ok {
-			delta := newStateWeight - oldStateWeight
-			if delta < 0 {
-				if delta == -1 && (subscription.IgnoreRecoverings || subscription.IgnoreWarnings){
-					return true
-				}
-				return subscription.IgnoreRecoverings
-			}
-			if delta == 1 {
-				return subscription.IgnoreWarnings
-			}
+	severityModel := GetSeverityModel()
+	if !severityModel.IsSeverity(eventData.OldState) || !severityModel.IsSeverity(eventData.State) {
+		return false
+	}
+	isWarningTransition := severityModel.IsWarningTransition(eventData.OldState, eventData.State)
+	delta := severityModel.Compare(eventData.State, eventData.OldState)
+	if delta < 0 {
+		if isWarningTransition && (subscription.IgnoreRecoverings || subscription.IgnoreWarnings) {
+			return true
 		}
+		return subscription.IgnoreRecoverings
+	}
+	if delta > 0 && isWarningTransition {
+		return subscription.IgnoreWarnings
 	}
 	return false
 }
@@ -3,6 +3,7 @@ package moira
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -127,6 +128,49 @@ func TestTriggerData_GetTags(t *testing.T) {
 	})
 }
 
+func TestNotificationEvent_GetMessageLine(t *testing.T) {
+	location, _ := time.LoadLocation("UTC")
+	value := 97.5
+	Convey("Line without message", t, func() {
+		event := NotificationEvent{Metric: "my.metric", Value: &value, OldState: "OK", State: "ERROR", Timestamp: 1500000000}
+		So(event.GetMessageLine(location), ShouldResemble, "02:40: my.metric = 97.5 (OK to ERROR)")
+	})
+	Convey("Line with message", t, func() {
+		message := "Acknowledged by user"
+		event := NotificationEvent{Metric: "my.metric", OldState: "ERROR", State: "ERROR", Message: &message, Timestamp: 1500000060}
+		So(event.GetMessageLine(location), ShouldResemble, "02:41: my.metric = 0 (ERROR to ERROR). Acknowledged by user")
+	})
+}
+
+func TestNotificationEvents_GetMessageHeader(t *testing.T) {
+	trigger := TriggerData{Name: "test trigger", Tags: []string{"tag1", "tag2"}}
+	events := NotificationEvents{{TriggerID: "trigger1", State: "OK"}, {TriggerID: "trigger1", State: "WARN"}}
+	Convey("Header with markdown link", t, func() {
+		markup := MessageMarkup{Bold: "**", LinkFormat: "[%[1]s](%[2]s)"}
+		So(events.GetMessageHeader(trigger, "http://moira", markup), ShouldEqual, "**WARN** [tag1][tag2] [test trigger](http://moira/trigger/trigger1)")
+	})
+	Convey("Plain header", t, func() {
+		So(events.GetMessageHeader(trigger, "http://moira", MessageMarkup{LinkFormat: "%[1]s"}), ShouldEqual, "WARN [tag1][tag2] test trigger")
+	})
+}
+
+func TestNotificationEvents_GetIconURL(t *testing.T) {
+	Convey("OK icon if all events are OK", t, func() {
+		events := NotificationEvents{{State: "OK"}, {State: "OK"}}
+		So(events.GetIconURL("http://moira"), ShouldEqual, "http://moira/public/fav72_ok.png")
+	})
+	Convey("Error icon if any event is not OK", t, func() {
+		events := NotificationEvents{{State: "OK"}, {State: "WARN"}}
+		So(events.GetIconURL("http://moira"), ShouldEqual, "http://moira/public/fav72_error.png")
+	})
+}
+
+func TestMessageMarkup_GetThrottledWarning(t *testing.T) {
+	Convey("Throttled warning with bold mark", t, func() {
+		So(MessageMarkup{Bold: "*"}.GetThrottledWarning(), ShouldEqual, "Please, *fix your system or tune this trigger* to generate less events.")
+	})
+}
+
 func TestScheduledNotification_GetKey(t *testing.T) {
 	Convey("Get key", t, func() {
 		notification := ScheduledNotification{
@@ -0,0 +1,45 @@
+package moira
+
+// EscalationStep represents contacts of subscription which are notified if alert
+// is neither acknowledged nor recovered in Delay seconds since the event
+type EscalationStep struct {
+	Contacts []string `json:"contacts"`
+	Delay    int64    `json:"delay"`
+}
+
+// GetAllContacts returns contacts of the first notification step and of all escalation steps without duplicates
+func (subscription *SubscriptionData) GetAllContacts() []string {
+	contacts := make([]string, 0, len(subscription.Contacts))
+	added := make(map[string]bool)
+	appendContacts := func(stepContacts []string) {
+		for _, contactID := range stepContacts {
+			if !added[contactID] {
+				added[contactID] = true
+				contacts = append(contacts, contactID)
+			}
+		}
+	}
+	appendContacts(subscription.Contacts)
+	for _, step := range subscription.Escalations {
+		appendContacts(step.Contacts)
+	}
+	return contacts
+}
+
+// NeedEscalation checks if event is an alert which is escalated: state has changed to the one with nonzero score
+func (event *NotificationEvent) NeedEscalation() bool {
+	return event.State != event.OldState && GetSeverityModel().GetScore(event.State) > 0
+}
+
+// IsEscalationActual checks if alert is still neither acknowledged nor recovered, so escalation of event must be sent
+func (checkData *CheckData) IsEscalationActual(event *NotificationEvent) bool {
+	state, acknowledgement := checkData.State, checkData.Acknowledgement
+	if !event.IsTriggerEvent {
+		metricState, ok := checkData.Metrics[event.Metric]
+		if !ok {
+			return false
+		}
+		state, acknowledgement = metricState.State, metricState.Acknowledgement
+	}
+	return state == event.State && acknowledgement == nil
+}
@@ -0,0 +1,52 @@
+package moira
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSubscriptionGetAllContacts(t *testing.T) {
+	Convey("Contacts of all steps are returned without duplicates", t, func() {
+		subscription := SubscriptionData{
+			Contacts: []string{"contact1", "contact2"},
+			Escalations: []EscalationStep{
+				{Contacts: []string{"contact2", "contact3"}, Delay: 600},
+				{Contacts: []string{"contact4"}, Delay: 1800},
+			},
+		}
+		So(subscription.GetAllContacts(), ShouldResemble, []string{"contact1", "contact2", "contact3", "contact4"})
+	})
+}
+
+func TestNotificationEventNeedEscalation(t *testing.T) {
+	Convey("Only changes to bad states are escalated", t, func() {
+		So((&NotificationEvent{OldState: "OK", State: "ERROR"}).NeedEscalation(), ShouldBeTrue)
+		So((&NotificationEvent{OldState: "ERROR", State: "EXCEPTION"}).NeedEscalation(), ShouldBeTrue)
+		So((&NotificationEvent{OldState: "ERROR", State: "OK"}).NeedEscalation(), ShouldBeFalse)
+		So((&NotificationEvent{OldState: "ERROR", State: "ERROR"}).NeedEscalation(), ShouldBeFalse)
+		So((&NotificationEvent{State: "TEST"}).NeedEscalation(), ShouldBeFalse)
+	})
+}
+
+func TestCheckDataIsEscalationActual(t *testing.T) {
+	checkData := CheckData{
+		State: "EXCEPTION",
+		Metrics: map[string]MetricState{
+			"metric1": {State: "ERROR"},
+			"metric2": {State: "ERROR", Acknowledgement: &Acknowledgement{User: "user", State: "ERROR"}},
+		},
+	}
+
+	Convey("Trigger alert", t, func() {
+		So(checkData.IsEscalationActual(&NotificationEvent{IsTriggerEvent: true, State: "EXCEPTION"}), ShouldBeTrue)
+		So(checkData.IsEscalationActual(&NotificationEvent{IsTriggerEvent: true, State: "NODATA"}), ShouldBeFalse)
+	})
+
+	Convey("Metric alert", t, func() {
+		So(checkData.IsEscalationActual(&NotificationEvent{Metric: "metric1", State: "ERROR"}), ShouldBeTrue)
+		So(checkData.IsEscalationActual(&NotificationEvent{Metric: "metric1", State: "WARN"}), ShouldBeFalse)
+		So(checkData.IsEscalationActual(&NotificationEvent{Metric: "metric2", State: "ERROR"}), ShouldBeFalse)
+		So(checkData.IsEscalationActual(&NotificationEvent{Metric: "metric3", State: "ERROR"}), ShouldBeFalse)
+	})
+}
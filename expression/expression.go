@@ -39,19 +39,22 @@ type TriggerExpression struct {
 	MainTargetValue         float64
 	AdditionalTargetsValues map[string]float64
 	PreviousState           string
+
+	// States of composite trigger inputs: t1, t2, ...
+	InputsStates map[string]string
 }
 
 // Get realizing govaluate.Parameters interface used in evaluable expression
 func (triggerExpression TriggerExpression) Get(name string) (interface{}, error) {
+	if state, ok := triggerExpression.InputsStates[name]; ok {
+		return state, nil
+	}
+	if moira.GetSeverityModel().IsSeverity(name) {
+		return name, nil
+	}
 	switch name {
-	case "OK":
-		return "OK", nil
-	case "WARN", "WARNING":
+	case "WARNING":
 		return "WARN", nil
-	case "ERROR":
-		return "ERROR", nil
-	case "NODATA":
-		return "NODATA", nil
 	case "WARN_VALUE":
 		if triggerExpression.WarnValue == nil {
 			return nil, fmt.Errorf("no value with name WARN_VALUE")
@@ -87,6 +90,9 @@ func (triggerExpression *TriggerExpression) Evaluate() (string, error) {
 	}
 	switch res := result.(type) {
 	case string:
+		if !moira.GetSeverityModel().IsKnownState(res) {
+			return "", ErrInvalidExpression{internalError: fmt.Errorf("expression result '%s' is not a state", res)}
+		}
 		return res, nil
 	default:
 		return "", ErrInvalidExpression{internalError: fmt.Errorf("expression result must be state value")}
@@ -94,9 +100,9 @@ func (triggerExpression *TriggerExpression) Evaluate() (string, error) {
 }
 
 func getExpression(triggerExpression *TriggerExpression) (*govaluate.EvaluableExpression, error) {
-	if triggerExpression.TriggerType == moira.ExpressionTrigger {
+	if triggerExpression.TriggerType == moira.ExpressionTrigger || triggerExpression.TriggerType == moira.CompositeTrigger {
 		if triggerExpression.Expression == nil || *triggerExpression.Expression == "" {
-			return nil, fmt.Errorf("trigger_type set to expression, but no expression provided")
+			return nil, fmt.Errorf("trigger_type set to %s, but no expression provided", triggerExpression.TriggerType)
 		}
 		return getUserExpression(*triggerExpression.Expression)
 	}
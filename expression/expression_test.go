@@ -82,6 +82,46 @@ func TestExpression(t *testing.T) {
 		So(err, ShouldResemble, ErrInvalidExpression{fmt.Errorf("functions is forbidden")})
 		So(result, ShouldBeEmpty)
 	})
+
+	Convey("Test Composite", t, func() {
+		expression := "t1 == ERROR && t2 == ERROR ? ERROR : OK"
+		result, err := (&TriggerExpression{Expression: &expression, InputsStates: map[string]string{"t1": "ERROR", "t2": "ERROR"}, TriggerType: moira.CompositeTrigger}).Evaluate()
+		So(err, ShouldBeNil)
+		So(result, ShouldResemble, "ERROR")
+
+		result, err = (&TriggerExpression{Expression: &expression, InputsStates: map[string]string{"t1": "ERROR", "t2": "WARN"}, TriggerType: moira.CompositeTrigger}).Evaluate()
+		So(err, ShouldBeNil)
+		So(result, ShouldResemble, "OK")
+
+		result, err = (&TriggerExpression{InputsStates: map[string]string{"t1": "ERROR"}, TriggerType: moira.CompositeTrigger}).Evaluate()
+		So(err, ShouldResemble, ErrInvalidExpression{fmt.Errorf("trigger_type set to composite, but no expression provided")})
+		So(result, ShouldBeEmpty)
+	})
+
+	Convey("Test Custom Severities", t, func() {
+		expression := "t1 > 20 ? CRITICAL : (t1 > 10 ? ERROR : OK)"
+		result, err := (&TriggerExpression{Expression: &expression, MainTargetValue: 21.0, TriggerType: moira.ExpressionTrigger}).Evaluate()
+		So(err, ShouldResemble, ErrInvalidExpression{fmt.Errorf("no value with name CRITICAL")})
+		So(result, ShouldBeEmpty)
+
+		severities := append([]moira.Severity{}, moira.DefaultSeverities...)
+		severities = append(severities[:3], moira.Severity{Name: "CRITICAL", Score: 500}, severities[3])
+		model, err := moira.NewSeverityModel(severities)
+		So(err, ShouldBeNil)
+		moira.SetSeverityModel(model)
+		defer func() {
+			defaultModel, _ := moira.NewSeverityModel(moira.DefaultSeverities)
+			moira.SetSeverityModel(defaultModel)
+		}()
+
+		result, err = (&TriggerExpression{Expression: &expression, MainTargetValue: 21.0, TriggerType: moira.ExpressionTrigger}).Evaluate()
+		So(err, ShouldBeNil)
+		So(result, ShouldResemble, "CRITICAL")
+
+		result, err = (&TriggerExpression{Expression: &expression, MainTargetValue: 11.0, TriggerType: moira.ExpressionTrigger}).Evaluate()
+		So(err, ShouldBeNil)
+		So(result, ShouldResemble, "ERROR")
+	})
 }
 
 func TestGetExpressionValue(t *testing.T) {
@@ -145,6 +185,11 @@ func TestGetExpressionValue(t *testing.T) {
 					name:          "PREV_STATE",
 					expectedValue: "NODATA",
 				},
+				{
+					values:        TriggerExpression{InputsStates: map[string]string{"t1": "WARN"}},
+					name:          "t1",
+					expectedValue: "WARN",
+				},
 			}
 			runGetExpressionValuesTest(getExpressionValuesTests)
 		}
@@ -12,7 +12,9 @@ type Database interface {
 	UpdateMetricsHeartbeat() error
 	GetMetricsUpdatesCount() (int64, error)
 	GetChecksUpdatesCount() (int64, error)
-	GetRemoteChecksUpdatesCount() (int64, error)
+	GetRemoteChecksUpdatesCount(remoteSource string) (int64, error)
+	SetRemoteBreakerState(remoteSource string, state string) error
+	GetRemoteBreakerState(remoteSource string) (string, error)
 	GetNotifierState() (string, error)
 	SetNotifierState(string) error
 
@@ -23,15 +25,27 @@ type Database interface {
 
 	// LastCheck storing
 	GetTriggerLastCheck(triggerID string) (CheckData, error)
-	SetTriggerLastCheck(triggerID string, checkData *CheckData, isRemote bool) error
+	SetTriggerLastCheck(triggerID string, checkData *CheckData, remoteSource string) error
 	RemoveTriggerLastCheck(triggerID string) error
 	GetTriggerCheckIDs(tags []string, onlyErrors bool) ([]string, error)
 	SetTriggerCheckMetricsMaintenance(triggerID string, metrics map[string]int64) error
+	SetTriggerMaintenance(triggerID string, maintenance int64) error
+	SetTriggerCheckAcknowledgement(triggerID string, metric string, acknowledgement *Acknowledgement) error
+
+	// Trigger state history storing
+	AddTriggerStateHistory(triggerID string, intervals []*StateInterval, retention int64) error
+	GetTriggerStateHistory(triggerID string, from int64, to int64) ([]*StateInterval, error)
+
+	// Maintenance windows storing
+	GetMaintenanceWindow(windowID string) (MaintenanceWindow, error)
+	GetMaintenanceWindows(from int64) ([]*MaintenanceWindow, error)
+	SaveMaintenanceWindow(window *MaintenanceWindow) error
+	RemoveMaintenanceWindow(windowID string) error
 
 	// Trigger storing
 	GetTriggerIDs() ([]string, error)
 	GetAllTriggerIDs() ([]string, error)
-	GetRemoteTriggerIDs() ([]string, error)
+	GetRemoteTriggerIDs(remoteSource string) ([]string, error)
 	GetTrigger(triggerID string) (Trigger, error)
 	GetTriggers(triggerIDs []string) ([]*Trigger, error)
 	GetTriggerChecks(triggerIDs []string) ([]*TriggerCheck, error)
@@ -39,6 +53,7 @@ type Database interface {
 	RemoveTrigger(triggerID string) error
 	GetPatternTriggerIDs(pattern string) ([]string, error)
 	RemovePatternTriggerIDs(pattern string) error
+	GetCompositeTriggerIDs(inputTriggerID string) ([]string, error)
 
 	// Throttling
 	GetTriggerThrottling(triggerID string) (time.Time, time.Time)
@@ -52,6 +67,10 @@ type Database interface {
 	FetchNotificationEvent() (NotificationEvent, error)
 	RemoveAllNotificationEvents() error
 
+	// StreamEvent publishing
+	PublishStreamEvent(event *StreamEvent) error
+	SubscribeStreamEvents(tomb *tomb.Tomb) (<-chan *StreamEvent, error)
+
 	// ContactData storing
 	GetContact(contactID string) (ContactData, error)
 	GetContacts(contactIDs []string) ([]*ContactData, error)
@@ -60,6 +79,12 @@ type Database interface {
 	SaveContact(contact *ContactData) error
 	GetUserContactIDs(userLogin string) ([]string, error)
 
+	// Rotation storing
+	GetRotation(rotationID string) (Rotation, error)
+	GetUserRotations(userLogin string) ([]*Rotation, error)
+	SaveRotation(rotation *Rotation) error
+	RemoveRotation(rotationID string) error
+
 	// SubscriptionData storing
 	GetSubscription(id string) (SubscriptionData, error)
 	GetSubscriptions(subscriptionIDs []string) ([]*SubscriptionData, error)
@@ -76,6 +101,7 @@ type Database interface {
 	FetchNotifications(to int64) ([]*ScheduledNotification, error)
 	AddNotification(notification *ScheduledNotification) error
 	AddNotifications(notification []*ScheduledNotification, timestamp int64) error
+	GetTriggerEscalations(triggerID string) ([]*ScheduledNotification, error)
 
 	// Patterns and metrics storing
 	GetPatterns() ([]string, error)
@@ -88,17 +114,29 @@ type Database interface {
 	SubscribeMetricEvents(tomb *tomb.Tomb) (<-chan *MetricEvent, error)
 	SaveMetrics(buffer map[string]*MatchedMetric) error
 	GetMetricRetention(metric string) (int64, error)
+	SetMetricsTTL(ttl int64) error
+	GetMetricsTTL() (int64, error)
 	GetMetricsValues(metrics []string, from int64, until int64) (map[string][]*MetricValue, error)
 	RemoveMetricValues(metric string, toTime int64) error
 	RemoveMetricsValues(metrics []string, toTime int64) error
 
-	AddTriggersToCheck(triggerIDs []string) error
-	GetTriggerToCheck() (string, error)
+	AddTriggersToCheck(triggerIDs []string, priority TriggerCheckPriority) error
+	GetTriggerToCheck() (TriggerToCheck, error)
 	GetTriggersToCheckCount() (int64, error)
 
-	AddRemoteTriggersToCheck(triggerIDs []string) error
-	GetRemoteTriggerToCheck() (string, error)
+	AddRemoteTriggersToCheck(triggerIDs []string, priority TriggerCheckPriority) error
+	GetRemoteTriggerToCheck() (TriggerToCheck, error)
 	GetRemoteTriggersToCheckCount() (int64, error)
+	MigrateTriggersToCheck() error
+
+	// Sharded checker instances
+	SetCheckerHeartbeat(instanceID string) error
+	GetCheckerInstances() (map[string]int64, error)
+	RemoveCheckerInstance(instanceID string) error
+	AddShardTriggersToCheck(instanceID string, triggerIDs []string, priority TriggerCheckPriority) error
+	GetShardTriggerToCheck(instanceID string) (TriggerToCheck, error)
+	AddShardRemoteTriggersToCheck(instanceID string, triggerIDs []string, priority TriggerCheckPriority) error
+	GetShardRemoteTriggerToCheck(instanceID string) (TriggerToCheck, error)
 
 	// TriggerCheckLock storing
 	AcquireTriggerCheckLock(triggerID string, timeout int) error
@@ -0,0 +1,127 @@
+package moira
+
+// MaintenanceWindow represents interval when events of triggers and metrics in its scope are suppressed
+type MaintenanceWindow struct {
+	ID string `json:"id"`
+	// Start and end of the window, unix timestamps. Recurring window occurs at every time matching Recurrence since Start
+	// and every occurrence lasts End - Start seconds
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+	// Cron expression (minute hour day-of-month month day-of-week, UTC) of recurring window occurrences starts
+	Recurrence string `json:"recurrence,omitempty"`
+	// Recurring window has no occurrences started after RecurrenceEnd, recurrence is not limited if it is not set
+	RecurrenceEnd int64            `json:"recurrence_end,omitempty"`
+	Scope         MaintenanceScope `json:"scope"`
+	// Login of user who has created the window
+	Author    string `json:"author"`
+	Reason    string `json:"reason"`
+	CreatedAt int64  `json:"created_at"`
+
+	// schedule is parsed Recurrence, it is set by ParseRecurrence to avoid parsing on every check
+	schedule *CronSchedule
+}
+
+// MaintenanceScope determines events suppressed by maintenance window, all conditions which are set must match
+type MaintenanceScope struct {
+	TriggerID string `json:"trigger_id,omitempty"`
+	// Graphite glob pattern of metric names, see MatchGraphitePattern for syntax. Trigger state events are suppressed only if it is not set
+	Metric string `json:"metric,omitempty"`
+	// Tags which trigger must have
+	Tags []string `json:"tags,omitempty"`
+}
+
+// IsRecurring checks if window has recurrence
+func (window *MaintenanceWindow) IsRecurring() bool {
+	return window.Recurrence != ""
+}
+
+// ParseRecurrence parses and keeps recurrence schedule of the window, it must be called before window is shared between goroutines
+func (window *MaintenanceWindow) ParseRecurrence() error {
+	if !window.IsRecurring() {
+		return nil
+	}
+	schedule, err := ParseCronSchedule(window.Recurrence)
+	if err != nil {
+		return err
+	}
+	window.schedule = schedule
+	return nil
+}
+
+// GetExpiration returns time when the last window occurrence ends, 0 is returned if window recurs infinitely
+func (window *MaintenanceWindow) GetExpiration() int64 {
+	if !window.IsRecurring() {
+		return window.End
+	}
+	if window.RecurrenceEnd == 0 {
+		return 0
+	}
+	return window.RecurrenceEnd + window.End - window.Start
+}
+
+// GetOccurrence returns start and end of window occurrence which is active at timestamp or the nearest upcoming one,
+// zeros are returned if window has no more occurrences
+func (window *MaintenanceWindow) GetOccurrence(timestamp int64) (int64, int64) {
+	if !window.IsRecurring() {
+		if timestamp >= window.End {
+			return 0, 0
+		}
+		return window.Start, window.End
+	}
+	schedule := window.schedule
+	if schedule == nil {
+		var err error
+		if schedule, err = ParseCronSchedule(window.Recurrence); err != nil {
+			return 0, 0
+		}
+	}
+	duration := window.End - window.Start
+	after := timestamp - duration
+	if after < window.Start-1 {
+		after = window.Start - 1
+	}
+	start := schedule.Next(after)
+	if start == 0 || (window.RecurrenceEnd != 0 && start > window.RecurrenceEnd) {
+		return 0, 0
+	}
+	return start, start + duration
+}
+
+// IsActive checks if window is active at timestamp
+func (window *MaintenanceWindow) IsActive(timestamp int64) bool {
+	start, end := window.GetOccurrence(timestamp)
+	return start <= timestamp && timestamp < end
+}
+
+// Matches checks if event of trigger metric is in scope, metric is empty for trigger state events
+func (scope *MaintenanceScope) Matches(triggerID string, triggerTags []string, metric string) bool {
+	if scope.TriggerID != "" && scope.TriggerID != triggerID {
+		return false
+	}
+	if scope.Metric != "" {
+		if metric == "" || !MatchGraphitePattern(scope.Metric, metric) {
+			return false
+		}
+	}
+	tags := make(map[string]bool, len(triggerTags))
+	for _, tag := range triggerTags {
+		tags[tag] = true
+	}
+	for _, tag := range scope.Tags {
+		if !tags[tag] {
+			return false
+		}
+	}
+	return true
+}
+
+// GetActiveMaintenanceWindow returns the first of windows which is active at timestamp and has the event of trigger metric in scope,
+// nil is returned if there is no such window
+func GetActiveMaintenanceWindow(windows []*MaintenanceWindow, triggerID string, triggerTags []string, metric string, timestamp int64) *MaintenanceWindow {
+	for _, window := range windows {
+		if window.Scope.Matches(triggerID, triggerTags, metric) && window.IsActive(timestamp) {
+			return window
+		}
+	}
+	return nil
+}
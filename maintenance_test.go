@@ -0,0 +1,105 @@
+package moira
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMaintenanceWindow_GetOccurrence(t *testing.T) {
+	Convey("One-time window", t, func() {
+		window := MaintenanceWindow{Start: 100, End: 200}
+		So(window.GetExpiration(), ShouldEqual, 200)
+
+		start, end := window.GetOccurrence(50)
+		So([]int64{start, end}, ShouldResemble, []int64{100, 200})
+		So(window.IsActive(50), ShouldBeFalse)
+		So(window.IsActive(100), ShouldBeTrue)
+		So(window.IsActive(199), ShouldBeTrue)
+
+		start, end = window.GetOccurrence(200)
+		So([]int64{start, end}, ShouldResemble, []int64{int64(0), int64(0)})
+		So(window.IsActive(200), ShouldBeFalse)
+	})
+
+	Convey("Recurring window", t, func() {
+		day := time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC).Unix()
+		// Every day from 03:00 to 04:00 since 2019-01-02 until 2019-01-04
+		window := MaintenanceWindow{
+			Start:         day + 3*3600,
+			End:           day + 4*3600,
+			Recurrence:    "0 3 * * *",
+			RecurrenceEnd: day + 2*24*3600 + 3*3600,
+		}
+		So(window.GetExpiration(), ShouldEqual, day+2*24*3600+4*3600)
+
+		start, end := window.GetOccurrence(day)
+		So([]int64{start, end}, ShouldResemble, []int64{day + 3*3600, day + 4*3600})
+		So(window.IsActive(day+3*3600-1), ShouldBeFalse)
+		So(window.IsActive(day+3*3600), ShouldBeTrue)
+		So(window.IsActive(day+4*3600), ShouldBeFalse)
+
+		start, end = window.GetOccurrence(day + 24*3600 + 3*3600 + 1800)
+		So([]int64{start, end}, ShouldResemble, []int64{day + 24*3600 + 3*3600, day + 24*3600 + 4*3600})
+		So(window.IsActive(day+24*3600+3*3600+1800), ShouldBeTrue)
+
+		start, end = window.GetOccurrence(day + 2*24*3600 + 5*3600)
+		So([]int64{start, end}, ShouldResemble, []int64{int64(0), int64(0)})
+	})
+
+	Convey("Recurring window without recurrence end", t, func() {
+		window := MaintenanceWindow{Start: 0, End: 600, Recurrence: "*/30 * * * *"}
+		So(window.GetExpiration(), ShouldEqual, 0)
+		So(window.IsActive(1800+599), ShouldBeTrue)
+		So(window.IsActive(1800+600), ShouldBeFalse)
+	})
+
+	Convey("Recurring window with parsed recurrence", t, func() {
+		window := MaintenanceWindow{Start: 0, End: 600, Recurrence: "*/30 * * * *"}
+		So(window.ParseRecurrence(), ShouldBeNil)
+		So(window.schedule, ShouldNotBeNil)
+		window.Recurrence = "invalid"
+		So(window.IsActive(1800+599), ShouldBeTrue)
+		So(window.IsActive(1800+600), ShouldBeFalse)
+	})
+
+	Convey("Recurring window with invalid recurrence", t, func() {
+		window := MaintenanceWindow{Start: 0, End: 600, Recurrence: "* * *"}
+		So(window.ParseRecurrence(), ShouldNotBeNil)
+		So(window.IsActive(300), ShouldBeFalse)
+	})
+}
+
+func TestMaintenanceScope_Matches(t *testing.T) {
+	tags := []string{"db", "production"}
+
+	Convey("Trigger scope", t, func() {
+		scope := MaintenanceScope{TriggerID: "trigger1"}
+		So(scope.Matches("trigger1", tags, ""), ShouldBeTrue)
+		So(scope.Matches("trigger1", tags, "db.host1.replication"), ShouldBeTrue)
+		So(scope.Matches("trigger2", tags, ""), ShouldBeFalse)
+	})
+
+	Convey("Metric scope", t, func() {
+		scope := MaintenanceScope{TriggerID: "trigger1", Metric: "db.*.replication"}
+		So(scope.Matches("trigger1", tags, "db.host1.replication"), ShouldBeTrue)
+		So(scope.Matches("trigger1", tags, "db.host1.cpu"), ShouldBeFalse)
+		So(scope.Matches("trigger1", tags, ""), ShouldBeFalse)
+	})
+
+	Convey("Tags scope", t, func() {
+		So((&MaintenanceScope{Tags: []string{"db"}}).Matches("trigger1", tags, ""), ShouldBeTrue)
+		So((&MaintenanceScope{Tags: []string{"db", "staging"}}).Matches("trigger1", tags, ""), ShouldBeFalse)
+	})
+
+	Convey("Get active window", t, func() {
+		windows := []*MaintenanceWindow{
+			{ID: "inactive", Start: 200, End: 300, Scope: MaintenanceScope{Tags: []string{"db"}}},
+			{ID: "other", Start: 0, End: 300, Scope: MaintenanceScope{TriggerID: "trigger2"}},
+			{ID: "active", Start: 0, End: 300, Scope: MaintenanceScope{Tags: []string{"production"}}},
+		}
+		So(GetActiveMaintenanceWindow(windows, "trigger1", tags, "", 100), ShouldEqual, windows[2])
+		So(GetActiveMaintenanceWindow(windows, "trigger3", nil, "", 100), ShouldBeNil)
+	})
+}
@@ -6,13 +6,18 @@ type CheckerMetrics struct {
 	RemoteMetrics          *CheckMetrics
 	MetricEventsChannelLen Histogram
 	MetricEventsHandleTime Timer
+	RemoteBreakerState     GaugeMap
+	RemoteRequestsInFlight GaugeMap
+	RemoteCacheHits        Meter
+	RemoteCacheMisses      Meter
 }
 
 // CheckMetrics is a collection of metrics for trigger checks
 type CheckMetrics struct {
-	CheckError           Meter
-	HandleError          Meter
-	TriggersCheckTime    Timer
-	TriggerCheckTime     TimerMap
-	TriggersToCheckCount Histogram
+	CheckError             Meter
+	HandleError            Meter
+	TriggersCheckTime      Timer
+	TriggerCheckTime       TimerMap
+	TriggersToCheckCount   Histogram
+	TriggersToCheckLatency TimerMap
 }
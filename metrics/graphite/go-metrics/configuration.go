@@ -42,17 +42,22 @@ func ConfigureCheckerMetrics(prefix string, remoteEnabled bool) *graphite.Checke
 	}
 	if remoteEnabled {
 		m.RemoteMetrics = configureCheckMetrics(prefix + ".remote")
+		m.RemoteBreakerState = newGaugeMap(metricNameWithPrefix(prefix, "remote.breakerState"))
+		m.RemoteRequestsInFlight = newGaugeMap(metricNameWithPrefix(prefix, "remote.requestsInFlight"))
+		m.RemoteCacheHits = registerMeter(metricNameWithPrefix(prefix, "remote.cache.hits"))
+		m.RemoteCacheMisses = registerMeter(metricNameWithPrefix(prefix, "remote.cache.misses"))
 	}
 	return m
 }
 
 func configureCheckMetrics(prefix string) *graphite.CheckMetrics {
 	return &graphite.CheckMetrics{
-		CheckError:           registerMeter(metricNameWithPrefix(prefix, "errors.check")),
-		HandleError:          registerMeter(metricNameWithPrefix(prefix, "errors.handle")),
-		TriggersCheckTime:    registerTimer(metricNameWithPrefix(prefix, "triggers")),
-		TriggerCheckTime:     newTimerMap(metricNameWithPrefix(prefix, "trigger")),
-		TriggersToCheckCount: registerHistogram(metricNameWithPrefix(prefix, "triggersToCheck")),
+		CheckError:             registerMeter(metricNameWithPrefix(prefix, "errors.check")),
+		HandleError:            registerMeter(metricNameWithPrefix(prefix, "errors.handle")),
+		TriggersCheckTime:      registerTimer(metricNameWithPrefix(prefix, "triggers")),
+		TriggerCheckTime:       newTimerMap(metricNameWithPrefix(prefix, "trigger")),
+		TriggersToCheckCount:   registerHistogram(metricNameWithPrefix(prefix, "triggersToCheck")),
+		TriggersToCheckLatency: newTimerMap(metricNameWithPrefix(prefix, "triggersToCheckLatency")),
 	}
 }
 
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/moira-alert/moira/metrics/graphite"
+)
+
+// GaugeMap is realization of metrics map of type Gauge
+type GaugeMap struct {
+	metrics map[string]*Gauge
+	addLock sync.Mutex
+	prefix  string
+}
+
+// newGaugeMap create empty Gauge map
+func newGaugeMap(prefix string) *GaugeMap {
+	return &GaugeMap{
+		metrics: make(map[string]*Gauge),
+		prefix:  prefix,
+	}
+}
+
+// GetOrAdd gets gauge and, if it does not exists, add it do map
+func (gaugeMap *GaugeMap) GetOrAdd(name, graphitePath string) graphite.Gauge {
+	if _, ok := gaugeMap.metrics[name]; !ok {
+		gaugeMap.addLock.Lock()
+		defer gaugeMap.addLock.Unlock()
+		if _, ok := gaugeMap.metrics[name]; !ok {
+			newMetricsMap := make(map[string]*Gauge, len(gaugeMap.metrics)+1)
+			for k, v := range gaugeMap.metrics {
+				newMetricsMap[k] = v
+			}
+			newMetricsMap[name] = registerGauge(metricNameWithPrefix(gaugeMap.prefix, strings.Replace(graphitePath, "-", "_", -1)))
+			gaugeMap.metrics = newMetricsMap
+		}
+	}
+	return gaugeMap.metrics[name]
+}
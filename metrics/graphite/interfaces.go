@@ -13,6 +13,11 @@ type TimerMap interface {
 	GetOrAdd(name, graphitePath string) Timer
 }
 
+// GaugeMap implements gauge collection abstraction
+type GaugeMap interface {
+	GetOrAdd(name, graphitePath string) Gauge
+}
+
 // Meter count events to produce exponentially-weighted moving average rates
 // at one-, five-, and fifteen-minutes and a mean rate.
 type Meter interface {
@@ -85,27 +85,63 @@ func (mr *MockDatabaseMockRecorder) AddPatternMetric(arg0, arg1 interface{}) *go
 }
 
 // AddRemoteTriggersToCheck mocks base method
-func (m *MockDatabase) AddRemoteTriggersToCheck(arg0 []string) error {
-	ret := m.ctrl.Call(m, "AddRemoteTriggersToCheck", arg0)
+func (m *MockDatabase) AddRemoteTriggersToCheck(arg0 []string, arg1 moira.TriggerCheckPriority) error {
+	ret := m.ctrl.Call(m, "AddRemoteTriggersToCheck", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddRemoteTriggersToCheck indicates an expected call of AddRemoteTriggersToCheck
-func (mr *MockDatabaseMockRecorder) AddRemoteTriggersToCheck(arg0 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRemoteTriggersToCheck", reflect.TypeOf((*MockDatabase)(nil).AddRemoteTriggersToCheck), arg0)
+func (mr *MockDatabaseMockRecorder) AddRemoteTriggersToCheck(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRemoteTriggersToCheck", reflect.TypeOf((*MockDatabase)(nil).AddRemoteTriggersToCheck), arg0, arg1)
+}
+
+// AddShardRemoteTriggersToCheck mocks base method
+func (m *MockDatabase) AddShardRemoteTriggersToCheck(arg0 string, arg1 []string, arg2 moira.TriggerCheckPriority) error {
+	ret := m.ctrl.Call(m, "AddShardRemoteTriggersToCheck", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddShardRemoteTriggersToCheck indicates an expected call of AddShardRemoteTriggersToCheck
+func (mr *MockDatabaseMockRecorder) AddShardRemoteTriggersToCheck(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddShardRemoteTriggersToCheck", reflect.TypeOf((*MockDatabase)(nil).AddShardRemoteTriggersToCheck), arg0, arg1, arg2)
+}
+
+// AddShardTriggersToCheck mocks base method
+func (m *MockDatabase) AddShardTriggersToCheck(arg0 string, arg1 []string, arg2 moira.TriggerCheckPriority) error {
+	ret := m.ctrl.Call(m, "AddShardTriggersToCheck", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddShardTriggersToCheck indicates an expected call of AddShardTriggersToCheck
+func (mr *MockDatabaseMockRecorder) AddShardTriggersToCheck(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddShardTriggersToCheck", reflect.TypeOf((*MockDatabase)(nil).AddShardTriggersToCheck), arg0, arg1, arg2)
+}
+
+// AddTriggerStateHistory mocks base method
+func (m *MockDatabase) AddTriggerStateHistory(arg0 string, arg1 []*moira.StateInterval, arg2 int64) error {
+	ret := m.ctrl.Call(m, "AddTriggerStateHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTriggerStateHistory indicates an expected call of AddTriggerStateHistory
+func (mr *MockDatabaseMockRecorder) AddTriggerStateHistory(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTriggerStateHistory", reflect.TypeOf((*MockDatabase)(nil).AddTriggerStateHistory), arg0, arg1, arg2)
 }
 
 // AddTriggersToCheck mocks base method
-func (m *MockDatabase) AddTriggersToCheck(arg0 []string) error {
-	ret := m.ctrl.Call(m, "AddTriggersToCheck", arg0)
+func (m *MockDatabase) AddTriggersToCheck(arg0 []string, arg1 moira.TriggerCheckPriority) error {
+	ret := m.ctrl.Call(m, "AddTriggersToCheck", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddTriggersToCheck indicates an expected call of AddTriggersToCheck
-func (mr *MockDatabaseMockRecorder) AddTriggersToCheck(arg0 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTriggersToCheck", reflect.TypeOf((*MockDatabase)(nil).AddTriggersToCheck), arg0)
+func (mr *MockDatabaseMockRecorder) AddTriggersToCheck(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTriggersToCheck", reflect.TypeOf((*MockDatabase)(nil).AddTriggersToCheck), arg0, arg1)
 }
 
 // DeleteTriggerCheckLock mocks base method
@@ -206,6 +242,19 @@ func (mr *MockDatabaseMockRecorder) GetAllTriggerIDs() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTriggerIDs", reflect.TypeOf((*MockDatabase)(nil).GetAllTriggerIDs))
 }
 
+// GetCheckerInstances mocks base method
+func (m *MockDatabase) GetCheckerInstances() (map[string]int64, error) {
+	ret := m.ctrl.Call(m, "GetCheckerInstances")
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCheckerInstances indicates an expected call of GetCheckerInstances
+func (mr *MockDatabaseMockRecorder) GetCheckerInstances() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCheckerInstances", reflect.TypeOf((*MockDatabase)(nil).GetCheckerInstances))
+}
+
 // GetChecksUpdatesCount mocks base method
 func (m *MockDatabase) GetChecksUpdatesCount() (int64, error) {
 	ret := m.ctrl.Call(m, "GetChecksUpdatesCount")
@@ -219,6 +268,19 @@ func (mr *MockDatabaseMockRecorder) GetChecksUpdatesCount() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChecksUpdatesCount", reflect.TypeOf((*MockDatabase)(nil).GetChecksUpdatesCount))
 }
 
+// GetCompositeTriggerIDs mocks base method
+func (m *MockDatabase) GetCompositeTriggerIDs(arg0 string) ([]string, error) {
+	ret := m.ctrl.Call(m, "GetCompositeTriggerIDs", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCompositeTriggerIDs indicates an expected call of GetCompositeTriggerIDs
+func (mr *MockDatabaseMockRecorder) GetCompositeTriggerIDs(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCompositeTriggerIDs", reflect.TypeOf((*MockDatabase)(nil).GetCompositeTriggerIDs), arg0)
+}
+
 // GetContact mocks base method
 func (m *MockDatabase) GetContact(arg0 string) (moira.ContactData, error) {
 	ret := m.ctrl.Call(m, "GetContact", arg0)
@@ -258,6 +320,32 @@ func (mr *MockDatabaseMockRecorder) GetIDByUsername(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIDByUsername", reflect.TypeOf((*MockDatabase)(nil).GetIDByUsername), arg0, arg1)
 }
 
+// GetMaintenanceWindow mocks base method
+func (m *MockDatabase) GetMaintenanceWindow(arg0 string) (moira.MaintenanceWindow, error) {
+	ret := m.ctrl.Call(m, "GetMaintenanceWindow", arg0)
+	ret0, _ := ret[0].(moira.MaintenanceWindow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMaintenanceWindow indicates an expected call of GetMaintenanceWindow
+func (mr *MockDatabaseMockRecorder) GetMaintenanceWindow(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaintenanceWindow", reflect.TypeOf((*MockDatabase)(nil).GetMaintenanceWindow), arg0)
+}
+
+// GetMaintenanceWindows mocks base method
+func (m *MockDatabase) GetMaintenanceWindows(arg0 int64) ([]*moira.MaintenanceWindow, error) {
+	ret := m.ctrl.Call(m, "GetMaintenanceWindows", arg0)
+	ret0, _ := ret[0].([]*moira.MaintenanceWindow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMaintenanceWindows indicates an expected call of GetMaintenanceWindows
+func (mr *MockDatabaseMockRecorder) GetMaintenanceWindows(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaintenanceWindows", reflect.TypeOf((*MockDatabase)(nil).GetMaintenanceWindows), arg0)
+}
+
 // GetMetricRetention mocks base method
 func (m *MockDatabase) GetMetricRetention(arg0 string) (int64, error) {
 	ret := m.ctrl.Call(m, "GetMetricRetention", arg0)
@@ -271,6 +359,19 @@ func (mr *MockDatabaseMockRecorder) GetMetricRetention(arg0 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetricRetention", reflect.TypeOf((*MockDatabase)(nil).GetMetricRetention), arg0)
 }
 
+// GetMetricsTTL mocks base method
+func (m *MockDatabase) GetMetricsTTL() (int64, error) {
+	ret := m.ctrl.Call(m, "GetMetricsTTL")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMetricsTTL indicates an expected call of GetMetricsTTL
+func (mr *MockDatabaseMockRecorder) GetMetricsTTL() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetricsTTL", reflect.TypeOf((*MockDatabase)(nil).GetMetricsTTL))
+}
+
 // GetMetricsUpdatesCount mocks base method
 func (m *MockDatabase) GetMetricsUpdatesCount() (int64, error) {
 	ret := m.ctrl.Call(m, "GetMetricsUpdatesCount")
@@ -388,36 +489,49 @@ func (mr *MockDatabaseMockRecorder) GetPatterns() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPatterns", reflect.TypeOf((*MockDatabase)(nil).GetPatterns))
 }
 
+// GetRemoteBreakerState mocks base method
+func (m *MockDatabase) GetRemoteBreakerState(arg0 string) (string, error) {
+	ret := m.ctrl.Call(m, "GetRemoteBreakerState", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRemoteBreakerState indicates an expected call of GetRemoteBreakerState
+func (mr *MockDatabaseMockRecorder) GetRemoteBreakerState(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemoteBreakerState", reflect.TypeOf((*MockDatabase)(nil).GetRemoteBreakerState), arg0)
+}
+
 // GetRemoteChecksUpdatesCount mocks base method
-func (m *MockDatabase) GetRemoteChecksUpdatesCount() (int64, error) {
-	ret := m.ctrl.Call(m, "GetRemoteChecksUpdatesCount")
+func (m *MockDatabase) GetRemoteChecksUpdatesCount(arg0 string) (int64, error) {
+	ret := m.ctrl.Call(m, "GetRemoteChecksUpdatesCount", arg0)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetRemoteChecksUpdatesCount indicates an expected call of GetRemoteChecksUpdatesCount
-func (mr *MockDatabaseMockRecorder) GetRemoteChecksUpdatesCount() *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemoteChecksUpdatesCount", reflect.TypeOf((*MockDatabase)(nil).GetRemoteChecksUpdatesCount))
+func (mr *MockDatabaseMockRecorder) GetRemoteChecksUpdatesCount(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemoteChecksUpdatesCount", reflect.TypeOf((*MockDatabase)(nil).GetRemoteChecksUpdatesCount), arg0)
 }
 
 // GetRemoteTriggerIDs mocks base method
-func (m *MockDatabase) GetRemoteTriggerIDs() ([]string, error) {
-	ret := m.ctrl.Call(m, "GetRemoteTriggerIDs")
+func (m *MockDatabase) GetRemoteTriggerIDs(arg0 string) ([]string, error) {
+	ret := m.ctrl.Call(m, "GetRemoteTriggerIDs", arg0)
 	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetRemoteTriggerIDs indicates an expected call of GetRemoteTriggerIDs
-func (mr *MockDatabaseMockRecorder) GetRemoteTriggerIDs() *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemoteTriggerIDs", reflect.TypeOf((*MockDatabase)(nil).GetRemoteTriggerIDs))
+func (mr *MockDatabaseMockRecorder) GetRemoteTriggerIDs(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemoteTriggerIDs", reflect.TypeOf((*MockDatabase)(nil).GetRemoteTriggerIDs), arg0)
 }
 
 // GetRemoteTriggerToCheck mocks base method
-func (m *MockDatabase) GetRemoteTriggerToCheck() (string, error) {
+func (m *MockDatabase) GetRemoteTriggerToCheck() (moira.TriggerToCheck, error) {
 	ret := m.ctrl.Call(m, "GetRemoteTriggerToCheck")
-	ret0, _ := ret[0].(string)
+	ret0, _ := ret[0].(moira.TriggerToCheck)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -440,6 +554,45 @@ func (mr *MockDatabaseMockRecorder) GetRemoteTriggersToCheckCount() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemoteTriggersToCheckCount", reflect.TypeOf((*MockDatabase)(nil).GetRemoteTriggersToCheckCount))
 }
 
+// GetRotation mocks base method
+func (m *MockDatabase) GetRotation(arg0 string) (moira.Rotation, error) {
+	ret := m.ctrl.Call(m, "GetRotation", arg0)
+	ret0, _ := ret[0].(moira.Rotation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRotation indicates an expected call of GetRotation
+func (mr *MockDatabaseMockRecorder) GetRotation(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRotation", reflect.TypeOf((*MockDatabase)(nil).GetRotation), arg0)
+}
+
+// GetShardRemoteTriggerToCheck mocks base method
+func (m *MockDatabase) GetShardRemoteTriggerToCheck(arg0 string) (moira.TriggerToCheck, error) {
+	ret := m.ctrl.Call(m, "GetShardRemoteTriggerToCheck", arg0)
+	ret0, _ := ret[0].(moira.TriggerToCheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShardRemoteTriggerToCheck indicates an expected call of GetShardRemoteTriggerToCheck
+func (mr *MockDatabaseMockRecorder) GetShardRemoteTriggerToCheck(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShardRemoteTriggerToCheck", reflect.TypeOf((*MockDatabase)(nil).GetShardRemoteTriggerToCheck), arg0)
+}
+
+// GetShardTriggerToCheck mocks base method
+func (m *MockDatabase) GetShardTriggerToCheck(arg0 string) (moira.TriggerToCheck, error) {
+	ret := m.ctrl.Call(m, "GetShardTriggerToCheck", arg0)
+	ret0, _ := ret[0].(moira.TriggerToCheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShardTriggerToCheck indicates an expected call of GetShardTriggerToCheck
+func (mr *MockDatabaseMockRecorder) GetShardTriggerToCheck(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShardTriggerToCheck", reflect.TypeOf((*MockDatabase)(nil).GetShardTriggerToCheck), arg0)
+}
+
 // GetSubscription mocks base method
 func (m *MockDatabase) GetSubscription(arg0 string) (moira.SubscriptionData, error) {
 	ret := m.ctrl.Call(m, "GetSubscription", arg0)
@@ -544,6 +697,19 @@ func (mr *MockDatabaseMockRecorder) GetTriggerChecks(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTriggerChecks", reflect.TypeOf((*MockDatabase)(nil).GetTriggerChecks), arg0)
 }
 
+// GetTriggerEscalations mocks base method
+func (m *MockDatabase) GetTriggerEscalations(arg0 string) ([]*moira.ScheduledNotification, error) {
+	ret := m.ctrl.Call(m, "GetTriggerEscalations", arg0)
+	ret0, _ := ret[0].([]*moira.ScheduledNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTriggerEscalations indicates an expected call of GetTriggerEscalations
+func (mr *MockDatabaseMockRecorder) GetTriggerEscalations(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTriggerEscalations", reflect.TypeOf((*MockDatabase)(nil).GetTriggerEscalations), arg0)
+}
+
 // GetTriggerIDs mocks base method
 func (m *MockDatabase) GetTriggerIDs() ([]string, error) {
 	ret := m.ctrl.Call(m, "GetTriggerIDs")
@@ -570,6 +736,19 @@ func (mr *MockDatabaseMockRecorder) GetTriggerLastCheck(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTriggerLastCheck", reflect.TypeOf((*MockDatabase)(nil).GetTriggerLastCheck), arg0)
 }
 
+// GetTriggerStateHistory mocks base method
+func (m *MockDatabase) GetTriggerStateHistory(arg0 string, arg1, arg2 int64) ([]*moira.StateInterval, error) {
+	ret := m.ctrl.Call(m, "GetTriggerStateHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*moira.StateInterval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTriggerStateHistory indicates an expected call of GetTriggerStateHistory
+func (mr *MockDatabaseMockRecorder) GetTriggerStateHistory(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTriggerStateHistory", reflect.TypeOf((*MockDatabase)(nil).GetTriggerStateHistory), arg0, arg1, arg2)
+}
+
 // GetTriggerThrottling mocks base method
 func (m *MockDatabase) GetTriggerThrottling(arg0 string) (time.Time, time.Time) {
 	ret := m.ctrl.Call(m, "GetTriggerThrottling", arg0)
@@ -584,9 +763,9 @@ func (mr *MockDatabaseMockRecorder) GetTriggerThrottling(arg0 interface{}) *gomo
 }
 
 // GetTriggerToCheck mocks base method
-func (m *MockDatabase) GetTriggerToCheck() (string, error) {
+func (m *MockDatabase) GetTriggerToCheck() (moira.TriggerToCheck, error) {
 	ret := m.ctrl.Call(m, "GetTriggerToCheck")
-	ret0, _ := ret[0].(string)
+	ret0, _ := ret[0].(moira.TriggerToCheck)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -635,6 +814,19 @@ func (mr *MockDatabaseMockRecorder) GetUserContactIDs(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserContactIDs", reflect.TypeOf((*MockDatabase)(nil).GetUserContactIDs), arg0)
 }
 
+// GetUserRotations mocks base method
+func (m *MockDatabase) GetUserRotations(arg0 string) ([]*moira.Rotation, error) {
+	ret := m.ctrl.Call(m, "GetUserRotations", arg0)
+	ret0, _ := ret[0].([]*moira.Rotation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRotations indicates an expected call of GetUserRotations
+func (mr *MockDatabaseMockRecorder) GetUserRotations(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRotations", reflect.TypeOf((*MockDatabase)(nil).GetUserRotations), arg0)
+}
+
 // GetUserSubscriptionIDs mocks base method
 func (m *MockDatabase) GetUserSubscriptionIDs(arg0 string) ([]string, error) {
 	ret := m.ctrl.Call(m, "GetUserSubscriptionIDs", arg0)
@@ -648,6 +840,30 @@ func (mr *MockDatabaseMockRecorder) GetUserSubscriptionIDs(arg0 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSubscriptionIDs", reflect.TypeOf((*MockDatabase)(nil).GetUserSubscriptionIDs), arg0)
 }
 
+// MigrateTriggersToCheck mocks base method
+func (m *MockDatabase) MigrateTriggersToCheck() error {
+	ret := m.ctrl.Call(m, "MigrateTriggersToCheck")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MigrateTriggersToCheck indicates an expected call of MigrateTriggersToCheck
+func (mr *MockDatabaseMockRecorder) MigrateTriggersToCheck() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MigrateTriggersToCheck", reflect.TypeOf((*MockDatabase)(nil).MigrateTriggersToCheck))
+}
+
+// PublishStreamEvent mocks base method
+func (m *MockDatabase) PublishStreamEvent(arg0 *moira.StreamEvent) error {
+	ret := m.ctrl.Call(m, "PublishStreamEvent", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishStreamEvent indicates an expected call of PublishStreamEvent
+func (mr *MockDatabaseMockRecorder) PublishStreamEvent(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishStreamEvent", reflect.TypeOf((*MockDatabase)(nil).PublishStreamEvent), arg0)
+}
+
 // PushNotificationEvent mocks base method
 func (m *MockDatabase) PushNotificationEvent(arg0 *moira.NotificationEvent, arg1 bool) error {
 	ret := m.ctrl.Call(m, "PushNotificationEvent", arg0, arg1)
@@ -696,6 +912,18 @@ func (mr *MockDatabaseMockRecorder) RemoveAllNotifications() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAllNotifications", reflect.TypeOf((*MockDatabase)(nil).RemoveAllNotifications))
 }
 
+// RemoveCheckerInstance mocks base method
+func (m *MockDatabase) RemoveCheckerInstance(arg0 string) error {
+	ret := m.ctrl.Call(m, "RemoveCheckerInstance", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveCheckerInstance indicates an expected call of RemoveCheckerInstance
+func (mr *MockDatabaseMockRecorder) RemoveCheckerInstance(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCheckerInstance", reflect.TypeOf((*MockDatabase)(nil).RemoveCheckerInstance), arg0)
+}
+
 // RemoveContact mocks base method
 func (m *MockDatabase) RemoveContact(arg0 string) error {
 	ret := m.ctrl.Call(m, "RemoveContact", arg0)
@@ -708,6 +936,18 @@ func (mr *MockDatabaseMockRecorder) RemoveContact(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveContact", reflect.TypeOf((*MockDatabase)(nil).RemoveContact), arg0)
 }
 
+// RemoveMaintenanceWindow mocks base method
+func (m *MockDatabase) RemoveMaintenanceWindow(arg0 string) error {
+	ret := m.ctrl.Call(m, "RemoveMaintenanceWindow", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveMaintenanceWindow indicates an expected call of RemoveMaintenanceWindow
+func (mr *MockDatabaseMockRecorder) RemoveMaintenanceWindow(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMaintenanceWindow", reflect.TypeOf((*MockDatabase)(nil).RemoveMaintenanceWindow), arg0)
+}
+
 // RemoveMetricValues mocks base method
 func (m *MockDatabase) RemoveMetricValues(arg0 string, arg1 int64) error {
 	ret := m.ctrl.Call(m, "RemoveMetricValues", arg0, arg1)
@@ -793,6 +1033,18 @@ func (mr *MockDatabaseMockRecorder) RemovePatternsMetrics(arg0 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePatternsMetrics", reflect.TypeOf((*MockDatabase)(nil).RemovePatternsMetrics), arg0)
 }
 
+// RemoveRotation mocks base method
+func (m *MockDatabase) RemoveRotation(arg0 string) error {
+	ret := m.ctrl.Call(m, "RemoveRotation", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveRotation indicates an expected call of RemoveRotation
+func (mr *MockDatabaseMockRecorder) RemoveRotation(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveRotation", reflect.TypeOf((*MockDatabase)(nil).RemoveRotation), arg0)
+}
+
 // RemoveSubscription mocks base method
 func (m *MockDatabase) RemoveSubscription(arg0 string) error {
 	ret := m.ctrl.Call(m, "RemoveSubscription", arg0)
@@ -877,6 +1129,18 @@ func (mr *MockDatabaseMockRecorder) SaveContact(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveContact", reflect.TypeOf((*MockDatabase)(nil).SaveContact), arg0)
 }
 
+// SaveMaintenanceWindow mocks base method
+func (m *MockDatabase) SaveMaintenanceWindow(arg0 *moira.MaintenanceWindow) error {
+	ret := m.ctrl.Call(m, "SaveMaintenanceWindow", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveMaintenanceWindow indicates an expected call of SaveMaintenanceWindow
+func (mr *MockDatabaseMockRecorder) SaveMaintenanceWindow(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveMaintenanceWindow", reflect.TypeOf((*MockDatabase)(nil).SaveMaintenanceWindow), arg0)
+}
+
 // SaveMetrics mocks base method
 func (m *MockDatabase) SaveMetrics(arg0 map[string]*moira.MatchedMetric) error {
 	ret := m.ctrl.Call(m, "SaveMetrics", arg0)
@@ -889,6 +1153,18 @@ func (mr *MockDatabaseMockRecorder) SaveMetrics(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveMetrics", reflect.TypeOf((*MockDatabase)(nil).SaveMetrics), arg0)
 }
 
+// SaveRotation mocks base method
+func (m *MockDatabase) SaveRotation(arg0 *moira.Rotation) error {
+	ret := m.ctrl.Call(m, "SaveRotation", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveRotation indicates an expected call of SaveRotation
+func (mr *MockDatabaseMockRecorder) SaveRotation(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRotation", reflect.TypeOf((*MockDatabase)(nil).SaveRotation), arg0)
+}
+
 // SaveSubscription mocks base method
 func (m *MockDatabase) SaveSubscription(arg0 *moira.SubscriptionData) error {
 	ret := m.ctrl.Call(m, "SaveSubscription", arg0)
@@ -925,6 +1201,30 @@ func (mr *MockDatabaseMockRecorder) SaveTrigger(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTrigger", reflect.TypeOf((*MockDatabase)(nil).SaveTrigger), arg0, arg1)
 }
 
+// SetCheckerHeartbeat mocks base method
+func (m *MockDatabase) SetCheckerHeartbeat(arg0 string) error {
+	ret := m.ctrl.Call(m, "SetCheckerHeartbeat", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCheckerHeartbeat indicates an expected call of SetCheckerHeartbeat
+func (mr *MockDatabaseMockRecorder) SetCheckerHeartbeat(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCheckerHeartbeat", reflect.TypeOf((*MockDatabase)(nil).SetCheckerHeartbeat), arg0)
+}
+
+// SetMetricsTTL mocks base method
+func (m *MockDatabase) SetMetricsTTL(arg0 int64) error {
+	ret := m.ctrl.Call(m, "SetMetricsTTL", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMetricsTTL indicates an expected call of SetMetricsTTL
+func (mr *MockDatabaseMockRecorder) SetMetricsTTL(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMetricsTTL", reflect.TypeOf((*MockDatabase)(nil).SetMetricsTTL), arg0)
+}
+
 // SetNotifierState mocks base method
 func (m *MockDatabase) SetNotifierState(arg0 string) error {
 	ret := m.ctrl.Call(m, "SetNotifierState", arg0)
@@ -937,6 +1237,30 @@ func (mr *MockDatabaseMockRecorder) SetNotifierState(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNotifierState", reflect.TypeOf((*MockDatabase)(nil).SetNotifierState), arg0)
 }
 
+// SetRemoteBreakerState mocks base method
+func (m *MockDatabase) SetRemoteBreakerState(arg0, arg1 string) error {
+	ret := m.ctrl.Call(m, "SetRemoteBreakerState", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRemoteBreakerState indicates an expected call of SetRemoteBreakerState
+func (mr *MockDatabaseMockRecorder) SetRemoteBreakerState(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRemoteBreakerState", reflect.TypeOf((*MockDatabase)(nil).SetRemoteBreakerState), arg0, arg1)
+}
+
+// SetTriggerCheckAcknowledgement mocks base method
+func (m *MockDatabase) SetTriggerCheckAcknowledgement(arg0, arg1 string, arg2 *moira.Acknowledgement) error {
+	ret := m.ctrl.Call(m, "SetTriggerCheckAcknowledgement", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTriggerCheckAcknowledgement indicates an expected call of SetTriggerCheckAcknowledgement
+func (mr *MockDatabaseMockRecorder) SetTriggerCheckAcknowledgement(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTriggerCheckAcknowledgement", reflect.TypeOf((*MockDatabase)(nil).SetTriggerCheckAcknowledgement), arg0, arg1, arg2)
+}
+
 // SetTriggerCheckLock mocks base method
 func (m *MockDatabase) SetTriggerCheckLock(arg0 string) (bool, error) {
 	ret := m.ctrl.Call(m, "SetTriggerCheckLock", arg0)
@@ -963,7 +1287,7 @@ func (mr *MockDatabaseMockRecorder) SetTriggerCheckMetricsMaintenance(arg0, arg1
 }
 
 // SetTriggerLastCheck mocks base method
-func (m *MockDatabase) SetTriggerLastCheck(arg0 string, arg1 *moira.CheckData, arg2 bool) error {
+func (m *MockDatabase) SetTriggerLastCheck(arg0 string, arg1 *moira.CheckData, arg2 string) error {
 	ret := m.ctrl.Call(m, "SetTriggerLastCheck", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
@@ -974,6 +1298,18 @@ func (mr *MockDatabaseMockRecorder) SetTriggerLastCheck(arg0, arg1, arg2 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTriggerLastCheck", reflect.TypeOf((*MockDatabase)(nil).SetTriggerLastCheck), arg0, arg1, arg2)
 }
 
+// SetTriggerMaintenance mocks base method
+func (m *MockDatabase) SetTriggerMaintenance(arg0 string, arg1 int64) error {
+	ret := m.ctrl.Call(m, "SetTriggerMaintenance", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTriggerMaintenance indicates an expected call of SetTriggerMaintenance
+func (mr *MockDatabaseMockRecorder) SetTriggerMaintenance(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTriggerMaintenance", reflect.TypeOf((*MockDatabase)(nil).SetTriggerMaintenance), arg0, arg1)
+}
+
 // SetTriggerThrottling mocks base method
 func (m *MockDatabase) SetTriggerThrottling(arg0 string, arg1 time.Time) error {
 	ret := m.ctrl.Call(m, "SetTriggerThrottling", arg0, arg1)
@@ -1011,6 +1347,19 @@ func (mr *MockDatabaseMockRecorder) SubscribeMetricEvents(arg0 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeMetricEvents", reflect.TypeOf((*MockDatabase)(nil).SubscribeMetricEvents), arg0)
 }
 
+// SubscribeStreamEvents mocks base method
+func (m *MockDatabase) SubscribeStreamEvents(arg0 *tomb_v2.Tomb) (<-chan *moira.StreamEvent, error) {
+	ret := m.ctrl.Call(m, "SubscribeStreamEvents", arg0)
+	ret0, _ := ret[0].(<-chan *moira.StreamEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeStreamEvents indicates an expected call of SubscribeStreamEvents
+func (mr *MockDatabaseMockRecorder) SubscribeStreamEvents(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeStreamEvents", reflect.TypeOf((*MockDatabase)(nil).SubscribeStreamEvents), arg0)
+}
+
 // UpdateMetricsHeartbeat mocks base method
 func (m *MockDatabase) UpdateMetricsHeartbeat() error {
 	ret := m.ctrl.Call(m, "UpdateMetricsHeartbeat")
@@ -108,22 +108,11 @@ func (worker *FetchEventsWorker) processEvent(event moira.NotificationEvent) err
 
 	for _, subscription := range subscriptions {
 		if worker.isNotificationRequired(subscription, triggerData, event) {
-			for _, contactID := range subscription.Contacts {
-				contact, err := worker.Database.GetContact(contactID)
-				if err != nil {
-					worker.Logger.Warningf("Failed to get contact: %s, skip handling it, error: %v", contactID, err)
-					continue
-				}
-				event.SubscriptionID = &subscription.ID
-				notification := worker.Scheduler.ScheduleNotification(time.Now(), event, triggerData, contact, false, 0)
-				key := notification.GetKey()
-				if _, exist := duplications[key]; !exist {
-					if err := worker.Database.AddNotification(notification); err != nil {
-						worker.Logger.Errorf("Failed to save scheduled notification: %s", err)
-					}
-					duplications[key] = true
-				} else {
-					worker.Logger.Debugf("Skip duplicated notification for contact %s", notification.Contact)
+			event.SubscriptionID = &subscription.ID
+			worker.scheduleNotifications(subscription.Contacts, event, triggerData, 0, 0, duplications)
+			if event.NeedEscalation() {
+				for i, step := range subscription.Escalations {
+					worker.scheduleNotifications(step.Contacts, event, triggerData, time.Duration(step.Delay)*time.Second, i+1, duplications)
 				}
 			}
 		}
@@ -131,6 +120,29 @@ func (worker *FetchEventsWorker) processEvent(event moira.NotificationEvent) err
 	return nil
 }
 
+// scheduleNotifications schedules notifications of contacts about event after delay,
+// escalation notifications are cancelled before sending if alert is acknowledged or recovered
+func (worker *FetchEventsWorker) scheduleNotifications(contactIDs []string, event moira.NotificationEvent, triggerData moira.TriggerData, delay time.Duration, escalationStep int, duplications map[string]bool) {
+	for _, contactID := range contactIDs {
+		contact, err := worker.Database.GetContact(contactID)
+		if err != nil {
+			worker.Logger.Warningf("Failed to get contact: %s, skip handling it, error: %v", contactID, err)
+			continue
+		}
+		notification := worker.Scheduler.ScheduleNotification(time.Now().Add(delay), event, triggerData, contact, false, 0)
+		notification.EscalationStep = escalationStep
+		key := notification.GetKey()
+		if _, exist := duplications[key]; !exist {
+			if err := worker.Database.AddNotification(notification); err != nil {
+				worker.Logger.Errorf("Failed to save scheduled notification: %s", err)
+			}
+			duplications[key] = true
+		} else {
+			worker.Logger.Debugf("Skip duplicated notification for contact %s", notification.Contact)
+		}
+	}
+}
+
 func (worker *FetchEventsWorker) getNotificationSubscriptions(event moira.NotificationEvent) (*moira.SubscriptionData, error) {
 	if event.SubscriptionID != nil {
 		worker.Logger.Debugf("Getting subscriptionID %s for test message", *event.SubscriptionID)
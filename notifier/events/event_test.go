@@ -289,6 +289,71 @@ func TestAddNotification(t *testing.T) {
 	})
 }
 
+func TestAddEscalationNotifications(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	logger, _ := logging.GetLogger("Events")
+	scheduler := mock_scheduler.NewMockScheduler(mockCtrl)
+	worker := FetchEventsWorker{
+		Database:  dataBase,
+		Logger:    logger,
+		Metrics:   metrics2,
+		Scheduler: scheduler,
+	}
+	escalationContact := moira.ContactData{
+		ID:    "ContactID-000000000000005",
+		Type:  "email",
+		Value: "mail5@example.com",
+	}
+	escalationSubscription := subscription
+	escalationSubscription.Escalations = []moira.EscalationStep{{Contacts: []string{escalationContact.ID}, Delay: 600}}
+
+	Convey("Alert is escalated", t, func() {
+		event := moira.NotificationEvent{
+			Metric:         "generate.event.1",
+			State:          "ERROR",
+			OldState:       "OK",
+			TriggerID:      triggerData.ID,
+			SubscriptionID: &escalationSubscription.ID,
+		}
+		notification := moira.ScheduledNotification{Contact: contact}
+		escalationNotification := moira.ScheduledNotification{Contact: escalationContact}
+
+		dataBase.EXPECT().GetTrigger(event.TriggerID).Return(trigger, nil)
+		dataBase.EXPECT().GetTagsSubscriptions(triggerData.Tags).Return([]*moira.SubscriptionData{&escalationSubscription}, nil)
+		dataBase.EXPECT().GetContact(contact.ID).Return(contact, nil)
+		dataBase.EXPECT().GetContact(escalationContact.ID).Return(escalationContact, nil)
+		scheduler.EXPECT().ScheduleNotification(gomock.Any(), event, triggerData, contact, false, 0).Return(&notification)
+		scheduler.EXPECT().ScheduleNotification(gomock.Any(), event, triggerData, escalationContact, false, 0).Return(&escalationNotification)
+		dataBase.EXPECT().AddNotification(&moira.ScheduledNotification{Contact: contact}).Return(nil)
+		dataBase.EXPECT().AddNotification(&moira.ScheduledNotification{Contact: escalationContact, EscalationStep: 1}).Return(nil)
+
+		err := worker.processEvent(event)
+		So(err, ShouldBeEmpty)
+	})
+
+	Convey("Recovery is not escalated", t, func() {
+		event := moira.NotificationEvent{
+			Metric:         "generate.event.1",
+			State:          "OK",
+			OldState:       "ERROR",
+			TriggerID:      triggerData.ID,
+			SubscriptionID: &escalationSubscription.ID,
+		}
+		notification := moira.ScheduledNotification{Contact: contact}
+
+		dataBase.EXPECT().GetTrigger(event.TriggerID).Return(trigger, nil)
+		dataBase.EXPECT().GetTagsSubscriptions(triggerData.Tags).Return([]*moira.SubscriptionData{&escalationSubscription}, nil)
+		dataBase.EXPECT().GetContact(contact.ID).Return(contact, nil)
+		scheduler.EXPECT().ScheduleNotification(gomock.Any(), event, triggerData, contact, false, 0).Return(&notification)
+		dataBase.EXPECT().AddNotification(&notification).Return(nil)
+
+		err := worker.processEvent(event)
+		So(err, ShouldBeEmpty)
+	})
+}
+
 func TestAddOneNotificationByTwoSubscriptionsWithSame(t *testing.T) {
 	Convey("When good subscription and create 2 same scheduled notifications, should add one new notification", t, func() {
 		mockCtrl := gomock.NewController(t)
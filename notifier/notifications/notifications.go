@@ -8,6 +8,7 @@ import (
 	"gopkg.in/tomb.v2"
 
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
 	"github.com/moira-alert/moira/notifier"
 )
 
@@ -54,21 +55,25 @@ func (worker *FetchNotificationsWorker) processScheduledNotifications() error {
 	if err != nil {
 		return fmt.Errorf("can't get current notifier state")
 	}
-	if state != "OK" {
+	if !moira.GetSeverityModel().IsOK(state) {
 		return fmt.Errorf("stop sending notifications. Current notifier state: %v", state)
 	}
 
+	notifications = worker.filterCancelledEscalations(notifications)
+	notifications = worker.resolveRotationContacts(notifications, time.Now().Unix())
+
 	notificationPackages := make(map[string]*notifier.NotificationPackage)
 	for _, notification := range notifications {
-		packageKey := fmt.Sprintf("%s:%s:%s", notification.Contact.Type, notification.Contact.Value, notification.Event.TriggerID)
+		packageKey := fmt.Sprintf("%s:%s:%s:%d", notification.Contact.Type, notification.Contact.Value, notification.Event.TriggerID, notification.EscalationStep)
 		p, found := notificationPackages[packageKey]
 		if !found {
 			p = &notifier.NotificationPackage{
-				Events:    make([]moira.NotificationEvent, 0, len(notifications)),
-				Trigger:   notification.Trigger,
-				Contact:   notification.Contact,
-				Throttled: notification.Throttled,
-				FailCount: notification.SendFail,
+				Events:         make([]moira.NotificationEvent, 0, len(notifications)),
+				Trigger:        notification.Trigger,
+				Contact:        notification.Contact,
+				Throttled:      notification.Throttled,
+				FailCount:      notification.SendFail,
+				EscalationStep: notification.EscalationStep,
 			}
 		}
 		p.Events = append(p.Events, notification.Event)
@@ -81,3 +86,80 @@ func (worker *FetchNotificationsWorker) processScheduledNotifications() error {
 	sendingWG.Wait()
 	return nil
 }
+
+// filterCancelledEscalations removes escalation notifications about alerts which are already acknowledged or recovered
+func (worker *FetchNotificationsWorker) filterCancelledEscalations(notifications []*moira.ScheduledNotification) []*moira.ScheduledNotification {
+	lastChecks := make(map[string]*moira.CheckData)
+	filtered := make([]*moira.ScheduledNotification, 0, len(notifications))
+	for _, notification := range notifications {
+		if notification.EscalationStep == 0 {
+			filtered = append(filtered, notification)
+			continue
+		}
+		triggerID := notification.Event.TriggerID
+		lastCheck, found := lastChecks[triggerID]
+		if !found {
+			checkData, err := worker.Database.GetTriggerLastCheck(triggerID)
+			if err != nil {
+				if err != database.ErrNil {
+					worker.Logger.Warningf("Failed to get trigger %s last check: %s", triggerID, err.Error())
+				}
+			} else {
+				lastCheck = &checkData
+			}
+			lastChecks[triggerID] = lastCheck
+		}
+		if lastCheck == nil || !lastCheck.IsEscalationActual(&notification.Event) {
+			worker.Logger.Debugf("Escalation step %d of trigger %s metric %s is cancelled", notification.EscalationStep, triggerID, notification.Event.Metric)
+			continue
+		}
+		filtered = append(filtered, notification)
+	}
+	return filtered
+}
+
+// resolveRotationContacts replaces rotation contacts of notifications with contacts of rotations participants on call at timestamp.
+// Notification is left unresolved if on-call contact can not be got, so it is resent later
+func (worker *FetchNotificationsWorker) resolveRotationContacts(notifications []*moira.ScheduledNotification, timestamp int64) []*moira.ScheduledNotification {
+	onCallContacts := make(map[string]*moira.ContactData)
+	resolved := make([]*moira.ScheduledNotification, 0, len(notifications))
+	for _, notification := range notifications {
+		if notification.Contact.Type != moira.RotationContactType {
+			resolved = append(resolved, notification)
+			continue
+		}
+		rotationID := notification.Contact.Value
+		contact, found := onCallContacts[rotationID]
+		if !found {
+			contact = worker.getOnCallContact(rotationID, timestamp)
+			onCallContacts[rotationID] = contact
+		}
+		if contact == nil {
+			resolved = append(resolved, notification)
+			continue
+		}
+		resolvedNotification := *notification
+		resolvedNotification.Contact = *contact
+		resolved = append(resolved, &resolvedNotification)
+	}
+	return resolved
+}
+
+func (worker *FetchNotificationsWorker) getOnCallContact(rotationID string, timestamp int64) *moira.ContactData {
+	rotation, err := worker.Database.GetRotation(rotationID)
+	if err != nil {
+		worker.Logger.Warningf("Failed to get rotation %s: %s", rotationID, err.Error())
+		return nil
+	}
+	contactID := rotation.GetOnCall(timestamp)
+	if contactID == "" {
+		worker.Logger.Warningf("Rotation %s has no participant on call", rotationID)
+		return nil
+	}
+	contact, err := worker.Database.GetContact(contactID)
+	if err != nil {
+		worker.Logger.Warningf("Failed to get contact %s of rotation %s participant on call: %s", contactID, rotationID, err.Error())
+		return nil
+	}
+	return &contact
+}
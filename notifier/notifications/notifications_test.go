@@ -9,6 +9,7 @@ import (
 	. "github.com/smartystreets/goconvey/convey"
 
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
 	"github.com/moira-alert/moira/mock/moira-alert"
 	mock_notifier "github.com/moira-alert/moira/mock/notifier"
 	notifier2 "github.com/moira-alert/moira/notifier"
@@ -121,6 +122,131 @@ func TestProcessScheduledEvent(t *testing.T) {
 	})
 }
 
+func TestProcessEscalationNotifications(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	notifier := mock_notifier.NewMockNotifier(mockCtrl)
+	logger, _ := logging.GetLogger("Notification")
+	worker := &FetchNotificationsWorker{
+		Database: dataBase,
+		Logger:   logger,
+		Notifier: notifier,
+	}
+
+	triggerID := "triggerID-00000000000001"
+	escalation := moira.ScheduledNotification{
+		Event: moira.NotificationEvent{
+			TriggerID: triggerID,
+			Metric:    "metric",
+			State:     "ERROR",
+			OldState:  "OK",
+		},
+		Contact:        contact1,
+		Timestamp:      1441188915,
+		EscalationStep: 1,
+	}
+	pkg := notifier2.NotificationPackage{
+		Trigger: escalation.Trigger,
+		Contact: escalation.Contact,
+		Events: []moira.NotificationEvent{
+			escalation.Event,
+		},
+		EscalationStep: escalation.EscalationStep,
+	}
+
+	Convey("Escalation of actual alert is sent", t, func() {
+		dataBase.EXPECT().FetchNotifications(gomock.Any()).Return([]*moira.ScheduledNotification{&escalation}, nil)
+		dataBase.EXPECT().GetNotifierState().Return("OK", nil)
+		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{
+			Metrics: map[string]moira.MetricState{"metric": {State: "ERROR"}},
+		}, nil)
+		notifier.EXPECT().Send(&pkg, gomock.Any())
+		err := worker.processScheduledNotifications()
+		So(err, ShouldBeEmpty)
+	})
+
+	Convey("Escalation of recovered alert is cancelled", t, func() {
+		dataBase.EXPECT().FetchNotifications(gomock.Any()).Return([]*moira.ScheduledNotification{&escalation}, nil)
+		dataBase.EXPECT().GetNotifierState().Return("OK", nil)
+		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{
+			Metrics: map[string]moira.MetricState{"metric": {State: "OK"}},
+		}, nil)
+		err := worker.processScheduledNotifications()
+		So(err, ShouldBeEmpty)
+	})
+
+	Convey("Escalation of acknowledged alert is cancelled", t, func() {
+		dataBase.EXPECT().FetchNotifications(gomock.Any()).Return([]*moira.ScheduledNotification{&escalation}, nil)
+		dataBase.EXPECT().GetNotifierState().Return("OK", nil)
+		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{
+			Metrics: map[string]moira.MetricState{"metric": {State: "ERROR", Acknowledgement: &moira.Acknowledgement{User: "user", State: "ERROR"}}},
+		}, nil)
+		err := worker.processScheduledNotifications()
+		So(err, ShouldBeEmpty)
+	})
+}
+
+func TestProcessRotationNotifications(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	notifier := mock_notifier.NewMockNotifier(mockCtrl)
+	logger, _ := logging.GetLogger("Notification")
+	worker := &FetchNotificationsWorker{
+		Database: dataBase,
+		Logger:   logger,
+		Notifier: notifier,
+	}
+
+	rotationContact := moira.ContactData{
+		ID:    "ContactID-000000000000007",
+		Type:  moira.RotationContactType,
+		Value: "rotation1",
+	}
+	notification := moira.ScheduledNotification{
+		Event: moira.NotificationEvent{
+			TriggerID: "triggerID-00000000000001",
+			State:     "ERROR",
+			OldState:  "OK",
+		},
+		Contact:   rotationContact,
+		Timestamp: 1441188915,
+	}
+	rotation := moira.Rotation{
+		ID:           "rotation1",
+		Participants: []string{contact1.ID},
+		Length:       604800,
+	}
+
+	Convey("Rotation contact is resolved to on-call participant contact", t, func() {
+		dataBase.EXPECT().FetchNotifications(gomock.Any()).Return([]*moira.ScheduledNotification{&notification}, nil)
+		dataBase.EXPECT().GetNotifierState().Return("OK", nil)
+		dataBase.EXPECT().GetRotation(rotation.ID).Return(rotation, nil)
+		dataBase.EXPECT().GetContact(contact1.ID).Return(contact1, nil)
+		notifier.EXPECT().Send(&notifier2.NotificationPackage{
+			Trigger: notification.Trigger,
+			Contact: contact1,
+			Events:  []moira.NotificationEvent{notification.Event},
+		}, gomock.Any())
+		err := worker.processScheduledNotifications()
+		So(err, ShouldBeEmpty)
+	})
+
+	Convey("Unresolved rotation contact is sent as is", t, func() {
+		dataBase.EXPECT().FetchNotifications(gomock.Any()).Return([]*moira.ScheduledNotification{&notification}, nil)
+		dataBase.EXPECT().GetNotifierState().Return("OK", nil)
+		dataBase.EXPECT().GetRotation(rotation.ID).Return(moira.Rotation{}, database.ErrNil)
+		notifier.EXPECT().Send(&notifier2.NotificationPackage{
+			Trigger: notification.Trigger,
+			Contact: rotationContact,
+			Events:  []moira.NotificationEvent{notification.Event},
+		}, gomock.Any())
+		err := worker.processScheduledNotifications()
+		So(err, ShouldBeEmpty)
+	})
+}
+
 func TestGoRoutine(t *testing.T) {
 	subID5 := "subscriptionID-00000000000005"
 
@@ -11,12 +11,13 @@ import (
 
 // NotificationPackage represent sending data
 type NotificationPackage struct {
-	Events     []moira.NotificationEvent
-	Trigger    moira.TriggerData
-	Contact    moira.ContactData
-	FailCount  int
-	Throttled  bool
-	DontResend bool
+	Events         []moira.NotificationEvent
+	Trigger        moira.TriggerData
+	Contact        moira.ContactData
+	FailCount      int
+	Throttled      bool
+	DontResend     bool
+	EscalationStep int
 }
 
 func (pkg NotificationPackage) String() string {
@@ -98,6 +99,7 @@ func (notifier *StandardNotifier) resend(pkg *NotificationPackage, reason string
 	} else {
 		for _, event := range pkg.Events {
 			notification := notifier.scheduler.ScheduleNotification(time.Now(), event, pkg.Trigger, pkg.Contact, pkg.Throttled, pkg.FailCount+1)
+			notification.EscalationStep = pkg.EscalationStep
 			if err := notifier.database.AddNotification(notification); err != nil {
 				notifier.logger.Errorf("Failed to save scheduled notification: %s", err)
 			}
@@ -7,12 +7,18 @@ import (
 	// "git.skbkontur.ru/devops/kontur"
 
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders/discord"
 	"github.com/moira-alert/moira/senders/mail"
+	"github.com/moira-alert/moira/senders/mattermost"
+	"github.com/moira-alert/moira/senders/msteams"
+	"github.com/moira-alert/moira/senders/opsgenie"
+	"github.com/moira-alert/moira/senders/pagerduty"
 	"github.com/moira-alert/moira/senders/pushover"
 	"github.com/moira-alert/moira/senders/script"
 	"github.com/moira-alert/moira/senders/slack"
 	"github.com/moira-alert/moira/senders/telegram"
 	"github.com/moira-alert/moira/senders/twilio"
+	"github.com/moira-alert/moira/senders/webhook"
 )
 
 // RegisterSenders watch on senders config and register all configured senders
@@ -48,6 +54,30 @@ func (notifier *StandardNotifier) RegisterSenders(connector moira.Database) erro
 			if err := notifier.RegisterSender(senderSettings, &twilio.Sender{}); err != nil {
 				notifier.logger.Fatalf("Can not register sender %s: %s", senderSettings["type"], err)
 			}
+		case "discord":
+			if err := notifier.RegisterSender(senderSettings, &discord.Sender{}); err != nil {
+				notifier.logger.Fatalf("Can not register sender %s: %s", senderSettings["type"], err)
+			}
+		case "mattermost":
+			if err := notifier.RegisterSender(senderSettings, &mattermost.Sender{}); err != nil {
+				notifier.logger.Fatalf("Can not register sender %s: %s", senderSettings["type"], err)
+			}
+		case "msteams":
+			if err := notifier.RegisterSender(senderSettings, &msteams.Sender{}); err != nil {
+				notifier.logger.Fatalf("Can not register sender %s: %s", senderSettings["type"], err)
+			}
+		case "opsgenie":
+			if err := notifier.RegisterSender(senderSettings, &opsgenie.Sender{}); err != nil {
+				notifier.logger.Fatalf("Can not register sender %s: %s", senderSettings["type"], err)
+			}
+		case "pagerduty":
+			if err := notifier.RegisterSender(senderSettings, &pagerduty.Sender{}); err != nil {
+				notifier.logger.Fatalf("Can not register sender %s: %s", senderSettings["type"], err)
+			}
+		case "webhook":
+			if err := notifier.RegisterSender(senderSettings, &webhook.Sender{}); err != nil {
+				notifier.logger.Fatalf("Can not register sender %s: %s", senderSettings["type"], err)
+			}
 		// case "email":
 		// 	if err := notifier.RegisterSender(senderSettings, &kontur.MailSender{}); err != nil {
 		// 	}
@@ -64,7 +94,7 @@ func (notifier *StandardNotifier) RegisterSenders(connector moira.Database) erro
 // RegisterSender adds sender for notification type and registers metrics
 func (notifier *StandardNotifier) RegisterSender(senderSettings map[string]string, sender moira.Sender) error {
 	var senderIdent string
-	if senderSettings["type"] == "script" {
+	if senderSettings["type"] == "script" || senderSettings["type"] == "webhook" {
 		senderIdent = senderSettings["name"]
 	} else {
 		senderIdent = senderSettings["type"]
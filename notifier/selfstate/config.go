@@ -7,7 +7,7 @@ import (
 // Config is representation of self state worker settings like moira admins contacts and threshold values for checked services
 type Config struct {
 	Enabled                        bool
-	RemoteTriggersEnabled          bool
+	RemoteSources                  []string
 	RedisDisconnectDelaySeconds    int64
 	LastMetricReceivedDelaySeconds int64
 	LastCheckDelaySeconds          int64
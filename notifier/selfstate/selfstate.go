@@ -10,15 +10,15 @@ import (
 
 	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/notifier"
+	"github.com/moira-alert/moira/remote"
 )
 
 var defaultCheckInterval = time.Second * 10
 
 const (
-	redisDisconnectedErrorMessage  = "Redis disconnected"
-	filterStateErrorMessage        = "Moira-Filter does not receive metrics"
-	checkerStateErrorMessage       = "Moira-Checker does not check triggers"
-	remoteCheckerStateErrorMessage = "Moira-Remote-Checker does not check remote triggers"
+	redisDisconnectedErrorMessage = "Redis disconnected"
+	filterStateErrorMessage       = "Moira-Filter does not receive metrics"
+	checkerStateErrorMessage      = "Moira-Checker does not check triggers"
 )
 
 // SelfCheckWorker checks what all notifier services works correctly and send message when moira don't work
@@ -40,12 +40,16 @@ func (selfCheck *SelfCheckWorker) Start() error {
 	if err := selfCheck.Config.checkConfig(senders); err != nil {
 		return fmt.Errorf("can't configure self state monitor: %s", err.Error())
 	}
-	var metricsCount, checksCount, remoteChecksCount int64
+	var metricsCount, checksCount int64
 	lastMetricReceivedTS := time.Now().Unix()
 	redisLastCheckTS := time.Now().Unix()
 	lastCheckTS := time.Now().Unix()
-	lastRemoteCheckTS := time.Now().Unix()
 	nextSendErrorMessage := time.Now().Unix()
+	remoteChecksCount := make(map[string]int64, len(selfCheck.Config.RemoteSources))
+	lastRemoteCheckTS := make(map[string]int64, len(selfCheck.Config.RemoteSources))
+	for _, remoteSource := range selfCheck.Config.RemoteSources {
+		lastRemoteCheckTS[remoteSource] = time.Now().Unix()
+	}
 
 	selfCheck.tomb.Go(func() error {
 		checkTicker := time.NewTicker(defaultCheckInterval)
@@ -56,7 +60,7 @@ func (selfCheck *SelfCheckWorker) Start() error {
 				selfCheck.Log.Info("Moira Notifier Self State Monitor Stopped")
 				return nil
 			case <-checkTicker.C:
-				selfCheck.check(time.Now().Unix(), &lastMetricReceivedTS, &redisLastCheckTS, &lastCheckTS, &lastRemoteCheckTS, &nextSendErrorMessage, &metricsCount, &checksCount, &remoteChecksCount)
+				selfCheck.check(time.Now().Unix(), &lastMetricReceivedTS, &redisLastCheckTS, &lastCheckTS, lastRemoteCheckTS, &nextSendErrorMessage, &metricsCount, &checksCount, remoteChecksCount)
 			}
 		}
 	})
@@ -74,14 +78,15 @@ func (selfCheck *SelfCheckWorker) Stop() error {
 	return selfCheck.tomb.Wait()
 }
 
-func (selfCheck *SelfCheckWorker) check(nowTS int64, lastMetricReceivedTS, redisLastCheckTS, lastCheckTS, lastRemoteCheckTS, nextSendErrorMessage, metricsCount, checksCount, remoteChecksCount *int64) {
+// check compares services counters with the previous ones, remote checker counters and last check timestamps are tracked for every remote source separately
+func (selfCheck *SelfCheckWorker) check(nowTS int64, lastMetricReceivedTS, redisLastCheckTS, lastCheckTS *int64, lastRemoteCheckTS map[string]int64, nextSendErrorMessage, metricsCount, checksCount *int64, remoteChecksCount map[string]int64) {
 	var events []moira.NotificationEvent
-	var rcc int64
 
 	mc, _ := selfCheck.DB.GetMetricsUpdatesCount()
 	cc, err := selfCheck.DB.GetChecksUpdatesCount()
-	if selfCheck.Config.RemoteTriggersEnabled {
-		rcc, _ = selfCheck.DB.GetRemoteChecksUpdatesCount()
+	rcc := make(map[string]int64, len(selfCheck.Config.RemoteSources))
+	for _, remoteSource := range selfCheck.Config.RemoteSources {
+		rcc[remoteSource], _ = selfCheck.DB.GetRemoteChecksUpdatesCount(remoteSource)
 	}
 	if err == nil {
 		*redisLastCheckTS = nowTS
@@ -93,10 +98,10 @@ func (selfCheck *SelfCheckWorker) check(nowTS int64, lastMetricReceivedTS, redis
 			*checksCount = cc
 			*lastCheckTS = nowTS
 		}
-		if selfCheck.Config.RemoteTriggersEnabled {
-			if *remoteChecksCount != rcc {
-				*remoteChecksCount = rcc
-				*lastRemoteCheckTS = nowTS
+		for _, remoteSource := range selfCheck.Config.RemoteSources {
+			if remoteChecksCount[remoteSource] != rcc[remoteSource] {
+				remoteChecksCount[remoteSource] = rcc[remoteSource]
+				lastRemoteCheckTS[remoteSource] = nowTS
 			}
 		}
 	}
@@ -122,11 +127,15 @@ func (selfCheck *SelfCheckWorker) check(nowTS int64, lastMetricReceivedTS, redis
 			selfCheck.setNotifierState(ERROR)
 		}
 
-		if selfCheck.Config.RemoteTriggersEnabled {
-			if *lastRemoteCheckTS < nowTS-selfCheck.Config.LastRemoteCheckDelaySeconds && err == nil {
-				interval := nowTS - *lastRemoteCheckTS
-				selfCheck.Log.Errorf("%s more than %ds. Send message.", remoteCheckerStateErrorMessage, interval)
-				appendNotificationEvents(&events, remoteCheckerStateErrorMessage, interval)
+		for _, remoteSource := range selfCheck.Config.RemoteSources {
+			if lastRemoteCheckTS[remoteSource] < nowTS-selfCheck.Config.LastRemoteCheckDelaySeconds && err == nil {
+				interval := nowTS - lastRemoteCheckTS[remoteSource]
+				selfCheck.Log.Errorf("%s more than %ds. Send message.", remoteCheckerStateErrorMessage(remoteSource), interval)
+				appendNotificationEvents(&events, remoteCheckerStateErrorMessage(remoteSource), interval)
+			}
+			if breakerState, _ := selfCheck.DB.GetRemoteBreakerState(remoteSource); breakerState == remote.BreakerOpen.String() {
+				selfCheck.Log.Errorf("%s. Send message.", remoteBreakerStateErrorMessage(remoteSource))
+				appendNotificationEvents(&events, remoteBreakerStateErrorMessage(remoteSource), 0)
 			}
 		}
 
@@ -189,3 +198,13 @@ func notifierStateErrorMessage(state string) string {
 	const template = "Moira-Notifier does not send messages. State: %v"
 	return fmt.Sprintf(template, state)
 }
+
+func remoteCheckerStateErrorMessage(remoteSource string) string {
+	const template = "Moira-Remote-Checker does not check remote triggers of source %v"
+	return fmt.Sprintf(template, remoteSource)
+}
+
+func remoteBreakerStateErrorMessage(remoteSource string) string {
+	const template = "Moira-Remote-Checker circuit breaker of source %v is open, remote storage is not requested"
+	return fmt.Sprintf(template, remoteSource)
+}
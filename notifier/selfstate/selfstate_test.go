@@ -32,11 +32,11 @@ func TestDatabaseDisconnected(t *testing.T) {
 	var (
 		metricsCount         int64
 		checksCount          int64
-		remoteChecksCount    int64
+		remoteChecksCount    = make(map[string]int64)
 		lastMetricReceivedTS int64
 		redisLastCheckTS     int64
 		lastCheckTS          int64
-		lastRemoteCheckTS    int64
+		lastRemoteCheckTS    = make(map[string]int64)
 		nextSendErrorMessage int64
 	)
 
@@ -55,7 +55,7 @@ func TestDatabaseDisconnected(t *testing.T) {
 			now := time.Now()
 			redisLastCheckTS = now.Add(-time.Second * 11).Unix()
 			lastCheckTS = now.Unix()
-			lastRemoteCheckTS = now.Unix()
+			lastRemoteCheckTS[moira.DefaultRemoteSource] = now.Unix()
 			nextSendErrorMessage = now.Add(-time.Second * 5).Unix()
 			lastMetricReceivedTS = now.Unix()
 			appendNotificationEvents(&events, redisDisconnectedErrorMessage, now.Unix()-redisLastCheckTS)
@@ -63,7 +63,7 @@ func TestDatabaseDisconnected(t *testing.T) {
 			expectedPackage := configureNotificationPackage(adminContact, &events)
 
 			mock.notif.EXPECT().Send(&expectedPackage, &sendingWG)
-			mock.selfCheckWorker.check(now.Unix(), &lastMetricReceivedTS, &redisLastCheckTS, &lastCheckTS, &lastRemoteCheckTS, &nextSendErrorMessage, &metricsCount, &checksCount, &remoteChecksCount)
+			mock.selfCheckWorker.check(now.Unix(), &lastMetricReceivedTS, &redisLastCheckTS, &lastCheckTS, lastRemoteCheckTS, &nextSendErrorMessage, &metricsCount, &checksCount, remoteChecksCount)
 
 			So(lastMetricReceivedTS, ShouldEqual, now.Unix())
 			So(lastCheckTS, ShouldEqual, now.Unix())
@@ -84,11 +84,11 @@ func TestMoiraCacheDoesNotReceivedNewMetrics(t *testing.T) {
 	var (
 		metricsCount         int64
 		checksCount          int64
-		remoteChecksCount    int64
+		remoteChecksCount    = make(map[string]int64)
 		lastMetricReceivedTS int64
 		redisLastCheckTS     int64
 		lastCheckTS          int64
-		lastRemoteCheckTS    int64
+		lastRemoteCheckTS    = make(map[string]int64)
 		nextSendErrorMessage int64
 	)
 
@@ -103,7 +103,7 @@ func TestMoiraCacheDoesNotReceivedNewMetrics(t *testing.T) {
 		now := time.Now()
 		redisLastCheckTS = now.Unix()
 		lastCheckTS = now.Unix()
-		lastRemoteCheckTS = now.Unix()
+		lastRemoteCheckTS[moira.DefaultRemoteSource] = now.Unix()
 		nextSendErrorMessage = now.Add(-time.Second * 5).Unix()
 		lastMetricReceivedTS = now.Add(-time.Second * 61).Unix()
 		metricsCount = 1
@@ -116,7 +116,7 @@ func TestMoiraCacheDoesNotReceivedNewMetrics(t *testing.T) {
 		mock.database.EXPECT().SetNotifierState(ERROR).Return(nil)
 		mock.database.EXPECT().GetNotifierState().Return(ERROR, nil)
 		mock.notif.EXPECT().Send(&expectedPackage, &sendingWG)
-		mock.selfCheckWorker.check(callingNow.Unix(), &lastMetricReceivedTS, &redisLastCheckTS, &lastCheckTS, &lastRemoteCheckTS, &nextSendErrorMessage, &metricsCount, &checksCount, &remoteChecksCount)
+		mock.selfCheckWorker.check(callingNow.Unix(), &lastMetricReceivedTS, &redisLastCheckTS, &lastCheckTS, lastRemoteCheckTS, &nextSendErrorMessage, &metricsCount, &checksCount, remoteChecksCount)
 
 		So(lastMetricReceivedTS, ShouldEqual, now.Add(-time.Second*61).Unix())
 		So(lastCheckTS, ShouldEqual, callingNow.Unix())
@@ -136,11 +136,11 @@ func TestMoiraCheckerDoesNotChecksTriggers(t *testing.T) {
 	var (
 		metricsCount         int64
 		checksCount          int64
-		remoteChecksCount    int64
+		remoteChecksCount    = make(map[string]int64)
 		lastMetricReceivedTS int64
 		redisLastCheckTS     int64
 		lastCheckTS          int64
-		lastRemoteCheckTS    int64
+		lastRemoteCheckTS    = make(map[string]int64)
 		nextSendErrorMessage int64
 	)
 
@@ -155,7 +155,7 @@ func TestMoiraCheckerDoesNotChecksTriggers(t *testing.T) {
 		now := time.Now()
 		redisLastCheckTS = now.Unix()
 		lastCheckTS = now.Add(-time.Second * 121).Unix()
-		lastRemoteCheckTS = now.Unix()
+		lastRemoteCheckTS[moira.DefaultRemoteSource] = now.Unix()
 		nextSendErrorMessage = now.Add(-time.Second * 5).Unix()
 		lastMetricReceivedTS = now.Unix()
 		checksCount = 1
@@ -168,7 +168,7 @@ func TestMoiraCheckerDoesNotChecksTriggers(t *testing.T) {
 		mock.database.EXPECT().SetNotifierState(ERROR).Return(nil)
 		mock.database.EXPECT().GetNotifierState().Return(ERROR, nil)
 		mock.notif.EXPECT().Send(&expectedPackage, &sendingWG)
-		mock.selfCheckWorker.check(callingNow.Unix(), &lastMetricReceivedTS, &redisLastCheckTS, &lastCheckTS, &lastRemoteCheckTS, &nextSendErrorMessage, &metricsCount, &checksCount, &remoteChecksCount)
+		mock.selfCheckWorker.check(callingNow.Unix(), &lastMetricReceivedTS, &redisLastCheckTS, &lastCheckTS, lastRemoteCheckTS, &nextSendErrorMessage, &metricsCount, &checksCount, remoteChecksCount)
 
 		So(lastMetricReceivedTS, ShouldEqual, callingNow.Unix())
 		So(lastCheckTS, ShouldEqual, now.Add(-time.Second*121).Unix())
@@ -188,11 +188,11 @@ func TestMoiraCheckerDoesNotChecksRemoteTriggers(t *testing.T) {
 	var (
 		metricsCount         int64
 		checksCount          int64
-		remoteChecksCount    int64
+		remoteChecksCount    = make(map[string]int64)
 		lastMetricReceivedTS int64
 		redisLastCheckTS     int64
 		lastCheckTS          int64
-		lastRemoteCheckTS    int64
+		lastRemoteCheckTS    = make(map[string]int64)
 		nextSendErrorMessage int64
 	)
 
@@ -203,27 +203,28 @@ func TestMoiraCheckerDoesNotChecksRemoteTriggers(t *testing.T) {
 		var sendingWG sync.WaitGroup
 		mock.database.EXPECT().GetMetricsUpdatesCount().Return(int64(1), nil)
 		mock.database.EXPECT().GetChecksUpdatesCount().Return(int64(1), nil)
-		mock.database.EXPECT().GetRemoteChecksUpdatesCount().Return(int64(1), nil)
+		mock.database.EXPECT().GetRemoteChecksUpdatesCount(moira.DefaultRemoteSource).Return(int64(1), nil)
 
 		now := time.Now()
 		redisLastCheckTS = now.Unix()
 		lastCheckTS = now.Unix()
-		lastRemoteCheckTS = now.Add(-time.Second * 121).Unix()
+		lastRemoteCheckTS[moira.DefaultRemoteSource] = now.Add(-time.Second * 121).Unix()
 		nextSendErrorMessage = now.Add(-time.Second * 5).Unix()
 		lastMetricReceivedTS = now.Unix()
 		checksCount = 1
-		remoteChecksCount = 1
+		remoteChecksCount[moira.DefaultRemoteSource] = 1
 
 		callingNow := now.Add(time.Second * 2)
-		appendNotificationEvents(&events, remoteCheckerStateErrorMessage, callingNow.Unix()-lastRemoteCheckTS)
+		appendNotificationEvents(&events, remoteCheckerStateErrorMessage(moira.DefaultRemoteSource), callingNow.Unix()-lastRemoteCheckTS[moira.DefaultRemoteSource])
 		expectedPackage := configureNotificationPackage(adminContact, &events)
 
+		mock.database.EXPECT().GetRemoteBreakerState(moira.DefaultRemoteSource).Return("closed", nil)
 		mock.database.EXPECT().GetNotifierState().Return(OK, nil)
 		mock.notif.EXPECT().Send(&expectedPackage, &sendingWG)
-		mock.selfCheckWorker.check(callingNow.Unix(), &lastMetricReceivedTS, &redisLastCheckTS, &lastCheckTS, &lastRemoteCheckTS, &nextSendErrorMessage, &metricsCount, &checksCount, &remoteChecksCount)
+		mock.selfCheckWorker.check(callingNow.Unix(), &lastMetricReceivedTS, &redisLastCheckTS, &lastCheckTS, lastRemoteCheckTS, &nextSendErrorMessage, &metricsCount, &checksCount, remoteChecksCount)
 
 		So(lastMetricReceivedTS, ShouldEqual, callingNow.Unix())
-		So(lastRemoteCheckTS, ShouldEqual, now.Add(-time.Second*121).Unix())
+		So(lastRemoteCheckTS[moira.DefaultRemoteSource], ShouldEqual, now.Add(-time.Second*121).Unix())
 		So(redisLastCheckTS, ShouldEqual, callingNow.Unix())
 		So(nextSendErrorMessage, ShouldEqual, callingNow.Unix()+mock.conf.NoticeIntervalSeconds)
 	})
@@ -231,6 +232,56 @@ func TestMoiraCheckerDoesNotChecksRemoteTriggers(t *testing.T) {
 	mock.mockCtrl.Finish()
 }
 
+func TestRemoteBreakerIsOpen(t *testing.T) {
+	adminContact := map[string]string{
+		"type":  "admin-mail",
+		"value": "admin@company.com",
+	}
+
+	var (
+		metricsCount         int64
+		checksCount          int64
+		remoteChecksCount    = make(map[string]int64)
+		lastMetricReceivedTS int64
+		redisLastCheckTS     int64
+		lastCheckTS          int64
+		lastRemoteCheckTS    = make(map[string]int64)
+		nextSendErrorMessage int64
+	)
+
+	mock := configureWorker(t, true)
+	mock.selfCheckWorker.Start()
+	Convey("Should notify admin", t, func() {
+		var events []moira.NotificationEvent
+		var sendingWG sync.WaitGroup
+		mock.database.EXPECT().GetMetricsUpdatesCount().Return(int64(1), nil)
+		mock.database.EXPECT().GetChecksUpdatesCount().Return(int64(1), nil)
+		mock.database.EXPECT().GetRemoteChecksUpdatesCount(moira.DefaultRemoteSource).Return(int64(1), nil)
+		mock.database.EXPECT().GetRemoteBreakerState(moira.DefaultRemoteSource).Return("open", nil)
+
+		now := time.Now()
+		redisLastCheckTS = now.Unix()
+		lastCheckTS = now.Unix()
+		lastRemoteCheckTS[moira.DefaultRemoteSource] = now.Unix()
+		nextSendErrorMessage = now.Add(-time.Second * 5).Unix()
+		lastMetricReceivedTS = now.Unix()
+		metricsCount = 1
+		checksCount = 1
+		remoteChecksCount[moira.DefaultRemoteSource] = 1
+
+		appendNotificationEvents(&events, remoteBreakerStateErrorMessage(moira.DefaultRemoteSource), 0)
+		expectedPackage := configureNotificationPackage(adminContact, &events)
+
+		mock.database.EXPECT().GetNotifierState().Return(OK, nil)
+		mock.notif.EXPECT().Send(&expectedPackage, &sendingWG)
+		mock.selfCheckWorker.check(now.Unix(), &lastMetricReceivedTS, &redisLastCheckTS, &lastCheckTS, lastRemoteCheckTS, &nextSendErrorMessage, &metricsCount, &checksCount, remoteChecksCount)
+
+		So(nextSendErrorMessage, ShouldEqual, now.Unix()+mock.conf.NoticeIntervalSeconds)
+	})
+	mock.selfCheckWorker.Stop()
+	mock.mockCtrl.Finish()
+}
+
 func TestRunGoRoutine(t *testing.T) {
 	adminContact := map[string]string{
 		"type":  "admin-mail",
@@ -285,8 +336,7 @@ func configureWorker(t *testing.T, remoteEnabled bool) *selfCheckWorkerMock {
 	}
 	defaultCheckInterval = time.Second * 1
 	conf := Config{
-		Enabled:               true,
-		RemoteTriggersEnabled: remoteEnabled,
+		Enabled: true,
 		Contacts: []map[string]string{
 			adminContact,
 		},
@@ -296,6 +346,9 @@ func configureWorker(t *testing.T, remoteEnabled bool) *selfCheckWorkerMock {
 		LastRemoteCheckDelaySeconds:    120,
 		NoticeIntervalSeconds:          60,
 	}
+	if remoteEnabled {
+		conf.RemoteSources = []string{moira.DefaultRemoteSource}
+	}
 
 	mockCtrl := gomock.NewController(t)
 	database := mock_moira_alert.NewMockDatabase(mockCtrl)
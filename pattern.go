@@ -0,0 +1,85 @@
+package moira
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// MatchGraphitePattern checks that metric name matches Graphite glob pattern. Pattern and metric name must have the same number
+// of dot separated parts and every metric name part must match pattern part: * and ? do not cross dots, [...] is a character class
+// and {a,b} lists alternatives, e.g. batch.*.duration matches batch.import.duration, but not batch.import.users.duration
+func MatchGraphitePattern(pattern string, metric string) bool {
+	patternParts := strings.Split(pattern, ".")
+	metricParts := strings.Split(metric, ".")
+	if len(patternParts) != len(metricParts) {
+		return false
+	}
+	for i, patternPart := range patternParts {
+		if !matchGraphitePatternPart(patternPart, metricParts[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateGraphitePattern checks that pattern has no empty parts and every its part is a valid glob
+func ValidateGraphitePattern(pattern string) error {
+	for _, patternPart := range strings.Split(pattern, ".") {
+		if patternPart == "" {
+			return fmt.Errorf("pattern has empty part")
+		}
+		alternatives, err := expandBraces(patternPart)
+		if err != nil {
+			return err
+		}
+		for _, alternative := range alternatives {
+			if _, err := path.Match(alternative, ""); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func matchGraphitePatternPart(patternPart string, metricPart string) bool {
+	if patternPart == "*" {
+		return true
+	}
+	alternatives, err := expandBraces(patternPart)
+	if err != nil {
+		return false
+	}
+	for _, alternative := range alternatives {
+		if matched, _ := path.Match(alternative, metricPart); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// expandBraces returns all variants of pattern part with {a,b} lists, nested lists are not supported
+func expandBraces(patternPart string) ([]string, error) {
+	start := strings.Index(patternPart, "{")
+	if start < 0 {
+		if strings.Contains(patternPart, "}") {
+			return nil, path.ErrBadPattern
+		}
+		return []string{patternPart}, nil
+	}
+	end := strings.Index(patternPart, "}")
+	if end < start || strings.Contains(patternPart[start+1:end], "{") {
+		return nil, path.ErrBadPattern
+	}
+	suffixes, err := expandBraces(patternPart[end+1:])
+	if err != nil {
+		return nil, err
+	}
+	variants := make([]string, 0)
+	for _, alternative := range strings.Split(patternPart[start+1:end], ",") {
+		for _, suffix := range suffixes {
+			variants = append(variants, patternPart[:start]+alternative+suffix)
+		}
+	}
+	return variants, nil
+}
@@ -0,0 +1,45 @@
+package moira
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMatchGraphitePattern(t *testing.T) {
+	Convey("Wildcards match within one part", t, func() {
+		So(MatchGraphitePattern("batch.*.duration", "batch.import.duration"), ShouldBeTrue)
+		So(MatchGraphitePattern("batch.*.duration", "batch.a.b.duration"), ShouldBeFalse)
+		So(MatchGraphitePattern("batch.*", "batch.import.duration"), ShouldBeFalse)
+		So(MatchGraphitePattern("batch.imp?rt.duration", "batch.import.duration"), ShouldBeTrue)
+		So(MatchGraphitePattern("batch.im*.duration", "batch.import.duration"), ShouldBeTrue)
+		So(MatchGraphitePattern("batch.[a-j]*.duration", "batch.import.duration"), ShouldBeTrue)
+	})
+
+	Convey("Alternatives", t, func() {
+		So(MatchGraphitePattern("batch.{import,export}.duration", "batch.export.duration"), ShouldBeTrue)
+		So(MatchGraphitePattern("batch.{import,export}.duration", "batch.delete.duration"), ShouldBeFalse)
+		So(MatchGraphitePattern("batch.{im,ex}port_{1,2}.duration", "batch.export_2.duration"), ShouldBeTrue)
+		So(MatchGraphitePattern("batch.{im,ex}port.*", "batch.import.a.b"), ShouldBeFalse)
+	})
+
+	Convey("Exact names", t, func() {
+		So(MatchGraphitePattern("batch.import.duration", "batch.import.duration"), ShouldBeTrue)
+		So(MatchGraphitePattern("batch.import.duration", "batch.import"), ShouldBeFalse)
+		So(MatchGraphitePattern("batch.import", ""), ShouldBeFalse)
+	})
+}
+
+func TestValidateGraphitePattern(t *testing.T) {
+	Convey("Valid patterns", t, func() {
+		So(ValidateGraphitePattern("batch.*.duration"), ShouldBeNil)
+		So(ValidateGraphitePattern("batch.{im,ex}port_[0-9].duration"), ShouldBeNil)
+	})
+
+	Convey("Invalid patterns", t, func() {
+		So(ValidateGraphitePattern("batch..duration"), ShouldNotBeNil)
+		So(ValidateGraphitePattern("batch.{import.duration"), ShouldNotBeNil)
+		So(ValidateGraphitePattern("batch.import}.duration"), ShouldNotBeNil)
+		So(ValidateGraphitePattern("batch.{a,{b,c}}.duration"), ShouldNotBeNil)
+	})
+}
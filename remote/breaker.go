@@ -0,0 +1,113 @@
+package remote
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// BreakerState represents state of remote storage circuit breaker
+type BreakerState int
+
+const (
+	// BreakerClosed means that remote requests are performed as usual
+	BreakerClosed BreakerState = iota
+	// BreakerHalfOpen means that open interval has passed and single trial request is allowed to check remote storage recovery
+	BreakerHalfOpen
+	// BreakerOpen means that remote storage failed too many times in a row and requests are rejected until open interval passes
+	BreakerOpen
+)
+
+// String is a representation of Stringer interface method
+func (state BreakerState) String() string {
+	switch state {
+	case BreakerHalfOpen:
+		return "half-open"
+	case BreakerOpen:
+		return "open"
+	default:
+		return "closed"
+	}
+}
+
+// ErrBreakerOpen is returned instead of performing remote request while circuit breaker is open
+var ErrBreakerOpen = errors.New("remote storage circuit breaker is open")
+
+// circuitBreaker opens after maxFailures consecutive failed requests and half-opens after openInterval
+// to let single trial request through: successful trial closes the breaker, failed one opens it again
+type circuitBreaker struct {
+	maxFailures  int
+	openInterval time.Duration
+
+	lock            sync.Mutex
+	state           BreakerState
+	failures        int
+	openedAt        time.Time
+	trialInProgress bool
+}
+
+// newCircuitBreaker creates circuit breaker, it never opens if maxFailures is not positive
+func newCircuitBreaker(maxFailures int, openInterval time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		maxFailures:  maxFailures,
+		openInterval: openInterval,
+	}
+}
+
+// allow checks if request can be performed and switches open breaker to half-open state when open interval has passed
+func (breaker *circuitBreaker) allow() bool {
+	if breaker.maxFailures <= 0 {
+		return true
+	}
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+	switch breaker.state {
+	case BreakerOpen:
+		if time.Since(breaker.openedAt) < breaker.openInterval {
+			return false
+		}
+		breaker.state = BreakerHalfOpen
+		breaker.trialInProgress = true
+		return true
+	case BreakerHalfOpen:
+		if breaker.trialInProgress {
+			return false
+		}
+		breaker.trialInProgress = true
+		return true
+	default:
+		return true
+	}
+}
+
+func (breaker *circuitBreaker) success() {
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+	breaker.state = BreakerClosed
+	breaker.failures = 0
+	breaker.trialInProgress = false
+}
+
+func (breaker *circuitBreaker) failure() {
+	if breaker.maxFailures <= 0 {
+		return
+	}
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+	breaker.failures++
+	breaker.trialInProgress = false
+	if breaker.state == BreakerHalfOpen || breaker.failures >= breaker.maxFailures {
+		breaker.state = BreakerOpen
+		breaker.openedAt = time.Now()
+	}
+}
+
+// getState returns current breaker state, open breaker is reported as half-open as soon as open interval has passed
+func (breaker *circuitBreaker) getState() BreakerState {
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+	if breaker.state == BreakerOpen && time.Since(breaker.openedAt) >= breaker.openInterval {
+		return BreakerHalfOpen
+	}
+	return breaker.state
+}
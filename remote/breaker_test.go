@@ -0,0 +1,66 @@
+package remote
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	Convey("Given breaker with max failures", t, func() {
+		breaker := newCircuitBreaker(2, time.Hour)
+		So(breaker.getState(), ShouldEqual, BreakerClosed)
+
+		Convey("single failure should not open breaker", func() {
+			breaker.failure()
+			So(breaker.allow(), ShouldBeTrue)
+			So(breaker.getState(), ShouldEqual, BreakerClosed)
+		})
+
+		Convey("success should reset failures counter", func() {
+			breaker.failure()
+			breaker.success()
+			breaker.failure()
+			So(breaker.getState(), ShouldEqual, BreakerClosed)
+		})
+
+		Convey("consecutive failures should open breaker", func() {
+			breaker.failure()
+			breaker.failure()
+			So(breaker.getState(), ShouldEqual, BreakerOpen)
+			So(breaker.allow(), ShouldBeFalse)
+		})
+
+		Convey("breaker should half-open after open interval", func() {
+			breaker.failure()
+			breaker.failure()
+			breaker.openedAt = time.Now().Add(-time.Hour)
+			So(breaker.getState(), ShouldEqual, BreakerHalfOpen)
+
+			So(breaker.allow(), ShouldBeTrue)
+			So(breaker.allow(), ShouldBeFalse)
+
+			Convey("successful trial should close breaker", func() {
+				breaker.success()
+				So(breaker.getState(), ShouldEqual, BreakerClosed)
+				So(breaker.allow(), ShouldBeTrue)
+			})
+
+			Convey("failed trial should open breaker again", func() {
+				breaker.failure()
+				So(breaker.getState(), ShouldEqual, BreakerOpen)
+				So(breaker.allow(), ShouldBeFalse)
+			})
+		})
+	})
+
+	Convey("Breaker without max failures should never open", t, func() {
+		breaker := newCircuitBreaker(0, time.Hour)
+		for i := 0; i < 10; i++ {
+			breaker.failure()
+		}
+		So(breaker.getState(), ShouldEqual, BreakerClosed)
+		So(breaker.allow(), ShouldBeTrue)
+	})
+}
@@ -0,0 +1,127 @@
+package remote
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// fetchCache stores successful remote responses for a short time and coalesces concurrent identical requests,
+// so triggers with the same targets don't request remote storage separately
+type fetchCache struct {
+	ttl time.Duration
+
+	lock      sync.Mutex
+	items     map[string]cacheItem
+	calls     map[string]*fetchCall
+	lastPurge time.Time
+	hits      int64
+	misses    int64
+}
+
+type cacheItem struct {
+	body      []byte
+	expiresAt time.Time
+}
+
+// fetchCall is a request in flight, which results are shared by all its concurrent identical requests
+type fetchCall struct {
+	wg         sync.WaitGroup
+	statusCode int
+	body       []byte
+	err        error
+}
+
+// newFetchCache creates fetch cache, responses are not stored if ttl is not positive, but concurrent requests are still coalesced
+func newFetchCache(ttl time.Duration) *fetchCache {
+	return &fetchCache{
+		ttl:   ttl,
+		items: make(map[string]cacheItem),
+		calls: make(map[string]*fetchCall),
+	}
+}
+
+// alignPeriod aligns from and until to cache ttl, so requests of the same target made within ttl
+// request the same period and share cached response
+func (cache *fetchCache) alignPeriod(from, until int64) (int64, int64) {
+	if alignment := int64(cache.ttl / time.Second); alignment > 1 {
+		from -= from % alignment
+		until -= until % alignment
+	}
+	return from, until
+}
+
+// getKey returns cache key of target requested for given period, period must be aligned by alignPeriod
+func (cache *fetchCache) getKey(target string, from, until int64) string {
+	return fmt.Sprintf("%s:%d:%d", target, from, until)
+}
+
+// fetch returns cached response by key or performs request. If identical request is already in flight,
+// fetch waits for it and returns its response, waiter is counted as hit only if the response is successful.
+// Only responses with 200 status are cached
+func (cache *fetchCache) fetch(key string, request func() (int, []byte, error)) (int, []byte, error) {
+	now := time.Now()
+	cache.lock.Lock()
+	if item, ok := cache.items[key]; ok && now.Before(item.expiresAt) {
+		cache.hits++
+		cache.lock.Unlock()
+		return http.StatusOK, item.body, nil
+	}
+	if call, ok := cache.calls[key]; ok {
+		cache.lock.Unlock()
+		call.wg.Wait()
+		cache.lock.Lock()
+		if call.isSuccessful() {
+			cache.hits++
+		} else {
+			cache.misses++
+		}
+		cache.lock.Unlock()
+		return call.statusCode, call.body, call.err
+	}
+	cache.misses++
+	call := &fetchCall{}
+	call.wg.Add(1)
+	cache.calls[key] = call
+	cache.lock.Unlock()
+
+	call.statusCode, call.body, call.err = request()
+
+	cache.lock.Lock()
+	delete(cache.calls, key)
+	if cache.ttl > 0 && call.isSuccessful() {
+		now = time.Now()
+		cache.purgeExpired(now)
+		cache.items[key] = cacheItem{body: call.body, expiresAt: now.Add(cache.ttl)}
+	}
+	cache.lock.Unlock()
+	call.wg.Done()
+	return call.statusCode, call.body, call.err
+}
+
+func (call *fetchCall) isSuccessful() bool {
+	return call.err == nil && call.statusCode == http.StatusOK
+}
+
+// purgeExpired removes expired items no more often than once per ttl, must be called under lock
+func (cache *fetchCache) purgeExpired(now time.Time) {
+	if now.Sub(cache.lastPurge) < cache.ttl {
+		return
+	}
+	for key, item := range cache.items {
+		if !now.Before(item.expiresAt) {
+			delete(cache.items, key)
+		}
+	}
+	cache.lastPurge = now
+}
+
+// takeStats returns numbers of cache hits and misses since previous call
+func (cache *fetchCache) takeStats() (int64, int64) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	hits, misses := cache.hits, cache.misses
+	cache.hits, cache.misses = 0, 0
+	return hits, misses
+}
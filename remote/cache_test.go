@@ -0,0 +1,172 @@
+package remote
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFetchCacheAlignPeriod(t *testing.T) {
+	Convey("Period should be aligned to cache ttl", t, func() {
+		cache := newFetchCache(time.Minute)
+		from, until := cache.alignPeriod(3601, 7259)
+		So([]int64{from, until}, ShouldResemble, []int64{3600, 7200})
+		from, until = cache.alignPeriod(3659, 7201)
+		So([]int64{from, until}, ShouldResemble, []int64{3600, 7200})
+		So(cache.getKey("sumSeries(a.*)", from, until), ShouldEqual, "sumSeries(a.*):3600:7200")
+	})
+
+	Convey("Period should not be aligned without cache ttl", t, func() {
+		cache := newFetchCache(0)
+		from, until := cache.alignPeriod(3601, 7259)
+		So([]int64{from, until}, ShouldResemble, []int64{3601, 7259})
+	})
+}
+
+func TestFetchCache(t *testing.T) {
+	Convey("Given cache with ttl", t, func() {
+		cache := newFetchCache(time.Minute)
+		var requestsCount int32
+		request := func(statusCode int) func() (int, []byte, error) {
+			return func() (int, []byte, error) {
+				atomic.AddInt32(&requestsCount, 1)
+				return statusCode, []byte("[]"), nil
+			}
+		}
+
+		Convey("successful response should be cached", func() {
+			for i := 0; i < 3; i++ {
+				statusCode, body, err := cache.fetch("key", request(http.StatusOK))
+				So(err, ShouldBeNil)
+				So(statusCode, ShouldEqual, http.StatusOK)
+				So(string(body), ShouldEqual, "[]")
+			}
+			So(requestsCount, ShouldEqual, 1)
+
+			hits, misses := cache.takeStats()
+			So(hits, ShouldEqual, 2)
+			So(misses, ShouldEqual, 1)
+			hits, misses = cache.takeStats()
+			So(hits, ShouldEqual, 0)
+			So(misses, ShouldEqual, 0)
+		})
+
+		Convey("bad response should not be cached", func() {
+			cache.fetch("key", request(http.StatusInternalServerError))
+			cache.fetch("key", request(http.StatusInternalServerError))
+			So(requestsCount, ShouldEqual, 2)
+		})
+
+		Convey("expired response should be requested again", func() {
+			cache.fetch("key", request(http.StatusOK))
+			item := cache.items["key"]
+			item.expiresAt = time.Now()
+			cache.items["key"] = item
+			cache.fetch("key", request(http.StatusOK))
+			So(requestsCount, ShouldEqual, 2)
+		})
+	})
+
+	Convey("Concurrent identical requests should be coalesced", t, func() {
+		cache := newFetchCache(0)
+		var requestsCount int32
+		release := make(chan struct{})
+		request := func(statusCode int) func() (int, []byte, error) {
+			return func() (int, []byte, error) {
+				atomic.AddInt32(&requestsCount, 1)
+				<-release
+				return statusCode, []byte("[]"), nil
+			}
+		}
+		fetchConcurrently := func(statusCode int) {
+			go cache.fetch("key", request(statusCode))
+			for {
+				cache.lock.Lock()
+				_, inFlight := cache.calls["key"]
+				cache.lock.Unlock()
+				if inFlight {
+					break
+				}
+				time.Sleep(time.Millisecond)
+			}
+
+			var wg, started sync.WaitGroup
+			for i := 0; i < 5; i++ {
+				wg.Add(1)
+				started.Add(1)
+				go func() {
+					defer wg.Done()
+					started.Done()
+					cache.fetch("key", request(statusCode))
+				}()
+			}
+			// give started requests time to join the request in flight, requests made after it would be counted
+			started.Wait()
+			time.Sleep(100 * time.Millisecond)
+			close(release)
+			wg.Wait()
+		}
+
+		Convey("waiters of successful request should be counted as hits", func() {
+			fetchConcurrently(http.StatusOK)
+			So(requestsCount, ShouldEqual, 1)
+			So(cache.items, ShouldBeEmpty)
+			hits, misses := cache.takeStats()
+			So(hits, ShouldEqual, 5)
+			So(misses, ShouldEqual, 1)
+		})
+
+		Convey("waiters of failed request should be counted as misses", func() {
+			fetchConcurrently(http.StatusInternalServerError)
+			So(requestsCount, ShouldEqual, 1)
+			hits, misses := cache.takeStats()
+			So(hits, ShouldEqual, 0)
+			So(misses, ShouldEqual, 6)
+		})
+	})
+}
+
+func TestFetchWithCache(t *testing.T) {
+	Convey("Given remote storage and config with cache ttl", t, func() {
+		var requestsCount int32
+		var requestedPeriods []string
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			atomic.AddInt32(&requestsCount, 1)
+			query := request.URL.Query()
+			requestedPeriods = append(requestedPeriods, query.Get("from")+"-"+query.Get("until"))
+			fmt.Fprint(writer, `[{"target":"sumSeries(a.*)","datapoints":[[1,3600],[2,3660]]}]`)
+		}))
+		defer server.Close()
+		cfg := &Config{URL: server.URL, Timeout: time.Second, CacheTTL: time.Minute}
+
+		Convey("triggers with the same target should share response", func() {
+			for _, until := range []int64{3661, 3662, 3700} {
+				timeSeries, err := Fetch(cfg, "sumSeries(a.*)", 3600, until, true)
+				So(err, ShouldBeNil)
+				So(timeSeries, ShouldHaveLength, 1)
+				So(timeSeries[0].Values, ShouldResemble, []float64{1, 2})
+			}
+			So(requestsCount, ShouldEqual, 1)
+			So(requestedPeriods, ShouldResemble, []string{"3600-3660"})
+
+			hits, misses := cfg.TakeCacheStats()
+			So(hits, ShouldEqual, 2)
+			So(misses, ShouldEqual, 1)
+		})
+
+		Convey("cached response should not be changed by realtime alerting setting", func() {
+			timeSeries, err := Fetch(cfg, "sumSeries(a.*)", 3600, 3661, false)
+			So(err, ShouldBeNil)
+			So(timeSeries[0].Values, ShouldResemble, []float64{1})
+			timeSeries, err = Fetch(cfg, "sumSeries(a.*)", 3600, 3661, true)
+			So(err, ShouldBeNil)
+			So(timeSeries[0].Values, ShouldResemble, []float64{1, 2})
+		})
+	})
+}
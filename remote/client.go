@@ -0,0 +1,115 @@
+package remote
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// defaultMaxIdleConns is a number of idle connections kept to remote storage if max in-flight requests is not limited
+const defaultMaxIdleConns = 100
+
+// ErrTooManyRequestsInFlight is returned if request could not start during timeout because of max in-flight requests limit
+var ErrTooManyRequestsInFlight = errors.New("too many remote requests in flight")
+
+// client is a remote storage http client shared by all requests to the same remote storage:
+// it reuses connections, limits number of requests in flight, protects remote storage by circuit breaker
+// and caches responses
+type client struct {
+	httpClient *http.Client
+	timeout    time.Duration
+	inFlight   chan struct{}
+	breaker    *circuitBreaker
+	cache      *fetchCache
+}
+
+func newClient(cfg *Config) *client {
+	maxIdleConns := cfg.MaxInFlight
+	if maxIdleConns <= 0 {
+		maxIdleConns = defaultMaxIdleConns
+	}
+	c := &client{
+		httpClient: &http.Client{
+			Timeout: cfg.Timeout,
+			Transport: &http.Transport{
+				Proxy:               http.ProxyFromEnvironment,
+				MaxIdleConns:        maxIdleConns,
+				MaxIdleConnsPerHost: maxIdleConns,
+				IdleConnTimeout:     90 * time.Second,
+			},
+		},
+		timeout: cfg.Timeout,
+		breaker: newCircuitBreaker(cfg.BreakerMaxFailures, cfg.BreakerOpenInterval),
+		cache:   newFetchCache(cfg.CacheTTL),
+	}
+	if cfg.MaxInFlight > 0 {
+		c.inFlight = make(chan struct{}, cfg.MaxInFlight)
+	}
+	return c
+}
+
+// do performs request and returns response status code and body.
+// Transport errors and 5xx responses are counted as circuit breaker failures
+func (c *client) do(req *http.Request) (int, []byte, error) {
+	if err := c.acquire(); err != nil {
+		return 0, nil, err
+	}
+	defer c.release()
+	if !c.breaker.allow() {
+		return 0, nil, ErrBreakerOpen
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.breaker.failure()
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		c.breaker.failure()
+		return resp.StatusCode, body, err
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		c.breaker.failure()
+	} else {
+		c.breaker.success()
+	}
+	return resp.StatusCode, body, nil
+}
+
+// doCached performs request through fetch cache, request is not cached if cache key is empty
+func (c *client) doCached(cacheKey string, req *http.Request) (int, []byte, error) {
+	if cacheKey == "" {
+		return c.do(req)
+	}
+	return c.cache.fetch(cacheKey, func() (int, []byte, error) {
+		return c.do(req)
+	})
+}
+
+// acquire waits for free in-flight request slot no longer than request timeout
+func (c *client) acquire() error {
+	if c.inFlight == nil {
+		return nil
+	}
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+	select {
+	case c.inFlight <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return ErrTooManyRequestsInFlight
+	}
+}
+
+func (c *client) release() {
+	if c.inFlight != nil {
+		<-c.inFlight
+	}
+}
+
+func (c *client) getRequestsInFlight() int {
+	return len(c.inFlight)
+}
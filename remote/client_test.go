@@ -0,0 +1,66 @@
+package remote
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestClient(t *testing.T) {
+	Convey("Given failing remote storage", t, func() {
+		requestsCount := 0
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			requestsCount++
+			writer.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+		cfg := &Config{URL: server.URL, Timeout: time.Second, BreakerMaxFailures: 2, BreakerOpenInterval: time.Hour}
+		req, _ := http.NewRequest("GET", server.URL, nil)
+
+		Convey("breaker should open and reject requests without calling remote storage", func() {
+			for i := 0; i < 2; i++ {
+				statusCode, _, err := cfg.getClient().do(req)
+				So(err, ShouldBeNil)
+				So(statusCode, ShouldEqual, http.StatusInternalServerError)
+			}
+			So(cfg.GetBreakerState(), ShouldEqual, BreakerOpen)
+
+			_, _, err := cfg.getClient().do(req)
+			So(err, ShouldEqual, ErrBreakerOpen)
+			So(requestsCount, ShouldEqual, 2)
+
+			available, err := IsRemoteAvailable(cfg)
+			So(available, ShouldBeFalse)
+			So(err, ShouldEqual, ErrBreakerOpen)
+			So(requestsCount, ShouldEqual, 2)
+		})
+	})
+
+	Convey("Given client with max in-flight limit", t, func() {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			fmt.Fprint(writer, "[]")
+		}))
+		defer server.Close()
+		cfg := &Config{URL: server.URL, Timeout: 100 * time.Millisecond, MaxInFlight: 1}
+		req, _ := http.NewRequest("GET", server.URL, nil)
+
+		Convey("request over the limit should fail after timeout", func() {
+			So(cfg.getClient().acquire(), ShouldBeNil)
+			So(cfg.GetRequestsInFlight(), ShouldEqual, 1)
+
+			_, _, err := cfg.getClient().do(req)
+			So(err, ShouldEqual, ErrTooManyRequestsInFlight)
+
+			cfg.getClient().release()
+			statusCode, body, err := cfg.getClient().do(req)
+			So(err, ShouldBeNil)
+			So(statusCode, ShouldEqual, http.StatusOK)
+			So(string(body), ShouldEqual, "[]")
+			So(cfg.GetRequestsInFlight(), ShouldEqual, 0)
+		})
+	})
+}
@@ -0,0 +1,125 @@
+package remote
+
+import (
+	"fmt"
+
+	"github.com/go-graphite/carbonapi/expr/types"
+
+	pb "github.com/go-graphite/protocol/carbonapi_v3_pb"
+)
+
+const (
+	// JSONFormat is a default graphite render API response format
+	JSONFormat = "json"
+	// ProtobufFormat is a carbonapi render API response format encoded with carbonapi_v3_pb protocol
+	ProtobufFormat = "protobuf"
+	// MsgpackFormat is a graphite-web render API response format encoded with msgpack
+	MsgpackFormat = "msgpack"
+)
+
+// carbonapiProtobufFormat is a render API format parameter value, which makes carbonapi respond with carbonapi_v3_pb protocol
+const carbonapiProtobufFormat = "carbonapi_v3_pb"
+
+// CheckFormat returns error if given render API response format is unknown, empty format means json
+func CheckFormat(format string) error {
+	switch format {
+	case "", JSONFormat, ProtobufFormat, MsgpackFormat:
+		return nil
+	default:
+		return fmt.Errorf("unknown response format '%s', one of %s, %s or %s expected", format, JSONFormat, ProtobufFormat, MsgpackFormat)
+	}
+}
+
+// getRequestFormat returns render API format parameter value for given response format, json is used by default
+func getRequestFormat(format string) string {
+	switch format {
+	case ProtobufFormat:
+		return carbonapiProtobufFormat
+	case MsgpackFormat:
+		return MsgpackFormat
+	default:
+		return JSONFormat
+	}
+}
+
+// decodeResponse decodes render API response body of given format
+func decodeResponse(body []byte, format string) ([]*types.MetricData, error) {
+	switch format {
+	case ProtobufFormat:
+		return decodeProtobufBody(body)
+	case MsgpackFormat:
+		return decodeMsgpackBody(body)
+	default:
+		return decodeBody(body)
+	}
+}
+
+// decodeProtobufBody decodes carbonapi_v3_pb response, absent values are already NaN in this protocol
+func decodeProtobufBody(body []byte) ([]*types.MetricData, error) {
+	var resp pb.MultiFetchResponse
+	if err := resp.Unmarshal(body); err != nil {
+		return nil, fmt.Errorf("failed to decode protobuf response: %s", err.Error())
+	}
+	res := make([]*types.MetricData, 0, len(resp.Metrics))
+	for _, m := range resp.Metrics {
+		res = append(res, &types.MetricData{
+			FetchResponse: m,
+		})
+	}
+	return res, nil
+}
+
+// decodeMsgpackBody decodes graphite-web msgpack response: array of maps with name, start, end, step and values keys,
+// null values are converted to NaN, other keys are skipped
+func decodeMsgpackBody(body []byte) ([]*types.MetricData, error) {
+	decoder := &msgpackDecoder{data: body}
+	seriesCount, err := decoder.readArrayLen()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode msgpack response: %s", err.Error())
+	}
+	res := make([]*types.MetricData, 0, seriesCount)
+	for i := 0; i < seriesCount; i++ {
+		pbResp, err := decodeMsgpackSeries(decoder)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode msgpack response: %s", err.Error())
+		}
+		res = append(res, &types.MetricData{
+			FetchResponse: pbResp,
+		})
+	}
+	return res, nil
+}
+
+func decodeMsgpackSeries(decoder *msgpackDecoder) (pb.FetchResponse, error) {
+	pbResp := pb.FetchResponse{StepTime: 60}
+	keysCount, err := decoder.readMapLen()
+	if err != nil {
+		return pbResp, err
+	}
+	for i := 0; i < keysCount; i++ {
+		key, err := decoder.readString()
+		if err != nil {
+			return pbResp, err
+		}
+		switch key {
+		case "name":
+			pbResp.Name, err = decoder.readString()
+		case "start":
+			pbResp.StartTime, err = decoder.readInt()
+		case "step":
+			pbResp.StepTime, err = decoder.readInt()
+		case "values":
+			pbResp.Values, err = decoder.readValues()
+		default:
+			err = decoder.skip()
+		}
+		if err != nil {
+			return pbResp, fmt.Errorf("failed to decode '%s': %s", key, err.Error())
+		}
+	}
+	pbResp.StopTime = pbResp.StartTime
+	if len(pbResp.Values) > 0 {
+		pbResp.StopTime += pbResp.StepTime * int64(len(pbResp.Values)-1)
+	}
+	return pbResp, nil
+}
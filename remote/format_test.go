@@ -0,0 +1,268 @@
+package remote
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	pb "github.com/go-graphite/protocol/carbonapi_v3_pb"
+)
+
+func TestCheckFormat(t *testing.T) {
+	Convey("Known formats should be accepted", t, func() {
+		So(CheckFormat(""), ShouldBeNil)
+		So(CheckFormat(JSONFormat), ShouldBeNil)
+		So(CheckFormat(ProtobufFormat), ShouldBeNil)
+		So(CheckFormat(MsgpackFormat), ShouldBeNil)
+	})
+
+	Convey("Unknown format should be rejected", t, func() {
+		So(CheckFormat("pickle"), ShouldNotBeNil)
+		So(CheckFormat("carbonapi_v3_pb"), ShouldNotBeNil)
+	})
+}
+
+func TestGetRequestFormat(t *testing.T) {
+	Convey("Render API format should match response format", t, func() {
+		So(getRequestFormat(""), ShouldEqual, "json")
+		So(getRequestFormat(JSONFormat), ShouldEqual, "json")
+		So(getRequestFormat(ProtobufFormat), ShouldEqual, "carbonapi_v3_pb")
+		So(getRequestFormat(MsgpackFormat), ShouldEqual, "msgpack")
+	})
+
+	Convey("Request should contain configured format", t, func() {
+		req, err := prepareRequest(300, 500, "foo.bar", &Config{URL: "http://test/", Format: ProtobufFormat})
+		So(err, ShouldBeNil)
+		So(req.URL.String(), ShouldEqual, "http://test/?format=carbonapi_v3_pb&from=300&target=foo.bar&until=500")
+	})
+}
+
+func TestDecodeProtobufBody(t *testing.T) {
+	Convey("Given protobuf response", t, func() {
+		body, _ := (&pb.MultiFetchResponse{Metrics: []pb.FetchResponse{
+			{Name: "foo.bar", StartTime: 300, StopTime: 420, StepTime: 60, Values: []float64{1, math.NaN(), 3}},
+		}}).Marshal()
+
+		resp, err := decodeResponse(body, ProtobufFormat)
+		So(err, ShouldBeNil)
+		So(resp, ShouldHaveLength, 1)
+		So(resp[0].Name, ShouldEqual, "foo.bar")
+		So(resp[0].StartTime, ShouldEqual, 300)
+		So(resp[0].StopTime, ShouldEqual, 420)
+		So(resp[0].StepTime, ShouldEqual, 60)
+		So(resp[0].Values[0], ShouldEqual, 1)
+		So(math.IsNaN(resp[0].Values[1]), ShouldBeTrue)
+		So(resp[0].Values[2], ShouldEqual, 3)
+	})
+
+	Convey("Given malformed protobuf response", t, func() {
+		_, err := decodeResponse([]byte("[]"), ProtobufFormat)
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestDecodeMsgpackBody(t *testing.T) {
+	Convey("Given msgpack response", t, func() {
+		encoder := &msgpackEncoder{}
+		encoder.writeArrayLen(2)
+		encoder.writeMapLen(6)
+		encoder.writeString("name")
+		encoder.writeString("foo.bar")
+		encoder.writeString("pathExpression")
+		encoder.writeString("foo.*")
+		encoder.writeString("tags")
+		encoder.writeMapLen(1)
+		encoder.writeString("name")
+		encoder.writeString("foo.bar")
+		encoder.writeString("start")
+		encoder.writeInt(1522076520)
+		encoder.writeString("step")
+		encoder.writeInt(60)
+		encoder.writeString("values")
+		encoder.writeArrayLen(4)
+		encoder.writeInt(5)
+		encoder.writeNil()
+		encoder.writeFloat(2.5)
+		encoder.writeInt(-300)
+		encoder.writeMapLen(2)
+		encoder.writeString("name")
+		encoder.writeString("foo.baz")
+		encoder.writeString("values")
+		encoder.writeArrayLen(0)
+
+		resp, err := decodeResponse(encoder.data, MsgpackFormat)
+		So(err, ShouldBeNil)
+		So(resp, ShouldHaveLength, 2)
+
+		So(resp[0].Name, ShouldEqual, "foo.bar")
+		So(resp[0].StartTime, ShouldEqual, 1522076520)
+		So(resp[0].StopTime, ShouldEqual, 1522076700)
+		So(resp[0].StepTime, ShouldEqual, 60)
+		So(resp[0].Values, ShouldHaveLength, 4)
+		So(resp[0].Values[0], ShouldEqual, 5)
+		So(math.IsNaN(resp[0].Values[1]), ShouldBeTrue)
+		So(resp[0].Values[2], ShouldEqual, 2.5)
+		So(resp[0].Values[3], ShouldEqual, -300)
+
+		So(resp[1].Name, ShouldEqual, "foo.baz")
+		So(resp[1].StepTime, ShouldEqual, 60)
+		So(resp[1].Values, ShouldBeEmpty)
+	})
+
+	Convey("Given empty msgpack response", t, func() {
+		encoder := &msgpackEncoder{}
+		encoder.writeArrayLen(0)
+		resp, err := decodeResponse(encoder.data, MsgpackFormat)
+		So(err, ShouldBeNil)
+		So(resp, ShouldBeEmpty)
+	})
+
+	Convey("Given truncated msgpack response", t, func() {
+		encoder := &msgpackEncoder{}
+		encoder.writeArrayLen(1)
+		encoder.writeMapLen(1)
+		encoder.writeString("values")
+		encoder.writeArrayLen(2)
+		encoder.writeFloat(1)
+		_, err := decodeResponse(encoder.data, MsgpackFormat)
+		So(err.Error(), ShouldEqual, "failed to decode msgpack response: failed to decode 'values': unexpected end of msgpack data")
+	})
+
+	Convey("Given json response instead of msgpack", t, func() {
+		_, err := decodeResponse([]byte("[]"), MsgpackFormat)
+		So(err.Error(), ShouldEqual, "failed to decode msgpack response: unexpected msgpack code 0x5b, array expected")
+	})
+}
+
+func BenchmarkDecodeJSONBody(b *testing.B) {
+	series := makeBenchmarkSeries()
+	metrics := make([]graphiteMetric, 0, len(series))
+	for _, s := range series {
+		datapoints := make([][2]*float64, len(s.Values))
+		for i := range s.Values {
+			value := s.Values[i]
+			timestamp := float64(s.StartTime + s.StepTime*int64(i))
+			datapoints[i] = [2]*float64{&value, &timestamp}
+		}
+		metrics = append(metrics, graphiteMetric{Target: s.Name, Datapoints: datapoints})
+	}
+	body, _ := json.Marshal(metrics)
+	benchmarkDecodeResponse(b, body, JSONFormat)
+}
+
+func BenchmarkDecodeProtobufBody(b *testing.B) {
+	body, _ := (&pb.MultiFetchResponse{Metrics: makeBenchmarkSeries()}).Marshal()
+	benchmarkDecodeResponse(b, body, ProtobufFormat)
+}
+
+func BenchmarkDecodeMsgpackBody(b *testing.B) {
+	series := makeBenchmarkSeries()
+	encoder := &msgpackEncoder{}
+	encoder.writeArrayLen(len(series))
+	for _, s := range series {
+		encoder.writeMapLen(5)
+		encoder.writeString("name")
+		encoder.writeString(s.Name)
+		encoder.writeString("start")
+		encoder.writeInt(s.StartTime)
+		encoder.writeString("end")
+		encoder.writeInt(s.StopTime + s.StepTime)
+		encoder.writeString("step")
+		encoder.writeInt(s.StepTime)
+		encoder.writeString("values")
+		encoder.writeArrayLen(len(s.Values))
+		for _, value := range s.Values {
+			encoder.writeFloat(value)
+		}
+	}
+	benchmarkDecodeResponse(b, encoder.data, MsgpackFormat)
+}
+
+func benchmarkDecodeResponse(b *testing.B, body []byte, format string) {
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := decodeResponse(body, format); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// makeBenchmarkSeries returns response of wide wildcard target: 500 series with day of minutely points
+func makeBenchmarkSeries() []pb.FetchResponse {
+	const seriesCount = 500
+	const pointsCount = 1440
+	series := make([]pb.FetchResponse, 0, seriesCount)
+	for i := 0; i < seriesCount; i++ {
+		values := make([]float64, pointsCount)
+		for j := range values {
+			values[j] = float64(i*pointsCount+j) / 3
+		}
+		series = append(series, pb.FetchResponse{
+			Name:      fmt.Sprintf("servers.server-%d.cpu.user", i),
+			StartTime: 1522076520,
+			StopTime:  1522076520 + 60*(pointsCount-1),
+			StepTime:  60,
+			Values:    values,
+		})
+	}
+	return series
+}
+
+// msgpackEncoder writes msgpack values in the same way as graphite-web does
+type msgpackEncoder struct {
+	data []byte
+}
+
+func (encoder *msgpackEncoder) writeArrayLen(length int) {
+	if length < 16 {
+		encoder.data = append(encoder.data, 0x90|byte(length))
+		return
+	}
+	encoder.data = append(encoder.data, 0xdd)
+	encoder.data = appendUint32(encoder.data, uint32(length))
+}
+
+func (encoder *msgpackEncoder) writeMapLen(length int) {
+	encoder.data = append(encoder.data, 0x80|byte(length))
+}
+
+func (encoder *msgpackEncoder) writeString(value string) {
+	if len(value) < 32 {
+		encoder.data = append(encoder.data, 0xa0|byte(len(value)))
+	} else {
+		encoder.data = append(encoder.data, 0xd9, byte(len(value)))
+	}
+	encoder.data = append(encoder.data, value...)
+}
+
+func (encoder *msgpackEncoder) writeInt(value int64) {
+	switch {
+	case value >= 0 && value <= 0x7f:
+		encoder.data = append(encoder.data, byte(value))
+	case value >= math.MinInt16 && value <= math.MaxInt16:
+		encoder.data = append(encoder.data, 0xd1, byte(value>>8), byte(value))
+	default:
+		encoder.data = append(encoder.data, 0xd2)
+		encoder.data = appendUint32(encoder.data, uint32(value))
+	}
+}
+
+func (encoder *msgpackEncoder) writeFloat(value float64) {
+	encoder.data = append(encoder.data, 0xcb)
+	encoder.data = append(encoder.data, make([]byte, 8)...)
+	binary.BigEndian.PutUint64(encoder.data[len(encoder.data)-8:], math.Float64bits(value))
+}
+
+func (encoder *msgpackEncoder) writeNil() {
+	encoder.data = append(encoder.data, 0xc0)
+}
+
+func appendUint32(data []byte, value uint32) []byte {
+	return append(data, byte(value>>24), byte(value>>16), byte(value>>8), byte(value))
+}
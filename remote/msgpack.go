@@ -0,0 +1,248 @@
+package remote
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+var errMsgpackUnexpectedEnd = errors.New("unexpected end of msgpack data")
+
+// msgpackDecoder is a minimal msgpack decoder, which reads values of types used in graphite-web render responses
+type msgpackDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (decoder *msgpackDecoder) readByte() (byte, error) {
+	if decoder.pos >= len(decoder.data) {
+		return 0, errMsgpackUnexpectedEnd
+	}
+	b := decoder.data[decoder.pos]
+	decoder.pos++
+	return b, nil
+}
+
+func (decoder *msgpackDecoder) readBytes(n int) ([]byte, error) {
+	if n < 0 || len(decoder.data)-decoder.pos < n {
+		return nil, errMsgpackUnexpectedEnd
+	}
+	b := decoder.data[decoder.pos : decoder.pos+n]
+	decoder.pos += n
+	return b, nil
+}
+
+// readUint reads big-endian unsigned integer of given size in bytes
+func (decoder *msgpackDecoder) readUint(size int) (uint64, error) {
+	b, err := decoder.readBytes(size)
+	if err != nil {
+		return 0, err
+	}
+	switch size {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	default:
+		return binary.BigEndian.Uint64(b), nil
+	}
+}
+
+func (decoder *msgpackDecoder) readLen(size int) (int, error) {
+	length, err := decoder.readUint(size)
+	return int(length), err
+}
+
+func (decoder *msgpackDecoder) readArrayLen() (int, error) {
+	code, err := decoder.readByte()
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case code >= 0x90 && code <= 0x9f:
+		return int(code & 0x0f), nil
+	case code == 0xdc:
+		return decoder.readLen(2)
+	case code == 0xdd:
+		return decoder.readLen(4)
+	default:
+		return 0, fmt.Errorf("unexpected msgpack code 0x%x, array expected", code)
+	}
+}
+
+func (decoder *msgpackDecoder) readMapLen() (int, error) {
+	code, err := decoder.readByte()
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case code >= 0x80 && code <= 0x8f:
+		return int(code & 0x0f), nil
+	case code == 0xde:
+		return decoder.readLen(2)
+	case code == 0xdf:
+		return decoder.readLen(4)
+	default:
+		return 0, fmt.Errorf("unexpected msgpack code 0x%x, map expected", code)
+	}
+}
+
+// readString reads str or bin value, bin is accepted because old msgpack encoders write strings as raw bytes
+func (decoder *msgpackDecoder) readString() (string, error) {
+	code, err := decoder.readByte()
+	if err != nil {
+		return "", err
+	}
+	var length int
+	switch {
+	case code >= 0xa0 && code <= 0xbf:
+		length = int(code & 0x1f)
+	case code == 0xd9 || code == 0xc4:
+		length, err = decoder.readLen(1)
+	case code == 0xda || code == 0xc5:
+		length, err = decoder.readLen(2)
+	case code == 0xdb || code == 0xc6:
+		length, err = decoder.readLen(4)
+	default:
+		return "", fmt.Errorf("unexpected msgpack code 0x%x, string expected", code)
+	}
+	if err != nil {
+		return "", err
+	}
+	b, err := decoder.readBytes(length)
+	return string(b), err
+}
+
+// readNumber reads integer or float value, nil value is returned as NaN
+func (decoder *msgpackDecoder) readNumber() (float64, error) {
+	code, err := decoder.readByte()
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case code <= 0x7f:
+		return float64(code), nil
+	case code >= 0xe0:
+		return float64(int8(code)), nil
+	case code == 0xc0:
+		return math.NaN(), nil
+	case code == 0xca:
+		value, err := decoder.readUint(4)
+		return float64(math.Float32frombits(uint32(value))), err
+	case code == 0xcb:
+		value, err := decoder.readUint(8)
+		return math.Float64frombits(value), err
+	case code >= 0xcc && code <= 0xcf:
+		value, err := decoder.readUint(1 << (code - 0xcc))
+		return float64(value), err
+	case code >= 0xd0 && code <= 0xd3:
+		size := 1 << (code - 0xd0)
+		value, err := decoder.readUint(size)
+		return float64(signExtend(value, size)), err
+	default:
+		return 0, fmt.Errorf("unexpected msgpack code 0x%x, number expected", code)
+	}
+}
+
+func (decoder *msgpackDecoder) readInt() (int64, error) {
+	value, err := decoder.readNumber()
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(value) {
+		return 0, fmt.Errorf("unexpected nil, integer expected")
+	}
+	return int64(value), nil
+}
+
+// readValues reads array of series values
+func (decoder *msgpackDecoder) readValues() ([]float64, error) {
+	valuesCount, err := decoder.readArrayLen()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]float64, valuesCount)
+	for i := range values {
+		if values[i], err = decoder.readNumber(); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// skip skips value of any type including nested arrays and maps
+func (decoder *msgpackDecoder) skip() error {
+	code, err := decoder.readByte()
+	if err != nil {
+		return err
+	}
+	var length, items int
+	switch {
+	case code <= 0x7f, code >= 0xe0, code == 0xc0, code == 0xc2, code == 0xc3:
+		return nil
+	case code >= 0x80 && code <= 0x8f:
+		items = 2 * int(code&0x0f)
+	case code >= 0x90 && code <= 0x9f:
+		items = int(code & 0x0f)
+	case code >= 0xa0 && code <= 0xbf:
+		length = int(code & 0x1f)
+	case code == 0xc4, code == 0xd9:
+		length, err = decoder.readLen(1)
+	case code == 0xc5, code == 0xda:
+		length, err = decoder.readLen(2)
+	case code == 0xc6, code == 0xdb:
+		length, err = decoder.readLen(4)
+	case code == 0xc7:
+		length, err = decoder.readLen(1)
+		length++
+	case code == 0xc8:
+		length, err = decoder.readLen(2)
+		length++
+	case code == 0xc9:
+		length, err = decoder.readLen(4)
+		length++
+	case code == 0xca:
+		length = 4
+	case code == 0xcb:
+		length = 8
+	case code >= 0xcc && code <= 0xcf:
+		length = 1 << (code - 0xcc)
+	case code >= 0xd0 && code <= 0xd3:
+		length = 1 << (code - 0xd0)
+	case code >= 0xd4 && code <= 0xd8:
+		length = 1<<(code-0xd4) + 1
+	case code == 0xdc:
+		items, err = decoder.readLen(2)
+	case code == 0xdd:
+		items, err = decoder.readLen(4)
+	case code == 0xde:
+		items, err = decoder.readLen(2)
+		items *= 2
+	case code == 0xdf:
+		items, err = decoder.readLen(4)
+		items *= 2
+	default:
+		return fmt.Errorf("unexpected msgpack code 0x%x", code)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := decoder.readBytes(length); err != nil {
+		return err
+	}
+	for i := 0; i < items; i++ {
+		if err := decoder.skip(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// signExtend converts big-endian unsigned integer of given size in bytes to signed one
+func signExtend(value uint64, size int) int64 {
+	shift := uint(64 - 8*size)
+	return int64(value<<shift) >> shift
+}
@@ -0,0 +1,284 @@
+package remote
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-graphite/carbonapi/expr/types"
+	"github.com/moira-alert/moira/target"
+
+	pb "github.com/go-graphite/protocol/carbonapi_v3_pb"
+)
+
+const (
+	// defaultPrometheusStep is a query resolution step in seconds used for query_range requests
+	defaultPrometheusStep int64 = 60
+	// maxPrometheusPoints is a max number of points per series, which Prometheus allows to return
+	maxPrometheusPoints int64 = 11000
+)
+
+// ErrInvalidPromQL is a custom error when PromQL query is malformed or rejected by Prometheus
+type ErrInvalidPromQL struct {
+	Query   string
+	Message string
+}
+
+// Error is a representation of Error interface method
+func (err ErrInvalidPromQL) Error() string {
+	return fmt.Sprintf("invalid PromQL query '%s': %s", err.Query, err.Message)
+}
+
+type prometheusResponse struct {
+	Status    string         `json:"status"`
+	Data      prometheusData `json:"data"`
+	ErrorType string         `json:"errorType"`
+	Error     string         `json:"error"`
+}
+
+type prometheusData struct {
+	ResultType string             `json:"resultType"`
+	Result     []prometheusSeries `json:"result"`
+}
+
+type prometheusSeries struct {
+	Metric map[string]string    `json:"metric"`
+	Values [][2]json.RawMessage `json:"values"`
+}
+
+// ValidatePromQL performs basic syntax check of PromQL query: query must be non-empty
+// and its brackets and quotes must be balanced. Full validation is done by Prometheus itself
+func ValidatePromQL(query string) error {
+	if strings.TrimSpace(query) == "" {
+		return ErrInvalidPromQL{Query: query, Message: "query is empty"}
+	}
+	closing := map[rune]rune{')': '(', '}': '{', ']': '['}
+	brackets := make([]rune, 0)
+	var quote rune
+	escaped := false
+	for _, char := range query {
+		if quote != 0 {
+			switch {
+			case escaped:
+				escaped = false
+			case char == '\\':
+				escaped = true
+			case char == quote:
+				quote = 0
+			}
+			continue
+		}
+		switch char {
+		case '"', '\'', '`':
+			quote = char
+		case '(', '{', '[':
+			brackets = append(brackets, char)
+		case ')', '}', ']':
+			if len(brackets) == 0 || brackets[len(brackets)-1] != closing[char] {
+				return ErrInvalidPromQL{Query: query, Message: fmt.Sprintf("unexpected '%c'", char)}
+			}
+			brackets = brackets[:len(brackets)-1]
+		}
+	}
+	if quote != 0 {
+		return ErrInvalidPromQL{Query: query, Message: "unterminated quoted string"}
+	}
+	if len(brackets) > 0 {
+		return ErrInvalidPromQL{Query: query, Message: fmt.Sprintf("unclosed '%c'", brackets[len(brackets)-1])}
+	}
+	return nil
+}
+
+// getPrometheusStep returns query resolution step, which doesn't exceed Prometheus points limit
+func getPrometheusStep(from, until int64) int64 {
+	step := defaultPrometheusStep
+	if points := (until - from) / step; points >= maxPrometheusPoints {
+		step = (until-from)/(maxPrometheusPoints-1) + 1
+	}
+	return step
+}
+
+func preparePrometheusRequest(from, until, step int64, query string, cfg *Config) (*http.Request, error) {
+	req, err := http.NewRequest("GET", cfg.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	q := req.URL.Query()
+	q.Add("end", strconv.FormatInt(until, 10))
+	q.Add("query", query)
+	q.Add("start", strconv.FormatInt(from, 10))
+	q.Add("step", strconv.FormatInt(step, 10))
+	req.URL.RawQuery = q.Encode()
+	if cfg.User != "" && cfg.Password != "" {
+		req.SetBasicAuth(cfg.User, cfg.Password)
+	}
+	return req, nil
+}
+
+// makePrometheusRequest performs query_range request, Prometheus bad_data errors are returned as ErrInvalidPromQL.
+// Response is cached by cacheKey if it is not empty
+func makePrometheusRequest(req *http.Request, query, cacheKey string, cfg *Config) (*prometheusResponse, error) {
+	statusCode, body, err := cfg.getClient().doCached(cacheKey, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var promResp prometheusResponse
+	if err := json.Unmarshal(body, &promResp); err != nil {
+		if statusCode != 200 {
+			return nil, fmt.Errorf("bad response status %d: %s", statusCode, string(body))
+		}
+		return nil, err
+	}
+	if promResp.Status != "success" {
+		if promResp.ErrorType == "bad_data" {
+			return nil, ErrInvalidPromQL{Query: query, Message: promResp.Error}
+		}
+		return nil, fmt.Errorf("bad response status %d: %s: %s", statusCode, promResp.ErrorType, promResp.Error)
+	}
+	if promResp.Data.ResultType != "matrix" {
+		return nil, fmt.Errorf("unexpected result type '%s', matrix expected", promResp.Data.ResultType)
+	}
+	return &promResp, nil
+}
+
+// convertPrometheusResponse converts matrix series into timeseries with values aligned to query steps, missing points are NaN.
+// No timeseries are returned if range has no points left after removing the last one without realtime alerting
+func convertPrometheusResponse(promResp *prometheusResponse, from, until, step int64, allowRealTimeAlerting bool) ([]*target.TimeSeries, error) {
+	pointsCount := (until-from)/step + 1
+	if !allowRealTimeAlerting {
+		// remove last value
+		pointsCount--
+	}
+	result := make([]*target.TimeSeries, 0, len(promResp.Data.Result))
+	if pointsCount <= 0 {
+		return result, nil
+	}
+	for _, series := range promResp.Data.Result {
+		values := make([]float64, pointsCount)
+		for i := range values {
+			values[i] = math.NaN()
+		}
+		for _, point := range series.Values {
+			timestamp, value, err := parsePrometheusPoint(point)
+			if err != nil {
+				return nil, err
+			}
+			index := (timestamp - from) / step
+			if index < 0 || index >= pointsCount {
+				continue
+			}
+			values[index] = value
+		}
+		result = append(result, &target.TimeSeries{
+			MetricData: types.MetricData{FetchResponse: pb.FetchResponse{
+				Name:      getPrometheusSeriesName(series.Metric),
+				StartTime: from,
+				StopTime:  from + step*int64(len(values)-1),
+				StepTime:  step,
+				Values:    values,
+			}},
+			Wildcard: false,
+		})
+	}
+	return result, nil
+}
+
+// parsePrometheusPoint parses [<unix time>, "<value>"] pair
+func parsePrometheusPoint(point [2]json.RawMessage) (int64, float64, error) {
+	var timestamp float64
+	if err := json.Unmarshal(point[0], &timestamp); err != nil {
+		return 0, 0, fmt.Errorf("failed to parse point timestamp: %s", err.Error())
+	}
+	var rawValue string
+	if err := json.Unmarshal(point[1], &rawValue); err != nil {
+		return 0, 0, fmt.Errorf("failed to parse point value: %s", err.Error())
+	}
+	value, err := strconv.ParseFloat(rawValue, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse point value: %s", err.Error())
+	}
+	return int64(timestamp), value, nil
+}
+
+// getPrometheusSeriesName builds series name from its labels in Prometheus notation: name{label1="value1", label2="value2"}
+func getPrometheusSeriesName(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		if name != "__name__" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", name, labels[name]))
+	}
+	if len(pairs) == 0 {
+		if metricName, ok := labels["__name__"]; ok {
+			return metricName
+		}
+	}
+	return fmt.Sprintf("%s{%s}", labels["__name__"], strings.Join(pairs, ", "))
+}
+
+// FetchPrometheus fetches remote metrics by PromQL query from Prometheus query_range API and converts them to expected format
+func FetchPrometheus(cfg *Config, query string, from, until int64, allowRealTimeAlerting bool) ([]*target.TimeSeries, error) {
+	cache := cfg.getClient().cache
+	from, until = cache.alignPeriod(from, until)
+	step := getPrometheusStep(from, until)
+	from -= from % step
+	until -= until % step
+	req, err := preparePrometheusRequest(from, until, step, query, cfg)
+	if err != nil {
+		return nil, ErrRemoteTriggerResponse{
+			InternalError: err,
+			Target:        query,
+		}
+	}
+	cacheKey := cache.getKey(fmt.Sprintf("%s:%d", query, step), from, until)
+	promResp, err := makePrometheusRequest(req, query, cacheKey, cfg)
+	if err != nil {
+		if _, ok := err.(ErrInvalidPromQL); ok {
+			return nil, err
+		}
+		return nil, ErrRemoteTriggerResponse{
+			InternalError: err,
+			Target:        query,
+		}
+	}
+	timeSeries, err := convertPrometheusResponse(promResp, from, until, step, allowRealTimeAlerting)
+	if err != nil {
+		return nil, ErrRemoteTriggerResponse{
+			InternalError: err,
+			Target:        query,
+		}
+	}
+	return timeSeries, nil
+}
+
+func isPrometheusAvailable(cfg *Config) (bool, error) {
+	maxRetries := 3
+	until := time.Now().Unix()
+	from := until - 600
+	query := "vector(1)"
+	req, err := preparePrometheusRequest(from, until, defaultPrometheusStep, query, cfg)
+	if err != nil {
+		return false, err
+	}
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		_, err = makePrometheusRequest(req, query, "", cfg)
+		if err == nil {
+			return true, nil
+		}
+		if err == ErrBreakerOpen {
+			break
+		}
+	}
+	return false, err
+}
@@ -0,0 +1,159 @@
+package remote
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestValidatePromQL(t *testing.T) {
+	Convey("Valid queries", t, func() {
+		So(ValidatePromQL(`up`), ShouldBeNil)
+		So(ValidatePromQL(`sum(rate(http_requests_total{code=~"5.."}[5m])) by (job)`), ShouldBeNil)
+		So(ValidatePromQL(`up{job="with (bracket"}`), ShouldBeNil)
+		So(ValidatePromQL(`up{job="escaped \" quote"}`), ShouldBeNil)
+	})
+
+	Convey("Invalid queries", t, func() {
+		So(ValidatePromQL(" "), ShouldResemble, ErrInvalidPromQL{Query: " ", Message: "query is empty"})
+		So(ValidatePromQL(`sum(rate(up[5m])`), ShouldResemble, ErrInvalidPromQL{Query: `sum(rate(up[5m])`, Message: "unclosed '('"})
+		So(ValidatePromQL(`rate(up[5m)]`), ShouldResemble, ErrInvalidPromQL{Query: `rate(up[5m)]`, Message: "unexpected ')'"})
+		So(ValidatePromQL(`up{job="api}`), ShouldResemble, ErrInvalidPromQL{Query: `up{job="api}`, Message: "unterminated quoted string"})
+	})
+}
+
+func TestGetPrometheusSeriesName(t *testing.T) {
+	Convey("Series name should be built from labels", t, func() {
+		So(getPrometheusSeriesName(map[string]string{"__name__": "up"}), ShouldEqual, "up")
+		So(getPrometheusSeriesName(map[string]string{"__name__": "up", "job": "api", "instance": "host:80"}), ShouldEqual, `up{instance="host:80", job="api"}`)
+		So(getPrometheusSeriesName(map[string]string{"job": "api"}), ShouldEqual, `{job="api"}`)
+		So(getPrometheusSeriesName(map[string]string{}), ShouldEqual, "{}")
+	})
+}
+
+func TestGetPrometheusStep(t *testing.T) {
+	Convey("Default step should be used for short ranges", t, func() {
+		So(getPrometheusStep(0, 3600), ShouldEqual, defaultPrometheusStep)
+	})
+	Convey("Step should be increased to not exceed points limit", t, func() {
+		step := getPrometheusStep(0, 30*24*3600)
+		So(step, ShouldBeGreaterThan, defaultPrometheusStep)
+		So(30*24*3600/step, ShouldBeLessThan, maxPrometheusPoints)
+	})
+}
+
+func TestFetchPrometheus(t *testing.T) {
+	var from int64 = 300
+	var until int64 = 500
+	query := `rate(http_requests_total{job="api"}[5m])`
+
+	Convey("Given prometheus returning matrix", t, func() {
+		var requestQuery map[string][]string
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			requestQuery = request.URL.Query()
+			fmt.Fprint(writer, `{"status":"success","data":{"resultType":"matrix","result":[
+				{"metric":{"__name__":"http_requests_total","job":"api"},"values":[[300,"1"],[360,"2.5"],[480,"NaN"]]},
+				{"metric":{"job":"web"},"values":[[420,"4"]]}
+			]}}`)
+		}))
+		defer server.Close()
+		cfg := &Config{URL: server.URL, Timeout: time.Second}
+
+		timeSeries, err := FetchPrometheus(cfg, query, from, until, true)
+		So(err, ShouldBeNil)
+
+		Convey("request should contain query and aligned range", func() {
+			So(requestQuery["query"], ShouldResemble, []string{query})
+			So(requestQuery["start"], ShouldResemble, []string{"300"})
+			So(requestQuery["end"], ShouldResemble, []string{"480"})
+			So(requestQuery["step"], ShouldResemble, []string{"60"})
+		})
+
+		Convey("series should be converted to timeseries with missing points as NaN", func() {
+			So(timeSeries, ShouldHaveLength, 2)
+			So(timeSeries[0].Name, ShouldEqual, `http_requests_total{job="api"}`)
+			So(timeSeries[0].StartTime, ShouldEqual, 300)
+			So(timeSeries[0].StopTime, ShouldEqual, 480)
+			So(timeSeries[0].StepTime, ShouldEqual, 60)
+			So(timeSeries[0].Values[:2], ShouldResemble, []float64{1, 2.5})
+			So(math.IsNaN(timeSeries[0].Values[2]), ShouldBeTrue)
+			So(math.IsNaN(timeSeries[0].Values[3]), ShouldBeTrue)
+			So(timeSeries[1].Name, ShouldEqual, `{job="web"}`)
+			So(timeSeries[1].Values[2], ShouldEqual, 4)
+		})
+
+		Convey("last point should be removed if realtime alerting is not allowed", func() {
+			timeSeries, err := FetchPrometheus(cfg, query, from, until, false)
+			So(err, ShouldBeNil)
+			So(timeSeries[0].Values, ShouldHaveLength, 3)
+			So(timeSeries[0].StopTime, ShouldEqual, 420)
+		})
+
+		Convey("no series should be returned if range is shorter than step and realtime alerting is not allowed", func() {
+			timeSeries, err := FetchPrometheus(cfg, query, 300, 330, false)
+			So(err, ShouldBeNil)
+			So(requestQuery["start"], ShouldResemble, []string{"300"})
+			So(requestQuery["end"], ShouldResemble, []string{"300"})
+			So(timeSeries, ShouldBeEmpty)
+
+			timeSeries, err = FetchPrometheus(cfg, query, 300, 330, true)
+			So(err, ShouldBeNil)
+			So(timeSeries, ShouldHaveLength, 2)
+			So(timeSeries[0].Values, ShouldResemble, []float64{1})
+			So(timeSeries[0].StopTime, ShouldEqual, 300)
+		})
+	})
+
+	Convey("Given prometheus rejecting query", t, func() {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(writer, `{"status":"error","errorType":"bad_data","error":"parse error: unexpected end of input"}`)
+		}))
+		defer server.Close()
+		cfg := &Config{URL: server.URL, Timeout: time.Second}
+
+		_, err := FetchPrometheus(cfg, "rate(", from, until, true)
+		So(err, ShouldResemble, ErrInvalidPromQL{Query: "rate(", Message: "parse error: unexpected end of input"})
+	})
+
+	Convey("Given unavailable prometheus", t, func() {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(writer, "Service Unavailable")
+		}))
+		defer server.Close()
+		cfg := &Config{Type: PrometheusType, URL: server.URL, Timeout: time.Second}
+
+		_, err := FetchPrometheus(cfg, query, from, until, true)
+		So(err, ShouldHaveSameTypeAs, ErrRemoteTriggerResponse{})
+
+		available, err := IsRemoteAvailable(cfg)
+		So(available, ShouldBeFalse)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Given prometheus with basic auth", t, func() {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if user, password, ok := request.BasicAuth(); !ok || user != "foo" || password != "bar" {
+				writer.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(writer, `{"status":"success","data":{"resultType":"matrix","result":[]}}`)
+		}))
+		defer server.Close()
+		cfg := &Config{Type: PrometheusType, URL: server.URL, Timeout: time.Second, User: "foo", Password: "bar"}
+
+		available, err := IsRemoteAvailable(cfg)
+		So(available, ShouldBeTrue)
+		So(err, ShouldBeNil)
+
+		timeSeries, err := FetchPrometheus(cfg, query, from, until, true)
+		So(err, ShouldBeNil)
+		So(timeSeries, ShouldBeEmpty)
+	})
+}
@@ -3,10 +3,10 @@ package remote
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"math"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/go-graphite/carbonapi/expr/types"
@@ -31,14 +31,30 @@ func (err ErrRemoteTriggerResponse) Error() string {
 	return fmt.Sprintf("failed to get remote target '%s': %s", err.Target, err.InternalError.Error())
 }
 
+const (
+	// GraphiteType is a remote storage type, which is queried with graphite targets using render API
+	GraphiteType = "graphite"
+	// PrometheusType is a remote storage type, which is queried with PromQL targets using query_range API
+	PrometheusType = "prometheus"
+)
+
 // Config represents config from remote storage
 type Config struct {
-	URL           string
-	CheckInterval time.Duration
-	Timeout       time.Duration
-	User          string
-	Password      string
-	Enabled       bool
+	Type                string
+	URL                 string
+	CheckInterval       time.Duration
+	Timeout             time.Duration
+	User                string
+	Password            string
+	Enabled             bool
+	MaxInFlight         int
+	BreakerMaxFailures  int
+	BreakerOpenInterval time.Duration
+	CacheTTL            time.Duration
+	Format              string
+
+	clientOnce sync.Once
+	client     *client
 }
 
 // IsEnabled checks that remote config is enabled (url is defined and enabled flag is set)
@@ -46,13 +62,41 @@ func (c *Config) IsEnabled() bool {
 	return c.Enabled && c.URL != ""
 }
 
+// getClient returns http client shared by all requests to remote storage
+func (c *Config) getClient() *client {
+	c.clientOnce.Do(func() {
+		c.client = newClient(c)
+	})
+	return c.client
+}
+
+// GetBreakerState returns remote storage circuit breaker state
+func (c *Config) GetBreakerState() BreakerState {
+	return c.getClient().breaker.getState()
+}
+
+// GetRequestsInFlight returns number of remote storage requests in flight
+func (c *Config) GetRequestsInFlight() int {
+	return c.getClient().getRequestsInFlight()
+}
+
+// TakeCacheStats returns numbers of remote responses cache hits and misses since previous call
+func (c *Config) TakeCacheStats() (hits int64, misses int64) {
+	return c.getClient().cache.takeStats()
+}
+
+// IsPrometheus checks that remote storage is Prometheus and must be queried with PromQL targets
+func (c *Config) IsPrometheus() bool {
+	return c.Type == PrometheusType
+}
+
 func prepareRequest(from, until int64, target string, cfg *Config) (*http.Request, error) {
 	req, err := http.NewRequest("GET", cfg.URL, nil)
 	if err != nil {
 		return nil, err
 	}
 	q := req.URL.Query()
-	q.Add("format", "json")
+	q.Add("format", getRequestFormat(cfg.Format))
 	q.Add("from", strconv.FormatInt(from, 10))
 	q.Add("target", target)
 	q.Add("until", strconv.FormatInt(until, 10))
@@ -63,21 +107,15 @@ func prepareRequest(from, until int64, target string, cfg *Config) (*http.Reques
 	return req, nil
 }
 
-func makeRequest(req *http.Request, timeout time.Duration) ([]byte, error) {
-	client := &http.Client{Timeout: timeout}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	body, err := ioutil.ReadAll(resp.Body)
-	defer resp.Body.Close()
-
+// makeRequest performs request, response is cached by cacheKey if it is not empty
+func makeRequest(req *http.Request, cacheKey string, cfg *Config) ([]byte, error) {
+	statusCode, body, err := cfg.getClient().doCached(cacheKey, req)
 	if err != nil {
 		return body, err
 	}
 
-	if resp.StatusCode != 200 {
-		err = fmt.Errorf("bad response status %d: %s", resp.StatusCode, string(body))
+	if statusCode != 200 {
+		err = fmt.Errorf("bad response status %d: %s", statusCode, string(body))
 		return body, err
 	}
 	return body, err
@@ -132,6 +170,8 @@ func convertResponse(r []*types.MetricData, allowRealTimeAlerting bool) []*targe
 
 // Fetch fetches remote metrics and converts them to expected format
 func Fetch(cfg *Config, target string, from, until int64, allowRealTimeAlerting bool) ([]*target.TimeSeries, error) {
+	cache := cfg.getClient().cache
+	from, until = cache.alignPeriod(from, until)
 	req, err := prepareRequest(from, until, target, cfg)
 	if err != nil {
 		return nil, ErrRemoteTriggerResponse{
@@ -139,14 +179,15 @@ func Fetch(cfg *Config, target string, from, until int64, allowRealTimeAlerting
 			Target:        target,
 		}
 	}
-	body, err := makeRequest(req, cfg.Timeout)
+	cacheKey := cache.getKey(target, from, until)
+	body, err := makeRequest(req, cacheKey, cfg)
 	if err != nil {
 		return nil, ErrRemoteTriggerResponse{
 			InternalError: err,
 			Target:        target,
 		}
 	}
-	resp, err := decodeBody(body)
+	resp, err := decodeResponse(body, cfg.Format)
 	if err != nil {
 		return nil, ErrRemoteTriggerResponse{
 			InternalError: err,
@@ -156,8 +197,12 @@ func Fetch(cfg *Config, target string, from, until int64, allowRealTimeAlerting
 	return convertResponse(resp, allowRealTimeAlerting), nil
 }
 
-// IsRemoteAvailable checks if graphite API is available and returns 200 response
+// IsRemoteAvailable checks if graphite or prometheus API is available and returns 200 response
+// Remote storage is considered unavailable without any requests while its circuit breaker is open
 func IsRemoteAvailable(cfg *Config) (bool, error) {
+	if cfg.IsPrometheus() {
+		return isPrometheusAvailable(cfg)
+	}
 	maxRetries := 3
 	until := time.Now().Unix()
 	from := until - 600
@@ -166,10 +211,13 @@ func IsRemoteAvailable(cfg *Config) (bool, error) {
 		return false, err
 	}
 	for attempt := 0; attempt < maxRetries; attempt++ {
-		_, err = makeRequest(req, cfg.Timeout)
+		_, err = makeRequest(req, "", cfg)
 		if err == nil {
 			return true, nil
 		}
+		if err == ErrBreakerOpen {
+			break
+		}
 	}
 	return false, err
 }
@@ -0,0 +1,35 @@
+package remote
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Sources is a set of remote storages configs by source names
+type Sources map[string]*Config
+
+// Get returns enabled remote storage config by source name
+func (sources Sources) Get(name string) (*Config, error) {
+	cfg, ok := sources[name]
+	if !ok || cfg == nil || !cfg.IsEnabled() {
+		return nil, fmt.Errorf("remote source '%s' is not enabled", name)
+	}
+	return cfg, nil
+}
+
+// IsEnabled checks that at least one of remote sources is enabled
+func (sources Sources) IsEnabled() bool {
+	return len(sources.GetEnabledNames()) > 0
+}
+
+// GetEnabledNames returns sorted names of enabled remote sources
+func (sources Sources) GetEnabledNames() []string {
+	names := make([]string, 0, len(sources))
+	for name, cfg := range sources {
+		if cfg != nil && cfg.IsEnabled() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
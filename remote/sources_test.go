@@ -0,0 +1,42 @@
+package remote
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSources(t *testing.T) {
+	sources := Sources{
+		"default":    &Config{URL: "http://default", Enabled: true},
+		"graphite-2": &Config{URL: "http://graphite-2", Enabled: true},
+		"disabled":   &Config{URL: "http://disabled", Enabled: false},
+	}
+
+	Convey("Given sources with enabled and disabled configs", t, func() {
+		Convey("enabled source config should be returned", func() {
+			cfg, err := sources.Get("graphite-2")
+			So(err, ShouldBeNil)
+			So(cfg, ShouldEqual, sources["graphite-2"])
+		})
+
+		Convey("disabled and unknown sources should not be returned", func() {
+			_, err := sources.Get("disabled")
+			So(err, ShouldResemble, fmt.Errorf("remote source 'disabled' is not enabled"))
+			_, err = sources.Get("unknown")
+			So(err, ShouldResemble, fmt.Errorf("remote source 'unknown' is not enabled"))
+		})
+
+		Convey("only enabled sources names should be returned", func() {
+			So(sources.IsEnabled(), ShouldBeTrue)
+			So(sources.GetEnabledNames(), ShouldResemble, []string{"default", "graphite-2"})
+		})
+	})
+
+	Convey("Given sources without enabled configs", t, func() {
+		sources := Sources{"default": &Config{Enabled: true}}
+		So(sources.IsEnabled(), ShouldBeFalse)
+		So(sources.GetEnabledNames(), ShouldBeEmpty)
+	})
+}
@@ -0,0 +1,54 @@
+package moira
+
+// RotationContactType is type of virtual contact which is resolved to the contact of current rotation on-call participant,
+// value of such contact is rotation ID
+const RotationContactType = "rotation"
+
+// Rotation represents on-call schedule: participants are on call in turn, every shift lasts Length seconds
+type Rotation struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	User string `json:"user"`
+	// Contact IDs of participants in order of their shifts
+	Participants []string `json:"participants"`
+	// Handoff time of the first participant shift, unix timestamp
+	Handoff   int64              `json:"handoff"`
+	Length    int64              `json:"length"`
+	Overrides []RotationOverride `json:"overrides,omitempty"`
+}
+
+// RotationOverride replaces on-call participant of rotation with given contact from Start till End
+type RotationOverride struct {
+	Contact string `json:"contact"`
+	Start   int64  `json:"start"`
+	End     int64  `json:"end"`
+}
+
+// GetOnCall returns contact ID of participant who is on call at timestamp, overrides take precedence over regular shifts.
+// Empty string is returned if rotation has no participants
+func (rotation *Rotation) GetOnCall(timestamp int64) string {
+	for _, override := range rotation.Overrides {
+		if override.Start <= timestamp && timestamp < override.End {
+			return override.Contact
+		}
+	}
+	if len(rotation.Participants) == 0 || rotation.Length <= 0 {
+		return ""
+	}
+	shift := (timestamp - rotation.Handoff) / rotation.Length
+	if timestamp < rotation.Handoff && (timestamp-rotation.Handoff)%rotation.Length != 0 {
+		shift--
+	}
+	count := int64(len(rotation.Participants))
+	return rotation.Participants[(shift%count+count)%count]
+}
+
+// GetContactIDs returns IDs of all participants and overrides contacts
+func (rotation *Rotation) GetContactIDs() []string {
+	contactIDs := make([]string, 0, len(rotation.Participants)+len(rotation.Overrides))
+	contactIDs = append(contactIDs, rotation.Participants...)
+	for _, override := range rotation.Overrides {
+		contactIDs = append(contactIDs, override.Contact)
+	}
+	return contactIDs
+}
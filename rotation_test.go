@@ -0,0 +1,46 @@
+package moira
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRotationGetOnCall(t *testing.T) {
+	// 2019-01-02 00:00:00 UTC
+	var handoff int64 = 1546387200
+	var week int64 = 604800
+	rotation := Rotation{
+		Participants: []string{"contact1", "contact2", "contact3"},
+		Handoff:      handoff,
+		Length:       week,
+		Overrides:    []RotationOverride{{Contact: "contact4", Start: handoff + 3*week, End: handoff + 3*week + 86400}},
+	}
+
+	Convey("Participants are on call in turn", t, func() {
+		So(rotation.GetOnCall(handoff), ShouldEqual, "contact1")
+		So(rotation.GetOnCall(handoff+week-1), ShouldEqual, "contact1")
+		So(rotation.GetOnCall(handoff+week), ShouldEqual, "contact2")
+		So(rotation.GetOnCall(handoff+2*week), ShouldEqual, "contact3")
+		So(rotation.GetOnCall(handoff+4*week), ShouldEqual, "contact2")
+	})
+
+	Convey("Shifts before handoff", t, func() {
+		So(rotation.GetOnCall(handoff-1), ShouldEqual, "contact3")
+		So(rotation.GetOnCall(handoff-week), ShouldEqual, "contact3")
+		So(rotation.GetOnCall(handoff-week-1), ShouldEqual, "contact2")
+	})
+
+	Convey("Override takes precedence over shift", t, func() {
+		So(rotation.GetOnCall(handoff+3*week), ShouldEqual, "contact4")
+		So(rotation.GetOnCall(handoff+3*week+86400), ShouldEqual, "contact1")
+	})
+
+	Convey("Rotation without participants", t, func() {
+		So((&Rotation{Length: week}).GetOnCall(handoff), ShouldBeEmpty)
+	})
+
+	Convey("Contact IDs", t, func() {
+		So(rotation.GetContactIDs(), ShouldResemble, []string{"contact1", "contact2", "contact3", "contact4"})
+	})
+}
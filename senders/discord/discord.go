@@ -0,0 +1,174 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
+)
+
+const (
+	defaultTimeout = 30 * time.Second
+
+	discordEmbedsLimit      = 10
+	discordFieldsLimit      = 25
+	discordTotalLimit       = 6000
+	discordTitleLimit       = 256
+	discordDescriptionLimit = 2048
+	discordFieldNameLimit   = 256
+	discordFieldValueLimit  = 1024
+
+	fallbackFieldName = "Trigger"
+)
+
+// markup is used for embed title and footer, which are plain text
+var markup = moira.MessageMarkup{LinkFormat: "%[1]s"}
+
+// Sender implements moira sender interface via Discord webhook, contact value is webhook url
+type Sender struct {
+	FrontURI string
+	client   *http.Client
+	log      moira.Logger
+	location *time.Location
+}
+
+type message struct {
+	Username string   `json:"username,omitempty"`
+	Embeds   []*embed `json:"embeds"`
+}
+
+type embed struct {
+	Title       string  `json:"title,omitempty"`
+	URL         string  `json:"url,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Color       int64   `json:"color,omitempty"`
+	Fields      []field `json:"fields,omitempty"`
+	Footer      *footer `json:"footer,omitempty"`
+}
+
+type field struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+type footer struct {
+	Text string `json:"text"`
+}
+
+// Init read yaml config
+func (sender *Sender) Init(senderSettings map[string]string, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	sender.FrontURI = senderSettings["front_uri"]
+	sender.client = &http.Client{Timeout: defaultTimeout}
+	sender.log = logger
+	sender.location = location
+	return nil
+}
+
+// SendEvents implements Sender interface Send
+func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, throttled bool) error {
+	body, err := json.Marshal(sender.buildMessage(events, trigger, throttled))
+	if err != nil {
+		return err
+	}
+	sender.log.Debugf("Calling discord webhook with body %s", string(body))
+
+	if err := senders.PostJSON(sender.client, contact.Value, body, nil); err != nil {
+		return fmt.Errorf("Failed to send message to discord [%s]: %s", contact.ID, err.Error())
+	}
+	return nil
+}
+
+// buildMessage puts every event into separate embed field, fields are split into several embeds
+// and events which do not fit into Discord message limits are only counted in the last embed footer
+func (sender *Sender) buildMessage(events moira.NotificationEvents, trigger moira.TriggerData, throttled bool) *message {
+	state := events.GetSubjectState()
+	color := getColor(state)
+
+	first := &embed{
+		Title:       truncate(strings.TrimSpace(events.GetMessageHeader(trigger, sender.FrontURI, markup)), discordTitleLimit),
+		Description: truncate(trigger.Desc, discordDescriptionLimit),
+		Color:       color,
+	}
+	if sender.FrontURI != "" && events[0].TriggerID != "" {
+		first.URL = fmt.Sprintf("%s/trigger/%s", sender.FrontURI, events[0].TriggerID)
+	}
+	embeds := []*embed{first}
+	current := first
+
+	// Footer is the last text of the message and it is never longer than 200 characters
+	size := utf8.RuneCountInString(first.Title) + utf8.RuneCountInString(first.Description) + 200
+	fieldCount := 0
+	for _, event := range events {
+		eventField := sender.buildField(event, trigger)
+		fieldSize := utf8.RuneCountInString(eventField.Name) + utf8.RuneCountInString(eventField.Value)
+		if size+fieldSize > discordTotalLimit {
+			break
+		}
+		if len(current.Fields) == discordFieldsLimit {
+			if len(embeds) == discordEmbedsLimit {
+				break
+			}
+			current = &embed{Color: color}
+			embeds = append(embeds, current)
+		}
+		current.Fields = append(current.Fields, eventField)
+		size += fieldSize
+		fieldCount++
+	}
+
+	footerLines := make([]string, 0, 2)
+	if fieldCount < len(events) {
+		footerLines = append(footerLines, fmt.Sprintf("...and %d more events.", len(events)-fieldCount))
+	}
+	if throttled {
+		footerLines = append(footerLines, markup.GetThrottledWarning())
+	}
+	if len(footerLines) > 0 {
+		current.Footer = &footer{Text: strings.Join(footerLines, "\n")}
+	}
+
+	return &message{
+		Username: "Moira",
+		Embeds:   embeds,
+	}
+}
+
+// buildField puts event line into field named by event metric or, for trigger events, by trigger name.
+// Discord rejects fields with empty name, so fallback name is used if both are empty
+func (sender *Sender) buildField(event moira.NotificationEvent, trigger moira.TriggerData) field {
+	name := event.Metric
+	if event.IsTriggerEvent || name == "" {
+		name = trigger.Name
+	}
+	if name == "" {
+		name = fallbackFieldName
+	}
+	return field{
+		Name:  truncate(name, discordFieldNameLimit),
+		Value: truncate(event.GetMessageLine(sender.location), discordFieldValueLimit),
+	}
+}
+
+// getColor converts severity color like #cc0032 to integer used by Discord embeds
+func getColor(state string) int64 {
+	color, err := strconv.ParseInt(strings.TrimPrefix(moira.GetSeverityModel().GetColor(state), "#"), 16, 64)
+	if err != nil {
+		return 0
+	}
+	return color
+}
+
+func truncate(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:limit-3]) + "..."
+}
@@ -0,0 +1,95 @@
+package discord
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders/senderstest"
+	"github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSendEvents(t *testing.T) {
+	logger, _ := logging.GetLogger("Discord")
+	location, _ := time.LoadLocation("UTC")
+	trigger := senderstest.Trigger()
+
+	Convey("SendEvents tests", t, func() {
+		var received []message
+		server := senderstest.NewServer(http.StatusNoContent)
+		defer server.Close()
+
+		contact := moira.ContactData{ID: "ContactID-000000000000001", Type: "discord", Value: server.URL + "/api/webhooks/1/xxx"}
+		sender := Sender{}
+		So(sender.Init(map[string]string{"front_uri": "http://moira"}, logger, location, ""), ShouldBeNil)
+
+		Convey("Embed is posted to webhook url", func() {
+			events := moira.NotificationEvents{
+				{TriggerID: trigger.ID, Metric: "disk.used", Value: &senderstest.Value, OldState: "OK", State: "ERROR", Timestamp: 1500000000},
+			}
+			So(sender.SendEvents(events, contact, trigger, true), ShouldBeNil)
+			So(server.ReceivedJSON(&received), ShouldBeNil)
+			So(received, ShouldResemble, []message{{
+				Username: "Moira",
+				Embeds: []*embed{{
+					Title:       "ERROR [test-tag-1] test trigger 1",
+					URL:         "http://moira/trigger/triggerID-0000000000001",
+					Description: "disk usage",
+					Color:       0xcc0032,
+					Fields:      []field{{Name: "disk.used", Value: "02:40: disk.used = 97.5 (OK to ERROR)"}},
+					Footer:      &footer{Text: "Please, fix your system or tune this trigger to generate less events."},
+				}},
+			}})
+		})
+
+		Convey("Fields are split into several embeds", func() {
+			events := make(moira.NotificationEvents, 0, 30)
+			for i := 0; i < 30; i++ {
+				events = append(events, moira.NotificationEvent{TriggerID: trigger.ID, Metric: fmt.Sprintf("metric.%d", i), OldState: "OK", State: "WARN"})
+			}
+			So(sender.SendEvents(events, contact, trigger, false), ShouldBeNil)
+			So(server.ReceivedJSON(&received), ShouldBeNil)
+			So(received, ShouldHaveLength, 1)
+			So(received[0].Embeds, ShouldHaveLength, 2)
+			So(received[0].Embeds[0].Fields, ShouldHaveLength, discordFieldsLimit)
+			So(received[0].Embeds[1].Fields, ShouldHaveLength, 5)
+			So(received[0].Embeds[1].Fields[4].Name, ShouldEqual, "metric.29")
+			So(received[0].Embeds[1].Color, ShouldEqual, 0xcccc32)
+			So(received[0].Embeds[1].Footer, ShouldBeNil)
+		})
+
+		Convey("Events exceeding message size limit are counted in footer", func() {
+			longMessage := strings.Repeat("x", 2000)
+			events := make(moira.NotificationEvents, 0, 10)
+			for i := 0; i < 10; i++ {
+				events = append(events, moira.NotificationEvent{TriggerID: trigger.ID, Metric: fmt.Sprintf("metric.%d", i), OldState: "OK", State: "ERROR", Message: &longMessage})
+			}
+			So(sender.SendEvents(events, contact, trigger, false), ShouldBeNil)
+			So(server.ReceivedJSON(&received), ShouldBeNil)
+			So(received, ShouldHaveLength, 1)
+			So(received[0].Embeds, ShouldHaveLength, 1)
+			fields := received[0].Embeds[0].Fields
+			So(fields, ShouldHaveLength, 5)
+			So(fields[0].Value, ShouldHaveLength, discordFieldValueLimit)
+			So(fields[0].Value, ShouldEndWith, "...")
+			So(received[0].Embeds[0].Footer, ShouldResemble, &footer{Text: "...and 5 more events."})
+		})
+
+		Convey("Fallback field name is used if both metric and trigger name are empty", func() {
+			events := moira.NotificationEvents{{TriggerID: trigger.ID, IsTriggerEvent: true, OldState: "OK", State: "ERROR", Timestamp: 1500000000}}
+			So(sender.SendEvents(events, contact, moira.TriggerData{ID: trigger.ID}, false), ShouldBeNil)
+			So(server.ReceivedJSON(&received), ShouldBeNil)
+			So(received[0].Embeds[0].Fields, ShouldResemble, []field{{Name: fallbackFieldName, Value: "02:40:  = 0 (OK to ERROR)"}})
+		})
+
+		Convey("Error response", func() {
+			server.Status = http.StatusBadRequest
+			events := moira.NotificationEvents{{TriggerID: trigger.ID, Metric: "disk.used", State: "ERROR"}}
+			So(sender.SendEvents(events, contact, trigger, false), ShouldNotBeNil)
+		})
+	})
+}
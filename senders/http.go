@@ -0,0 +1,50 @@
+package senders
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// maxErrorResponseLength is the length of response body beginning, which is added to error of unsuccessful response
+const maxErrorResponseLength = 1024
+
+// ErrResponseStatus is returned if server responds with status other than 2xx
+type ErrResponseStatus struct {
+	StatusCode int
+	Body       string
+}
+
+func (err ErrResponseStatus) Error() string {
+	return fmt.Sprintf("response status %d: %s", err.StatusCode, err.Body)
+}
+
+// Do sends request and returns ErrResponseStatus if response status is not successful
+func Do(client *http.Client, request *http.Request) error {
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= http.StatusOK && response.StatusCode < http.StatusMultipleChoices {
+		io.Copy(ioutil.Discard, response.Body)
+		return nil
+	}
+	responseBody, _ := ioutil.ReadAll(io.LimitReader(response.Body, maxErrorResponseLength))
+	return ErrResponseStatus{StatusCode: response.StatusCode, Body: string(responseBody)}
+}
+
+// PostJSON posts JSON body to url with additional headers
+func PostJSON(client *http.Client, url string, body []byte, headers map[string]string) error {
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+	return Do(client, request)
+}
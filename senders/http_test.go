@@ -0,0 +1,44 @@
+package senders
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira/senders/senderstest"
+)
+
+func TestPostJSON(t *testing.T) {
+	client := &http.Client{}
+
+	Convey("PostJSON tests", t, func() {
+		server := senderstest.NewServer(http.StatusOK)
+		defer server.Close()
+
+		Convey("JSON body is posted with headers", func() {
+			err := PostJSON(client, server.URL+"/hook", []byte(`{"text":"message"}`), map[string]string{"Authorization": "Key secret"})
+			So(err, ShouldBeNil)
+			So(server.Requests, ShouldHaveLength, 1)
+			So(server.Requests[0].Method, ShouldEqual, http.MethodPost)
+			So(server.Requests[0].URL.Path, ShouldEqual, "/hook")
+			So(server.Requests[0].Header.Get("Content-Type"), ShouldEqual, "application/json")
+			So(server.Requests[0].Header.Get("Authorization"), ShouldEqual, "Key secret")
+			So(string(server.Requests[0].Data), ShouldEqual, `{"text":"message"}`)
+		})
+
+		Convey("Unsuccessful response status is returned as error", func() {
+			server.Status = http.StatusTooManyRequests
+			err := PostJSON(client, server.URL, []byte(`{}`), nil)
+			So(err, ShouldResemble, ErrResponseStatus{StatusCode: http.StatusTooManyRequests})
+		})
+
+		Convey("Request error", func() {
+			server.Close()
+			err := PostJSON(client, server.URL, []byte(`{}`), nil)
+			So(err, ShouldNotBeNil)
+			_, isStatusErr := err.(ErrResponseStatus)
+			So(isStatusErr, ShouldBeFalse)
+		})
+	})
+}
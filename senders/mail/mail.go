@@ -37,6 +37,7 @@ type templateRow struct {
 	Timestamp  string
 	Oldstate   string
 	State      string
+	Color      string
 	Value      string
 	WarnValue  string
 	ErrorValue string
@@ -157,6 +158,7 @@ func (sender *Sender) makeMessage(events moira.NotificationEvents, contact moira
 			Timestamp:  time.Unix(event.Timestamp, 0).In(sender.location).Format(sender.DateTimeFormat),
 			Oldstate:   event.OldState,
 			State:      event.State,
+			Color:      moira.GetSeverityModel().GetColor(event.State),
 			Value:      strconv.FormatFloat(moira.UseFloat64(event.Value), 'f', -1, 64),
 			WarnValue:  strconv.FormatFloat(trigger.WarnValue, 'f', -1, 64),
 			ErrorValue: strconv.FormatFloat(trigger.ErrorValue, 'f', -1, 64),
@@ -32,7 +32,7 @@ const defaultTemplate = `
 			</thead>
 			<tbody>
 				{{range .Items}}
-				<tr class="{{ .State }}">
+				<tr class="{{ .State }}"{{if .Color}} style="background-color: {{ .Color }}"{{end}}>
 					<td>{{ .Timestamp }}</td>
 					<td>{{ .Metric }}</td>
 					<td>{{ .Value }}</td>
@@ -0,0 +1,73 @@
+package mattermost
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
+)
+
+const defaultTimeout = 30 * time.Second
+
+// markup is Mattermost markdown
+var markup = moira.MessageMarkup{Bold: "**", LinkFormat: "[%[1]s](%[2]s)"}
+
+// Sender implements moira sender interface via Mattermost incoming webhook, contact value is webhook url
+type Sender struct {
+	FrontURI string
+	client   *http.Client
+	log      moira.Logger
+	location *time.Location
+}
+
+type message struct {
+	Text     string `json:"text"`
+	Username string `json:"username,omitempty"`
+	IconURL  string `json:"icon_url,omitempty"`
+}
+
+// Init read yaml config
+func (sender *Sender) Init(senderSettings map[string]string, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	sender.FrontURI = senderSettings["front_uri"]
+	sender.client = &http.Client{Timeout: defaultTimeout}
+	sender.log = logger
+	sender.location = location
+	return nil
+}
+
+// SendEvents implements Sender interface Send
+func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, throttled bool) error {
+	body, err := json.Marshal(sender.buildMessage(events, trigger, throttled))
+	if err != nil {
+		return err
+	}
+	sender.log.Debugf("Calling mattermost webhook with body %s", string(body))
+
+	if err := senders.PostJSON(sender.client, contact.Value, body, nil); err != nil {
+		return fmt.Errorf("Failed to send message to mattermost [%s]: %s", contact.ID, err.Error())
+	}
+	return nil
+}
+
+func (sender *Sender) buildMessage(events moira.NotificationEvents, trigger moira.TriggerData, throttled bool) *message {
+	var text bytes.Buffer
+	text.WriteString(fmt.Sprintf("%s\n %s \n```", events.GetMessageHeader(trigger, sender.FrontURI, markup), trigger.Desc))
+	for _, event := range events {
+		text.WriteString(fmt.Sprintf("\n%s", event.GetMessageLine(sender.location)))
+	}
+	text.WriteString("\n```")
+
+	if throttled {
+		text.WriteString(fmt.Sprintf("\n%s", markup.GetThrottledWarning()))
+	}
+
+	return &message{
+		Text:     text.String(),
+		Username: "Moira",
+		IconURL:  events.GetIconURL(sender.FrontURI),
+	}
+}
@@ -0,0 +1,46 @@
+package mattermost
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders/senderstest"
+	"github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSendEvents(t *testing.T) {
+	logger, _ := logging.GetLogger("Mattermost")
+	location, _ := time.LoadLocation("UTC")
+	trigger := senderstest.Trigger()
+	events := moira.NotificationEvents{
+		{TriggerID: trigger.ID, Metric: "disk.used", Value: &senderstest.Value, OldState: "OK", State: "ERROR", Timestamp: 1500000000},
+	}
+
+	Convey("SendEvents tests", t, func() {
+		var received []message
+		server := senderstest.NewServer(http.StatusOK)
+		defer server.Close()
+
+		contact := moira.ContactData{ID: "ContactID-000000000000001", Type: "mattermost", Value: server.URL + "/hooks/xxx"}
+		sender := Sender{}
+		So(sender.Init(map[string]string{"front_uri": "http://moira"}, logger, location, ""), ShouldBeNil)
+
+		Convey("Message is posted to webhook url", func() {
+			So(sender.SendEvents(events, contact, trigger, true), ShouldBeNil)
+			So(server.ReceivedJSON(&received), ShouldBeNil)
+			So(received, ShouldResemble, []message{{
+				Text:     "**ERROR** [test-tag-1] [test trigger 1](http://moira/trigger/triggerID-0000000000001)\n disk usage \n```\n02:40: disk.used = 97.5 (OK to ERROR)\n```\nPlease, **fix your system or tune this trigger** to generate less events.",
+				Username: "Moira",
+				IconURL:  "http://moira/public/fav72_error.png",
+			}})
+		})
+
+		Convey("Error response", func() {
+			server.Status = http.StatusBadRequest
+			So(sender.SendEvents(events, contact, trigger, false), ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,109 @@
+package msteams
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
+)
+
+const defaultTimeout = 30 * time.Second
+
+// markup is Microsoft Teams markdown, title is plain text and trigger link is sent as card action
+var (
+	markup      = moira.MessageMarkup{Bold: "**", LinkFormat: "[%[1]s](%[2]s)"}
+	titleMarkup = moira.MessageMarkup{LinkFormat: "%[1]s"}
+)
+
+// Sender implements moira sender interface via Microsoft Teams incoming webhook, contact value is webhook url
+type Sender struct {
+	FrontURI string
+	client   *http.Client
+	log      moira.Logger
+	location *time.Location
+}
+
+type messageCard struct {
+	Type            string          `json:"@type"`
+	Context         string          `json:"@context"`
+	Summary         string          `json:"summary"`
+	Title           string          `json:"title"`
+	Text            string          `json:"text,omitempty"`
+	ThemeColor      string          `json:"themeColor,omitempty"`
+	Sections        []section       `json:"sections,omitempty"`
+	PotentialAction []openURIAction `json:"potentialAction,omitempty"`
+}
+
+type section struct {
+	Text string `json:"text"`
+}
+
+type openURIAction struct {
+	Type    string      `json:"@type"`
+	Name    string      `json:"name"`
+	Targets []uriTarget `json:"targets"`
+}
+
+type uriTarget struct {
+	OS  string `json:"os"`
+	URI string `json:"uri"`
+}
+
+// Init read yaml config
+func (sender *Sender) Init(senderSettings map[string]string, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	sender.FrontURI = senderSettings["front_uri"]
+	sender.client = &http.Client{Timeout: defaultTimeout}
+	sender.log = logger
+	sender.location = location
+	return nil
+}
+
+// SendEvents implements Sender interface Send
+func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, throttled bool) error {
+	body, err := json.Marshal(sender.buildMessageCard(events, trigger, throttled))
+	if err != nil {
+		return err
+	}
+	sender.log.Debugf("Calling msteams webhook with body %s", string(body))
+
+	if err := senders.PostJSON(sender.client, contact.Value, body, nil); err != nil {
+		return fmt.Errorf("Failed to send message to msteams [%s]: %s", contact.ID, err.Error())
+	}
+	return nil
+}
+
+func (sender *Sender) buildMessageCard(events moira.NotificationEvents, trigger moira.TriggerData, throttled bool) *messageCard {
+	state := events.GetSubjectState()
+	title := strings.TrimSpace(events.GetMessageHeader(trigger, sender.FrontURI, titleMarkup))
+
+	lines := make([]string, 0, len(events))
+	for _, event := range events {
+		lines = append(lines, event.GetMessageLine(sender.location))
+	}
+	sections := []section{{Text: strings.Join(lines, "\n\n")}}
+	if throttled {
+		sections = append(sections, section{Text: markup.GetThrottledWarning()})
+	}
+
+	card := &messageCard{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		Summary:    title,
+		Title:      title,
+		Text:       trigger.Desc,
+		ThemeColor: strings.TrimPrefix(moira.GetSeverityModel().GetColor(state), "#"),
+		Sections:   sections,
+	}
+	if sender.FrontURI != "" && events[0].TriggerID != "" {
+		card.PotentialAction = []openURIAction{{
+			Type:    "OpenUri",
+			Name:    "Open in Moira",
+			Targets: []uriTarget{{OS: "default", URI: fmt.Sprintf("%s/trigger/%s", sender.FrontURI, events[0].TriggerID)}},
+		}}
+	}
+	return card
+}
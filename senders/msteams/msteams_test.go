@@ -0,0 +1,59 @@
+package msteams
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders/senderstest"
+	"github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSendEvents(t *testing.T) {
+	logger, _ := logging.GetLogger("MSTeams")
+	location, _ := time.LoadLocation("UTC")
+	trigger := senderstest.Trigger()
+	events := moira.NotificationEvents{
+		{TriggerID: trigger.ID, Metric: "disk.used", Value: &senderstest.Value, OldState: "OK", State: "WARN", Timestamp: 1500000000},
+		{TriggerID: trigger.ID, Metric: "disk.free", Value: &senderstest.Value, OldState: "WARN", State: "OK", Timestamp: 1500000060},
+	}
+
+	Convey("SendEvents tests", t, func() {
+		var received []messageCard
+		server := senderstest.NewServer(http.StatusOK)
+		defer server.Close()
+
+		contact := moira.ContactData{ID: "ContactID-000000000000001", Type: "msteams", Value: server.URL + "/webhook/xxx"}
+		sender := Sender{}
+		So(sender.Init(map[string]string{"front_uri": "http://moira"}, logger, location, ""), ShouldBeNil)
+
+		Convey("MessageCard is posted to webhook url", func() {
+			So(sender.SendEvents(events, contact, trigger, true), ShouldBeNil)
+			So(server.ReceivedJSON(&received), ShouldBeNil)
+			So(received, ShouldResemble, []messageCard{{
+				Type:       "MessageCard",
+				Context:    "https://schema.org/extensions",
+				Summary:    "WARN [test-tag-1] test trigger 1",
+				Title:      "WARN [test-tag-1] test trigger 1",
+				Text:       "disk usage",
+				ThemeColor: "cccc32",
+				Sections: []section{
+					{Text: "02:40: disk.used = 97.5 (OK to WARN)\n\n02:41: disk.free = 97.5 (WARN to OK)"},
+					{Text: "Please, **fix your system or tune this trigger** to generate less events."},
+				},
+				PotentialAction: []openURIAction{{
+					Type:    "OpenUri",
+					Name:    "Open in Moira",
+					Targets: []uriTarget{{OS: "default", URI: "http://moira/trigger/triggerID-0000000000001"}},
+				}},
+			}})
+		})
+
+		Convey("Error response", func() {
+			server.Status = http.StatusBadRequest
+			So(sender.SendEvents(events, contact, trigger, false), ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,193 @@
+package opsgenie
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
+)
+
+const (
+	defaultAPIURL     = "https://api.opsgenie.com"
+	defaultTimeout    = 30 * time.Second
+	alertSource       = "Moira"
+	maxMessageLength  = 130
+	maxAliasLength    = 512
+	createAlertPath   = "/v2/alerts"
+	closeAlertPathFmt = "/v2/alerts/%s/close?identifierType=alias"
+)
+
+// alertPriorities are priorities of severities, which are not warnings, from the most critical one,
+// less critical severities get the last priority
+var alertPriorities = []string{"P1", "P2", "P3"}
+
+const (
+	exceptionPriority = "P1"
+	warningPriority   = "P4"
+	testPriority      = "P5"
+)
+
+// Sender implements moira sender interface via Opsgenie Alert API
+type Sender struct {
+	APIURL   string
+	APIKey   string
+	FrontURI string
+	client   *http.Client
+	log      moira.Logger
+	location *time.Location
+}
+
+type createAlertRequest struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Details     map[string]string `json:"details,omitempty"`
+	Entity      string            `json:"entity,omitempty"`
+	Source      string            `json:"source"`
+	Priority    string            `json:"priority"`
+}
+
+type closeAlertRequest struct {
+	Source string `json:"source"`
+	Note   string `json:"note,omitempty"`
+}
+
+// Init read yaml config
+func (sender *Sender) Init(senderSettings map[string]string, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	sender.APIURL = strings.TrimSuffix(senderSettings["api_url"], "/")
+	if sender.APIURL == "" {
+		sender.APIURL = defaultAPIURL
+	}
+	sender.APIKey = senderSettings["api_key"]
+	sender.FrontURI = senderSettings["front_uri"]
+	sender.client = &http.Client{Timeout: defaultTimeout}
+	sender.log = logger
+	sender.location = location
+	return nil
+}
+
+// SendEvents implements Sender interface Send
+func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, throttled bool) error {
+	apiKey := contact.Value
+	if apiKey == "" {
+		apiKey = sender.APIKey
+	}
+	if apiKey == "" {
+		return fmt.Errorf("Failed to send events to Opsgenie: api key is not set neither in contact %s nor in sender settings", contact.ID)
+	}
+	for _, event := range events {
+		alias := getAlias(event)
+		var err error
+		if moira.GetSeverityModel().IsOK(event.State) {
+			err = sender.closeAlert(apiKey, alias, event)
+		} else {
+			err = sender.createAlert(apiKey, alias, event, trigger, throttled)
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to send alert %s to Opsgenie: %s", alias, err.Error())
+		}
+	}
+	return nil
+}
+
+func (sender *Sender) createAlert(apiKey string, alias string, event moira.NotificationEvent, trigger moira.TriggerData, throttled bool) error {
+	priority := getPriority(event.State)
+	entity := event.Metric
+	if event.IsTriggerEvent || entity == "" {
+		entity = trigger.Name
+	}
+	message := fmt.Sprintf("%s %s %s: %s", event.State, trigger.GetTags(), trigger.Name, entity)
+	if runes := []rune(message); len(runes) > maxMessageLength {
+		message = string(runes[:maxMessageLength])
+	}
+	details := map[string]string{
+		"trigger_id": event.TriggerID,
+		"state":      event.State,
+		"old_state":  event.OldState,
+		"value":      strconv.FormatFloat(moira.UseFloat64(event.Value), 'f', -1, 64),
+		"timestamp":  time.Unix(event.Timestamp, 0).In(sender.location).Format(time.RFC3339),
+	}
+	if sender.FrontURI != "" && event.TriggerID != "" {
+		details["link"] = fmt.Sprintf("%s/trigger/%s", sender.FrontURI, event.TriggerID)
+	}
+	var description bytes.Buffer
+	description.WriteString(trigger.Desc)
+	if eventMessage := moira.UseString(event.Message); eventMessage != "" {
+		description.WriteString(fmt.Sprintf("\n%s", eventMessage))
+	}
+	if link, ok := details["link"]; ok {
+		description.WriteString(fmt.Sprintf("\n%s", link))
+	}
+	if throttled {
+		description.WriteString("\nPlease, fix your system or tune this trigger to generate less events.")
+	}
+	request := &createAlertRequest{
+		Message:     message,
+		Alias:       alias,
+		Description: strings.TrimSpace(description.String()),
+		Tags:        trigger.Tags,
+		Details:     details,
+		Entity:      entity,
+		Source:      alertSource,
+		Priority:    priority,
+	}
+	return sender.post(apiKey, sender.APIURL+createAlertPath, request)
+}
+
+func (sender *Sender) closeAlert(apiKey string, alias string, event moira.NotificationEvent) error {
+	request := &closeAlertRequest{
+		Source: alertSource,
+		Note:   fmt.Sprintf("State changed from %s to %s", event.OldState, event.State),
+	}
+	return sender.post(apiKey, sender.APIURL+fmt.Sprintf(closeAlertPathFmt, url.PathEscape(alias)), request)
+}
+
+func (sender *Sender) post(apiKey string, requestURL string, data interface{}) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	sender.log.Debugf("Calling Opsgenie %s with body %s", requestURL, string(body))
+	return senders.PostJSON(sender.client, requestURL, body, map[string]string{"Authorization": fmt.Sprintf("GenieKey %s", apiKey)})
+}
+
+// getPriority maps moira state to Opsgenie alert priority by its place in severity model
+func getPriority(state string) string {
+	model := moira.GetSeverityModel()
+	switch {
+	case state == "EXCEPTION":
+		return exceptionPriority
+	case state == "TEST":
+		return testPriority
+	case model.IsWarning(state):
+		return warningPriority
+	}
+	rank, _ := model.GetAlertRank(state)
+	if rank < 0 || rank >= len(alertPriorities) {
+		return alertPriorities[len(alertPriorities)-1]
+	}
+	return alertPriorities[rank]
+}
+
+// getAlias returns alias which is the same for all events of trigger or trigger metric,
+// so Opsgenie deduplicates them into single open alert which is closed on OK.
+// Metric is replaced with its hash if alias is longer than Opsgenie allows
+func getAlias(event moira.NotificationEvent) string {
+	if event.IsTriggerEvent {
+		return fmt.Sprintf("moira:%s", event.TriggerID)
+	}
+	alias := fmt.Sprintf("moira:%s:%s", event.TriggerID, event.Metric)
+	if len(alias) > maxAliasLength {
+		return fmt.Sprintf("moira:%s:%x", event.TriggerID, sha1.Sum([]byte(event.Metric)))
+	}
+	return alias
+}
@@ -0,0 +1,160 @@
+package opsgenie
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders/senderstest"
+	"github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSendEvents(t *testing.T) {
+	logger, _ := logging.GetLogger("Opsgenie")
+	location, _ := time.LoadLocation("UTC")
+	trigger := senderstest.Trigger()
+	trigger.Tags = append(trigger.Tags, "test-tag-2")
+	contact := moira.ContactData{ID: "ContactID-000000000000001", Type: "opsgenie", Value: "contact-key"}
+
+	Convey("SendEvents tests", t, func() {
+		server := senderstest.NewServer(http.StatusAccepted)
+		defer server.Close()
+
+		sender := Sender{}
+		err := sender.Init(map[string]string{"api_url": server.URL + "/", "api_key": "global-key", "front_uri": "http://moira"}, logger, location, "")
+		So(err, ShouldBeNil)
+
+		Convey("Bad state creates alert and OK closes it by alias", func() {
+			events := moira.NotificationEvents{
+				{TriggerID: trigger.ID, Metric: "disk.used", Value: &senderstest.Value, OldState: "OK", State: "ERROR", Timestamp: 1500000000},
+				{TriggerID: trigger.ID, Metric: "disk.used", Value: &senderstest.Value, OldState: "ERROR", State: "OK", Timestamp: 1500000060},
+			}
+			err := sender.SendEvents(events, contact, trigger, false)
+			So(err, ShouldBeNil)
+			So(server.Requests, ShouldHaveLength, 2)
+
+			So(server.Requests[0].RequestURI, ShouldEqual, "/v2/alerts")
+			So(server.Requests[0].Header.Get("Authorization"), ShouldEqual, "GenieKey contact-key")
+			var created createAlertRequest
+			So(json.Unmarshal(server.Requests[0].Data, &created), ShouldBeNil)
+			So(created, ShouldResemble, createAlertRequest{
+				Message:     "ERROR [test-tag-1][test-tag-2] test trigger 1: disk.used",
+				Alias:       "moira:triggerID-0000000000001:disk.used",
+				Description: "disk usage\nhttp://moira/trigger/triggerID-0000000000001",
+				Tags:        []string{"test-tag-1", "test-tag-2"},
+				Details: map[string]string{
+					"trigger_id": "triggerID-0000000000001",
+					"state":      "ERROR",
+					"old_state":  "OK",
+					"value":      "97.5",
+					"timestamp":  "2017-07-14T02:40:00Z",
+					"link":       "http://moira/trigger/triggerID-0000000000001",
+				},
+				Entity:   "disk.used",
+				Source:   alertSource,
+				Priority: "P2",
+			})
+
+			So(server.Requests[1].RequestURI, ShouldEqual, "/v2/alerts/moira:triggerID-0000000000001:disk.used/close?identifierType=alias")
+			var closed closeAlertRequest
+			So(json.Unmarshal(server.Requests[1].Data, &closed), ShouldBeNil)
+			So(closed, ShouldResemble, closeAlertRequest{Source: alertSource, Note: "State changed from ERROR to OK"})
+		})
+
+		Convey("Priority is mapped from state", func() {
+			events := moira.NotificationEvents{
+				{TriggerID: trigger.ID, Metric: "m1", State: "EXCEPTION"},
+				{TriggerID: trigger.ID, Metric: "m2", State: "WARN"},
+				{TriggerID: trigger.ID, IsTriggerEvent: true, State: "NODATA"},
+			}
+			So(sender.SendEvents(events, contact, trigger, false), ShouldBeNil)
+			So(server.Requests, ShouldHaveLength, 3)
+			expected := []struct{ priority, alias string }{
+				{"P1", "moira:triggerID-0000000000001:m1"},
+				{"P4", "moira:triggerID-0000000000001:m2"},
+				{"P1", "moira:triggerID-0000000000001"},
+			}
+			for i, request := range server.Requests {
+				var created createAlertRequest
+				So(json.Unmarshal(request.Data, &created), ShouldBeNil)
+				So(created.Priority, ShouldEqual, expected[i].priority)
+				So(created.Alias, ShouldEqual, expected[i].alias)
+			}
+		})
+
+		Convey("Long message is truncated by characters and long alias is hashed", func() {
+			metric := strings.Repeat("диск.", 110)
+			events := moira.NotificationEvents{{TriggerID: trigger.ID, Metric: metric, State: "ERROR"}}
+			So(sender.SendEvents(events, contact, trigger, false), ShouldBeNil)
+			So(server.Requests, ShouldHaveLength, 1)
+			var created createAlertRequest
+			So(json.Unmarshal(server.Requests[0].Data, &created), ShouldBeNil)
+			So(utf8.ValidString(created.Message), ShouldBeTrue)
+			So(utf8.RuneCountInString(created.Message), ShouldEqual, maxMessageLength)
+			So(created.Alias, ShouldEqual, fmt.Sprintf("moira:triggerID-0000000000001:%x", sha1.Sum([]byte(metric))))
+		})
+
+		Convey("Global api key is used if contact value is empty", func() {
+			events := moira.NotificationEvents{{TriggerID: trigger.ID, Metric: "m1", State: "ERROR"}}
+			So(sender.SendEvents(events, moira.ContactData{ID: contact.ID}, trigger, false), ShouldBeNil)
+			So(server.Requests[0].Header.Get("Authorization"), ShouldEqual, "GenieKey global-key")
+		})
+
+		Convey("No api key", func() {
+			sender.APIKey = ""
+			events := moira.NotificationEvents{{TriggerID: trigger.ID, Metric: "m1", State: "ERROR"}}
+			So(sender.SendEvents(events, moira.ContactData{ID: contact.ID}, trigger, false), ShouldNotBeNil)
+			So(server.Requests, ShouldBeEmpty)
+		})
+
+		Convey("Error response stops sending", func() {
+			server.Status = http.StatusUnauthorized
+			events := moira.NotificationEvents{
+				{TriggerID: trigger.ID, Metric: "m1", State: "ERROR"},
+				{TriggerID: trigger.ID, Metric: "m2", State: "ERROR"},
+			}
+			So(sender.SendEvents(events, contact, trigger, false), ShouldNotBeNil)
+			So(server.Requests, ShouldHaveLength, 1)
+		})
+	})
+}
+
+func TestGetPriority(t *testing.T) {
+	Convey("Priorities of default model", t, func() {
+		So(getPriority("NODATA"), ShouldEqual, "P1")
+		So(getPriority("ERROR"), ShouldEqual, "P2")
+		So(getPriority("WARN"), ShouldEqual, "P4")
+		So(getPriority("EXCEPTION"), ShouldEqual, "P1")
+		So(getPriority("TEST"), ShouldEqual, "P5")
+	})
+
+	Convey("Priorities of custom model", t, func() {
+		model, err := moira.NewSeverityModel([]moira.Severity{
+			{Name: "OK"},
+			{Name: "WARN", Score: 1, Warning: true},
+			{Name: "ERROR", Score: 100},
+			{Name: "MAJOR", Score: 200},
+			{Name: "CRITICAL", Score: 500},
+			{Name: "NODATA", Score: 1000},
+		})
+		So(err, ShouldBeNil)
+		moira.SetSeverityModel(model)
+		defer func() {
+			defaultModel, _ := moira.NewSeverityModel(moira.DefaultSeverities)
+			moira.SetSeverityModel(defaultModel)
+		}()
+
+		So(getPriority("NODATA"), ShouldEqual, "P1")
+		So(getPriority("CRITICAL"), ShouldEqual, "P2")
+		So(getPriority("MAJOR"), ShouldEqual, "P3")
+		So(getPriority("ERROR"), ShouldEqual, "P3")
+		So(getPriority("WARN"), ShouldEqual, "P4")
+	})
+}
@@ -0,0 +1,168 @@
+package pagerduty
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
+)
+
+const (
+	defaultAPIURL  = "https://events.pagerduty.com/v2/enqueue"
+	defaultTimeout = 30 * time.Second
+	clientName     = "Moira"
+	actionTrigger  = "trigger"
+	actionResolve  = "resolve"
+
+	severityCritical = "critical"
+	severityError    = "error"
+	severityWarning  = "warning"
+	severityInfo     = "info"
+
+	maxDedupKeyLength = 255
+)
+
+// Sender implements moira sender interface via PagerDuty Events API v2
+type Sender struct {
+	APIURL   string
+	FrontURI string
+	client   *http.Client
+	log      moira.Logger
+	location *time.Location
+}
+
+type event struct {
+	RoutingKey  string   `json:"routing_key"`
+	EventAction string   `json:"event_action"`
+	DedupKey    string   `json:"dedup_key"`
+	Payload     *payload `json:"payload,omitempty"`
+	Client      string   `json:"client,omitempty"`
+	ClientURL   string   `json:"client_url,omitempty"`
+}
+
+type payload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	Timestamp     string            `json:"timestamp,omitempty"`
+	Component     string            `json:"component,omitempty"`
+	Group         string            `json:"group,omitempty"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+// Init read yaml config
+func (sender *Sender) Init(senderSettings map[string]string, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	sender.APIURL = senderSettings["api_url"]
+	if sender.APIURL == "" {
+		sender.APIURL = defaultAPIURL
+	}
+	sender.FrontURI = senderSettings["front_uri"]
+	sender.client = &http.Client{Timeout: defaultTimeout}
+	sender.log = logger
+	sender.location = location
+	return nil
+}
+
+// SendEvents implements Sender interface Send
+func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, throttled bool) error {
+	for _, notificationEvent := range events {
+		pagerDutyEvent := sender.buildEvent(notificationEvent, contact, trigger, throttled)
+		if err := sender.send(pagerDutyEvent); err != nil {
+			return fmt.Errorf("Failed to send %s event to PagerDuty [%s]: %s", pagerDutyEvent.EventAction, pagerDutyEvent.DedupKey, err.Error())
+		}
+	}
+	return nil
+}
+
+func (sender *Sender) buildEvent(notificationEvent moira.NotificationEvent, contact moira.ContactData, trigger moira.TriggerData, throttled bool) *event {
+	pagerDutyEvent := &event{
+		RoutingKey: contact.Value,
+		DedupKey:   getDedupKey(notificationEvent),
+		Client:     clientName,
+	}
+	if sender.FrontURI != "" && notificationEvent.TriggerID != "" {
+		pagerDutyEvent.ClientURL = fmt.Sprintf("%s/trigger/%s", sender.FrontURI, notificationEvent.TriggerID)
+	}
+	if moira.GetSeverityModel().IsOK(notificationEvent.State) {
+		pagerDutyEvent.EventAction = actionResolve
+		return pagerDutyEvent
+	}
+	pagerDutyEvent.EventAction = actionTrigger
+	severity := getSeverity(notificationEvent.State)
+
+	source := notificationEvent.Metric
+	if notificationEvent.IsTriggerEvent || source == "" {
+		source = trigger.Name
+	}
+	details := map[string]string{
+		"state":     notificationEvent.State,
+		"old_state": notificationEvent.OldState,
+		"value":     strconv.FormatFloat(moira.UseFloat64(notificationEvent.Value), 'f', -1, 64),
+	}
+	if trigger.Desc != "" {
+		details["description"] = trigger.Desc
+	}
+	if message := moira.UseString(notificationEvent.Message); message != "" {
+		details["message"] = message
+	}
+	if throttled {
+		details["throttled"] = "Please, fix your system or tune this trigger to generate less events."
+	}
+	pagerDutyEvent.Payload = &payload{
+		Summary:       fmt.Sprintf("%s %s %s: %s", notificationEvent.State, trigger.GetTags(), trigger.Name, source),
+		Source:        source,
+		Severity:      severity,
+		Timestamp:     time.Unix(notificationEvent.Timestamp, 0).In(sender.location).Format(time.RFC3339),
+		Component:     trigger.Name,
+		Group:         notificationEvent.TriggerID,
+		CustomDetails: details,
+	}
+	return pagerDutyEvent
+}
+
+func (sender *Sender) send(pagerDutyEvent *event) error {
+	body, err := json.Marshal(pagerDutyEvent)
+	if err != nil {
+		return err
+	}
+	sender.log.Debugf("Calling PagerDuty with body %s", string(body))
+	return senders.PostJSON(sender.client, sender.APIURL, body, nil)
+}
+
+// getSeverity maps moira state to PagerDuty event severity by its place in severity model:
+// the more critical half of severities, which are not warnings, is critical, the other half is error
+func getSeverity(state string) string {
+	model := moira.GetSeverityModel()
+	switch {
+	case state == "EXCEPTION":
+		return severityCritical
+	case state == "TEST":
+		return severityInfo
+	case model.IsWarning(state):
+		return severityWarning
+	}
+	rank, count := model.GetAlertRank(state)
+	if rank >= 0 && rank < (count+1)/2 {
+		return severityCritical
+	}
+	return severityError
+}
+
+// getDedupKey returns key which is the same for all events of trigger or trigger metric,
+// so PagerDuty groups them into single incident and resolves it on OK.
+// Metric is replaced with its hash if key is longer than PagerDuty allows
+func getDedupKey(notificationEvent moira.NotificationEvent) string {
+	if notificationEvent.IsTriggerEvent {
+		return fmt.Sprintf("moira:%s", notificationEvent.TriggerID)
+	}
+	dedupKey := fmt.Sprintf("moira:%s:%s", notificationEvent.TriggerID, notificationEvent.Metric)
+	if len(dedupKey) > maxDedupKeyLength {
+		return fmt.Sprintf("moira:%s:%x", notificationEvent.TriggerID, sha1.Sum([]byte(notificationEvent.Metric)))
+	}
+	return dedupKey
+}
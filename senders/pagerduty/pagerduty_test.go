@@ -0,0 +1,149 @@
+package pagerduty
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders/senderstest"
+	"github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSendEvents(t *testing.T) {
+	logger, _ := logging.GetLogger("PagerDuty")
+	location, _ := time.LoadLocation("UTC")
+	trigger := senderstest.Trigger()
+	contact := moira.ContactData{ID: "ContactID-000000000000001", Type: "pagerduty", Value: "routing-key-1"}
+
+	Convey("SendEvents tests", t, func() {
+		var received []event
+		server := senderstest.NewServer(http.StatusAccepted)
+		defer server.Close()
+
+		sender := Sender{}
+		err := sender.Init(map[string]string{"api_url": server.URL, "front_uri": "http://moira"}, logger, location, "")
+		So(err, ShouldBeNil)
+
+		Convey("Bad state triggers incident and OK resolves it with the same dedup key", func() {
+			events := moira.NotificationEvents{
+				{TriggerID: trigger.ID, Metric: "disk.used", Value: &senderstest.Value, OldState: "OK", State: "ERROR", Timestamp: 1500000000},
+				{TriggerID: trigger.ID, Metric: "disk.used", Value: &senderstest.Value, OldState: "ERROR", State: "OK", Timestamp: 1500000060},
+			}
+			err := sender.SendEvents(events, contact, trigger, false)
+			So(err, ShouldBeNil)
+			So(server.ReceivedJSON(&received), ShouldBeNil)
+			So(received, ShouldHaveLength, 2)
+			So(received[0], ShouldResemble, event{
+				RoutingKey:  "routing-key-1",
+				EventAction: actionTrigger,
+				DedupKey:    "moira:triggerID-0000000000001:disk.used",
+				Client:      clientName,
+				ClientURL:   "http://moira/trigger/triggerID-0000000000001",
+				Payload: &payload{
+					Summary:   "ERROR [test-tag-1] test trigger 1: disk.used",
+					Source:    "disk.used",
+					Severity:  "error",
+					Timestamp: "2017-07-14T02:40:00Z",
+					Component: "test trigger 1",
+					Group:     "triggerID-0000000000001",
+					CustomDetails: map[string]string{
+						"state":       "ERROR",
+						"old_state":   "OK",
+						"value":       "97.5",
+						"description": "disk usage",
+					},
+				},
+			})
+			So(received[1], ShouldResemble, event{
+				RoutingKey:  "routing-key-1",
+				EventAction: actionResolve,
+				DedupKey:    "moira:triggerID-0000000000001:disk.used",
+				Client:      clientName,
+				ClientURL:   "http://moira/trigger/triggerID-0000000000001",
+			})
+		})
+
+		Convey("Severity is mapped from state", func() {
+			events := moira.NotificationEvents{
+				{TriggerID: trigger.ID, Metric: "m1", State: "WARN"},
+				{TriggerID: trigger.ID, Metric: "m2", State: "NODATA"},
+				{TriggerID: trigger.ID, Metric: "m3", State: "EXCEPTION"},
+				{TriggerID: trigger.ID, IsTriggerEvent: true, State: "ERROR"},
+			}
+			So(sender.SendEvents(events, contact, trigger, true), ShouldBeNil)
+			So(server.ReceivedJSON(&received), ShouldBeNil)
+			So(received, ShouldHaveLength, 4)
+			So(received[0].Payload.Severity, ShouldEqual, "warning")
+			So(received[1].Payload.Severity, ShouldEqual, "critical")
+			So(received[2].Payload.Severity, ShouldEqual, "critical")
+			So(received[3].Payload.Severity, ShouldEqual, "error")
+			So(received[3].DedupKey, ShouldEqual, "moira:triggerID-0000000000001")
+			So(received[3].Payload.Source, ShouldEqual, trigger.Name)
+			So(received[0].Payload.CustomDetails["throttled"], ShouldNotBeEmpty)
+		})
+
+		Convey("Error response stops sending", func() {
+			server.Status = http.StatusBadRequest
+			events := moira.NotificationEvents{
+				{TriggerID: trigger.ID, Metric: "m1", State: "ERROR"},
+				{TriggerID: trigger.ID, Metric: "m2", State: "ERROR"},
+			}
+			So(sender.SendEvents(events, contact, trigger, false), ShouldNotBeNil)
+			So(server.Requests, ShouldHaveLength, 1)
+		})
+	})
+}
+
+func TestGetSeverity(t *testing.T) {
+	Convey("Severities of default model", t, func() {
+		So(getSeverity("WARN"), ShouldEqual, severityWarning)
+		So(getSeverity("ERROR"), ShouldEqual, severityError)
+		So(getSeverity("NODATA"), ShouldEqual, severityCritical)
+		So(getSeverity("EXCEPTION"), ShouldEqual, severityCritical)
+		So(getSeverity("TEST"), ShouldEqual, severityInfo)
+		So(getSeverity("UNKNOWN"), ShouldEqual, severityError)
+	})
+
+	Convey("Severities of custom model", t, func() {
+		model, err := moira.NewSeverityModel([]moira.Severity{
+			{Name: "OK"},
+			{Name: "INFO", Warning: true},
+			{Name: "WARN", Score: 1, Warning: true},
+			{Name: "ERROR", Score: 100},
+			{Name: "CRITICAL", Score: 500},
+			{Name: "NODATA", Score: 1000},
+		})
+		So(err, ShouldBeNil)
+		moira.SetSeverityModel(model)
+		defer moira.SetSeverityModel(defaultSeverityModel())
+
+		So(getSeverity("INFO"), ShouldEqual, severityWarning)
+		So(getSeverity("ERROR"), ShouldEqual, severityError)
+		So(getSeverity("CRITICAL"), ShouldEqual, severityCritical)
+		So(getSeverity("NODATA"), ShouldEqual, severityCritical)
+	})
+}
+
+func defaultSeverityModel() *moira.SeverityModel {
+	model, _ := moira.NewSeverityModel(moira.DefaultSeverities)
+	return model
+}
+
+func TestGetDedupKey(t *testing.T) {
+	Convey("Dedup key of trigger and metric events", t, func() {
+		So(getDedupKey(moira.NotificationEvent{TriggerID: "trigger1", IsTriggerEvent: true}), ShouldEqual, "moira:trigger1")
+		So(getDedupKey(moira.NotificationEvent{TriggerID: "trigger1", Metric: "disk.used"}), ShouldEqual, "moira:trigger1:disk.used")
+	})
+
+	Convey("Long metric is hashed", t, func() {
+		metric := strings.Repeat("metric.", 40)
+		dedupKey := getDedupKey(moira.NotificationEvent{TriggerID: "trigger1", Metric: metric})
+		So(dedupKey, ShouldEqual, fmt.Sprintf("moira:trigger1:%x", sha1.Sum([]byte(metric))))
+		So(len(dedupKey), ShouldBeLessThanOrEqualTo, maxDedupKeyLength)
+	})
+}
@@ -46,11 +46,8 @@ func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.
 		if i > 4 {
 			break
 		}
-		if event.State == "ERROR" || event.State == "EXCEPTION" {
-			priority = pushover.PriorityEmergency
-		}
-		if priority != pushover.PriorityEmergency && (event.State == "WARN" || event.State == "NODATA") {
-			priority = pushover.PriorityHigh
+		if eventPriority := getPriority(event.State); eventPriority > priority {
+			priority = eventPriority
 		}
 		value := strconv.FormatFloat(moira.UseFloat64(event.Value), 'f', -1, 64)
 		message.WriteString(fmt.Sprintf("%s: %s = %s (%s to %s)", time.Unix(event.Timestamp, 0).In(sender.location).Format("15:04"), event.Metric, value, event.OldState, event.State))
@@ -86,3 +83,19 @@ func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.
 	}
 	return nil
 }
+
+// getPriority maps moira state to pushover message priority by severity model:
+// exceptions and severities, which are neither OK nor warnings, are emergency, warnings are high
+func getPriority(state string) int {
+	model := moira.GetSeverityModel()
+	switch {
+	case state == "EXCEPTION":
+		return pushover.PriorityEmergency
+	case model.IsWarning(state):
+		return pushover.PriorityHigh
+	}
+	if rank, _ := model.GetAlertRank(state); rank >= 0 {
+		return pushover.PriorityEmergency
+	}
+	return pushover.PriorityNormal
+}
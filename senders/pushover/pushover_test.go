@@ -0,0 +1,39 @@
+package pushover
+
+import (
+	"testing"
+
+	"github.com/gregdel/pushover"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+)
+
+func TestGetPriority(t *testing.T) {
+	Convey("Priorities of default model", t, func() {
+		So(getPriority("OK"), ShouldEqual, pushover.PriorityNormal)
+		So(getPriority("WARN"), ShouldEqual, pushover.PriorityHigh)
+		So(getPriority("ERROR"), ShouldEqual, pushover.PriorityEmergency)
+		So(getPriority("NODATA"), ShouldEqual, pushover.PriorityEmergency)
+		So(getPriority("EXCEPTION"), ShouldEqual, pushover.PriorityEmergency)
+		So(getPriority("TEST"), ShouldEqual, pushover.PriorityNormal)
+	})
+
+	Convey("Priorities of custom model", t, func() {
+		defaultModel := moira.GetSeverityModel()
+		model, err := moira.NewSeverityModel([]moira.Severity{
+			{Name: "OK"},
+			{Name: "INFO", Warning: true},
+			{Name: "WARN", Score: 1, Warning: true},
+			{Name: "ERROR", Score: 100},
+			{Name: "CRITICAL", Score: 500},
+			{Name: "NODATA", Score: 1000},
+		})
+		So(err, ShouldBeNil)
+		moira.SetSeverityModel(model)
+		defer moira.SetSeverityModel(defaultModel)
+
+		So(getPriority("INFO"), ShouldEqual, pushover.PriorityHigh)
+		So(getPriority("CRITICAL"), ShouldEqual, pushover.PriorityEmergency)
+	})
+}
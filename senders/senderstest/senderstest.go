@@ -0,0 +1,66 @@
+// Package senderstest provides trigger, event value and HTTP server shared by senders tests
+package senderstest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/moira-alert/moira"
+)
+
+// Value is value of test events
+var Value = 97.5
+
+// Trigger returns trigger of test events
+func Trigger() moira.TriggerData {
+	return moira.TriggerData{
+		ID:   "triggerID-0000000000001",
+		Name: "test trigger 1",
+		Desc: "disk usage",
+		Tags: []string{"test-tag-1"},
+	}
+}
+
+// Request is request received by Server with its read body
+type Request struct {
+	*http.Request
+	Data []byte
+}
+
+// Server is HTTP server, which records received requests and responds with queued Statuses, then with Status
+type Server struct {
+	*httptest.Server
+	Status   int
+	Statuses []int
+	Requests []*Request
+}
+
+// NewServer starts Server responding with status
+func NewServer(status int) *Server {
+	server := &Server{Status: status}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		server.Requests = append(server.Requests, &Request{Request: r, Data: data})
+		status := server.Status
+		if len(server.Statuses) > 0 {
+			status, server.Statuses = server.Statuses[0], server.Statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	return server
+}
+
+// ReceivedJSON unmarshals JSON bodies of received requests into slice pointed by received
+func (server *Server) ReceivedJSON(received interface{}) error {
+	bodies := make([]json.RawMessage, 0, len(server.Requests))
+	for _, request := range server.Requests {
+		bodies = append(bodies, request.Data)
+	}
+	data, err := json.Marshal(bodies)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, received)
+}
@@ -3,7 +3,6 @@ package slack
 import (
 	"bytes"
 	"fmt"
-	"strconv"
 	"time"
 
 	"github.com/moira-alert/moira"
@@ -11,6 +10,9 @@ import (
 	"github.com/nlopes/slack"
 )
 
+// markup is Slack mrkdwn
+var markup = moira.MessageMarkup{Bold: "*", LinkFormat: "<%[2]s|%[1]s>"}
+
 // Sender implements moira sender interface via slack
 type Sender struct {
 	APIToken string
@@ -36,25 +38,16 @@ func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.
 	api := slack.New(sender.APIToken)
 
 	var message bytes.Buffer
-	state := events.GetSubjectState()
-	tags := trigger.GetTags()
-	message.WriteString(fmt.Sprintf("*%s* %s <%s/trigger/%s|%s>\n %s \n```", state, tags, sender.FrontURI, events[0].TriggerID, trigger.Name, trigger.Desc))
-	icon := fmt.Sprintf("%s/public/fav72_ok.png", sender.FrontURI)
+	message.WriteString(fmt.Sprintf("%s\n %s \n```", events.GetMessageHeader(trigger, sender.FrontURI, markup), trigger.Desc))
+	icon := events.GetIconURL(sender.FrontURI)
 	for _, event := range events {
-		if event.State != "OK" {
-			icon = fmt.Sprintf("%s/public/fav72_error.png", sender.FrontURI)
-		}
-		value := strconv.FormatFloat(moira.UseFloat64(event.Value), 'f', -1, 64)
-		message.WriteString(fmt.Sprintf("\n%s: %s = %s (%s to %s)", time.Unix(event.Timestamp, 0).In(sender.location).Format("15:04"), event.Metric, value, event.OldState, event.State))
-		if len(moira.UseString(event.Message)) > 0 {
-			message.WriteString(fmt.Sprintf(". %s", moira.UseString(event.Message)))
-		}
+		message.WriteString(fmt.Sprintf("\n%s", event.GetMessageLine(sender.location)))
 	}
 
 	message.WriteString("```")
 
 	if throttled {
-		message.WriteString("\nPlease, *fix your system or tune this trigger* to generate less events.")
+		message.WriteString(fmt.Sprintf("\n%s", markup.GetThrottledWarning()))
 	}
 
 	sender.log.Debugf("Calling slack with message body %s", message.String())
@@ -16,13 +16,6 @@ var (
 	pollerTimeout           = 10 * time.Second
 	databaseMutexExpiry     = 30 * time.Second
 	singlePollerStateExpiry = time.Minute
-	emojiStates             = map[string]string{
-		"OK":     "\xe2\x9c\x85",
-		"WARN":   "\xe2\x9a\xa0",
-		"ERROR":  "\xe2\xad\x95",
-		"NODATA": "\xf0\x9f\x92\xa3",
-		"TEST":   "\xf0\x9f\x98\x8a",
-	}
 )
 
 // Sender implements moira sender interface via telegram
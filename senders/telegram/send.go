@@ -14,7 +14,7 @@ func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.
 	var message bytes.Buffer
 	state := events.GetSubjectState()
 	tags := trigger.GetTags()
-	emoji := emojiStates[state]
+	emoji := moira.GetSeverityModel().GetEmoji(state)
 
 	message.WriteString(fmt.Sprintf("%s%s %s %s (%d)\n", emoji, state, trigger.Name, tags, len(events)))
 
@@ -0,0 +1,202 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/senders"
+)
+
+const (
+	defaultMethod      = http.MethodPost
+	defaultURL         = "{{ .Contact.Value }}"
+	defaultContentType = "application/json"
+	defaultTimeout     = 30 * time.Second
+	defaultMaxRetries  = 2
+	defaultRetryDelay  = 200 * time.Millisecond
+	headerPrefix       = "header_"
+)
+
+// Retries block sender goroutine, so they are kept short and are not started after retriesTimeout since the first attempt.
+// Notifier resends failed notifications later itself
+const (
+	retriesLimit    = 3
+	retryDelayLimit = time.Second
+	retriesTimeout  = 5 * time.Second
+)
+
+// Sender implements moira sender interface via HTTP request to webhook
+type Sender struct {
+	URL        *template.Template
+	Method     string
+	Headers    map[string]string
+	User       string
+	Password   string
+	Token      string
+	Body       *template.Template
+	MaxRetries int
+	RetryDelay time.Duration
+	FrontURI   string
+	client     *http.Client
+	log        moira.Logger
+}
+
+// payload is data available in URL and body templates, it is sent as JSON if body template is not set
+type payload struct {
+	Events    moira.NotificationEvents `json:"events"`
+	Trigger   moira.TriggerData        `json:"trigger"`
+	Contact   moira.ContactData        `json:"contact"`
+	Throttled bool                     `json:"throttled"`
+	FrontURI  string                   `json:"-"`
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(value interface{}) (string, error) {
+		bytes, err := json.Marshal(value)
+		return string(bytes), err
+	},
+}
+
+// Init read yaml config
+func (sender *Sender) Init(senderSettings map[string]string, logger moira.Logger, location *time.Location, dateTimeFormat string) error {
+	if senderSettings["name"] == "" {
+		return fmt.Errorf("Required name for sender type webhook")
+	}
+	var err error
+	url := senderSettings["url"]
+	if url == "" {
+		url = defaultURL
+	}
+	if sender.URL, err = template.New("url").Funcs(templateFuncs).Parse(url); err != nil {
+		return fmt.Errorf("Can not parse webhook url template: %s", err.Error())
+	}
+	if body := senderSettings["body"]; body != "" {
+		if sender.Body, err = template.New("body").Funcs(templateFuncs).Parse(body); err != nil {
+			return fmt.Errorf("Can not parse webhook body template: %s", err.Error())
+		}
+	}
+	sender.Method = strings.ToUpper(senderSettings["method"])
+	if sender.Method == "" {
+		sender.Method = defaultMethod
+	}
+	sender.Headers = map[string]string{"Content-Type": defaultContentType}
+	for key, value := range senderSettings {
+		if strings.HasPrefix(key, headerPrefix) {
+			sender.Headers[strings.TrimPrefix(key, headerPrefix)] = value
+		}
+	}
+	sender.User = senderSettings["user"]
+	sender.Password = senderSettings["password"]
+	sender.Token = senderSettings["token"]
+	if sender.Token != "" && sender.User != "" {
+		return fmt.Errorf("Webhook basic and bearer authorization can not be used together")
+	}
+	sender.MaxRetries = defaultMaxRetries
+	if maxRetries := senderSettings["max_retries"]; maxRetries != "" {
+		if sender.MaxRetries, err = strconv.Atoi(maxRetries); err != nil || sender.MaxRetries < 0 || sender.MaxRetries > retriesLimit {
+			return fmt.Errorf("Can not parse webhook max_retries %s, it must be from 0 to %d", maxRetries, retriesLimit)
+		}
+	}
+	if sender.RetryDelay, err = parseDuration(senderSettings["retry_delay"], defaultRetryDelay); err != nil {
+		return fmt.Errorf("Can not parse webhook retry_delay: %s", err.Error())
+	}
+	if sender.RetryDelay < 0 || sender.RetryDelay > retryDelayLimit {
+		return fmt.Errorf("Webhook retry_delay must be from 0 to %s", retryDelayLimit)
+	}
+	timeout, err := parseDuration(senderSettings["timeout"], defaultTimeout)
+	if err != nil {
+		return fmt.Errorf("Can not parse webhook timeout: %s", err.Error())
+	}
+	sender.client = &http.Client{Timeout: timeout}
+	sender.FrontURI = senderSettings["front_uri"]
+	sender.log = logger
+	return nil
+}
+
+// SendEvents implements Sender interface Send
+func (sender *Sender) SendEvents(events moira.NotificationEvents, contact moira.ContactData, trigger moira.TriggerData, throttled bool) error {
+	data := &payload{
+		Events:    events,
+		Trigger:   trigger,
+		Contact:   contact,
+		Throttled: throttled,
+		FrontURI:  sender.FrontURI,
+	}
+	url, err := executeTemplate(sender.URL, data)
+	if err != nil {
+		return fmt.Errorf("Failed to build webhook url: %s", err.Error())
+	}
+	body, err := sender.buildBody(data)
+	if err != nil {
+		return fmt.Errorf("Failed to build webhook body: %s", err.Error())
+	}
+
+	startedAt := time.Now()
+	for attempt := 0; ; attempt++ {
+		retryable, err := sender.send(url, body)
+		if err == nil {
+			return nil
+		}
+		if !retryable || attempt >= sender.MaxRetries || time.Since(startedAt) > retriesTimeout {
+			return fmt.Errorf("Failed to send webhook %s %s: %s", sender.Method, url, err.Error())
+		}
+		sender.log.Warningf("Failed to send webhook %s %s, retry %d of %d: %s", sender.Method, url, attempt+1, sender.MaxRetries, err.Error())
+		time.Sleep(sender.RetryDelay)
+	}
+}
+
+func (sender *Sender) buildBody(data *payload) ([]byte, error) {
+	if sender.Body == nil {
+		return json.Marshal(data)
+	}
+	body, err := executeTemplate(sender.Body, data)
+	return []byte(body), err
+}
+
+// send makes single request and returns error if response status is not successful,
+// failure is retryable only if server responds with 429 or 5xx status. Failed requests are not retried,
+// because they may have taken the whole timeout, they are resent by notifier
+func (sender *Sender) send(url string, body []byte) (bool, error) {
+	request, err := http.NewRequest(sender.Method, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	for name, value := range sender.Headers {
+		request.Header.Set(name, value)
+	}
+	if sender.User != "" {
+		request.SetBasicAuth(sender.User, sender.Password)
+	}
+	if sender.Token != "" {
+		request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", sender.Token))
+	}
+	sender.log.Debugf("Calling webhook %s %s with body %s", sender.Method, url, string(body))
+
+	err = senders.Do(sender.client, request)
+	if statusErr, ok := err.(senders.ErrResponseStatus); ok {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= http.StatusInternalServerError, err
+	}
+	return false, err
+}
+
+func executeTemplate(tmpl *template.Template, data *payload) (string, error) {
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, data); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+func parseDuration(value string, defaultValue time.Duration) (time.Duration, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	return time.ParseDuration(value)
+}
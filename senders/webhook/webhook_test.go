@@ -0,0 +1,155 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/mock/moira-alert"
+	"github.com/moira-alert/moira/senders/senderstest"
+	"github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var (
+	testTrigger = senderstest.Trigger()
+	testEvents  = moira.NotificationEvents{
+		{Metric: "test.metric.1", State: "ERROR", OldState: "OK", Timestamp: 1500000000},
+	}
+)
+
+func TestInit(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	logger := mock_moira_alert.NewMockLogger(mockCtrl)
+	location, _ := time.LoadLocation("UTC")
+
+	Convey("Init tests", t, func() {
+		sender := Sender{}
+
+		Convey("Empty name", func() {
+			err := sender.Init(map[string]string{}, logger, location, "")
+			So(err, ShouldResemble, fmt.Errorf("Required name for sender type webhook"))
+		})
+
+		Convey("Invalid body template", func() {
+			err := sender.Init(map[string]string{"name": "hook", "body": "{{ .Events"}, logger, location, "")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Basic and bearer authorization together", func() {
+			err := sender.Init(map[string]string{"name": "hook", "user": "user", "token": "token"}, logger, location, "")
+			So(err, ShouldResemble, fmt.Errorf("Webhook basic and bearer authorization can not be used together"))
+		})
+
+		Convey("Invalid max_retries", func() {
+			err := sender.Init(map[string]string{"name": "hook", "max_retries": "-1"}, logger, location, "")
+			So(err, ShouldResemble, fmt.Errorf("Can not parse webhook max_retries -1, it must be from 0 to 3"))
+			err = sender.Init(map[string]string{"name": "hook", "max_retries": "10"}, logger, location, "")
+			So(err, ShouldResemble, fmt.Errorf("Can not parse webhook max_retries 10, it must be from 0 to 3"))
+		})
+
+		Convey("Too long retry_delay", func() {
+			err := sender.Init(map[string]string{"name": "hook", "retry_delay": "1m"}, logger, location, "")
+			So(err, ShouldResemble, fmt.Errorf("Webhook retry_delay must be from 0 to 1s"))
+		})
+
+		Convey("Defaults", func() {
+			err := sender.Init(map[string]string{"name": "hook", "header_X-Source": "moira"}, logger, location, "")
+			So(err, ShouldBeNil)
+			So(sender.Method, ShouldEqual, http.MethodPost)
+			So(sender.Body, ShouldBeNil)
+			So(sender.MaxRetries, ShouldEqual, defaultMaxRetries)
+			So(sender.RetryDelay, ShouldEqual, defaultRetryDelay)
+			So(sender.Headers, ShouldResemble, map[string]string{"Content-Type": defaultContentType, "X-Source": "moira"})
+		})
+	})
+}
+
+func TestSendEvents(t *testing.T) {
+	logger, _ := logging.GetLogger("Webhook")
+	location, _ := time.LoadLocation("UTC")
+
+	Convey("SendEvents tests", t, func() {
+		server := senderstest.NewServer(http.StatusOK)
+		defer server.Close()
+
+		contact := moira.ContactData{ID: "ContactID-000000000000001", Type: "hook", Value: server.URL + "/hooks/1"}
+		settings := map[string]string{"name": "hook", "retry_delay": "1ms"}
+		sender := Sender{}
+
+		Convey("Default payload is sent as JSON to contact value", func() {
+			So(sender.Init(settings, logger, location, ""), ShouldBeNil)
+			err := sender.SendEvents(testEvents, contact, testTrigger, true)
+			So(err, ShouldBeNil)
+			So(server.Requests, ShouldHaveLength, 1)
+			So(server.Requests[0].Method, ShouldEqual, http.MethodPost)
+			So(server.Requests[0].URL.Path, ShouldEqual, "/hooks/1")
+			So(server.Requests[0].Header.Get("Content-Type"), ShouldEqual, defaultContentType)
+			var actual payload
+			So(json.Unmarshal(server.Requests[0].Data, &actual), ShouldBeNil)
+			So(actual, ShouldResemble, payload{Events: testEvents, Trigger: testTrigger, Contact: contact, Throttled: true})
+		})
+
+		Convey("Templated url, body, method, headers and basic auth", func() {
+			settings["url"] = server.URL + "/triggers/{{ .Trigger.ID }}"
+			settings["method"] = "put"
+			settings["body"] = `{"name": {{ json .Trigger.Name }}, "state": "{{ (index .Events 0).State }}", "contact": "{{ .Contact.ID }}"}`
+			settings["header_X-Source"] = "moira"
+			settings["user"] = "user"
+			settings["password"] = "secret"
+			So(sender.Init(settings, logger, location, ""), ShouldBeNil)
+			err := sender.SendEvents(testEvents, contact, testTrigger, false)
+			So(err, ShouldBeNil)
+			So(server.Requests, ShouldHaveLength, 1)
+			So(server.Requests[0].Method, ShouldEqual, http.MethodPut)
+			So(server.Requests[0].URL.Path, ShouldEqual, "/triggers/"+testTrigger.ID)
+			So(server.Requests[0].Header.Get("X-Source"), ShouldEqual, "moira")
+			user, password, ok := server.Requests[0].BasicAuth()
+			So(ok, ShouldBeTrue)
+			So(user, ShouldEqual, "user")
+			So(password, ShouldEqual, "secret")
+			So(string(server.Requests[0].Data), ShouldEqual, `{"name": "test trigger 1", "state": "ERROR", "contact": "ContactID-000000000000001"}`)
+		})
+
+		Convey("Bearer auth", func() {
+			settings["token"] = "token"
+			So(sender.Init(settings, logger, location, ""), ShouldBeNil)
+			So(sender.SendEvents(testEvents, contact, testTrigger, false), ShouldBeNil)
+			So(server.Requests[0].Header.Get("Authorization"), ShouldEqual, "Bearer token")
+		})
+
+		Convey("Retry on server errors", func() {
+			server.Statuses = []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}
+			So(sender.Init(settings, logger, location, ""), ShouldBeNil)
+			So(sender.SendEvents(testEvents, contact, testTrigger, false), ShouldBeNil)
+			So(server.Requests, ShouldHaveLength, 3)
+		})
+
+		Convey("Fail after max retries", func() {
+			server.Statuses = []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable}
+			So(sender.Init(settings, logger, location, ""), ShouldBeNil)
+			So(sender.SendEvents(testEvents, contact, testTrigger, false), ShouldNotBeNil)
+			So(server.Requests, ShouldHaveLength, 3)
+		})
+
+		Convey("No retry on client errors", func() {
+			server.Statuses = []int{http.StatusBadRequest}
+			So(sender.Init(settings, logger, location, ""), ShouldBeNil)
+			So(sender.SendEvents(testEvents, contact, testTrigger, false), ShouldNotBeNil)
+			So(server.Requests, ShouldHaveLength, 1)
+		})
+
+		Convey("No retry on failed requests", func() {
+			So(sender.Init(settings, logger, location, ""), ShouldBeNil)
+			server.Close()
+			retryable, err := sender.send(contact.Value, nil)
+			So(err, ShouldNotBeNil)
+			So(retryable, ShouldBeFalse)
+		})
+	})
+}
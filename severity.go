@@ -0,0 +1,194 @@
+package moira
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Severity describes trigger or metric state which can be evaluated by trigger
+type Severity struct {
+	// Name is used as a state value in triggers, events and expressions
+	Name string
+	// Score is added to trigger score for trigger and every its metric in this state, triggers with greater score are shown first
+	Score int64
+	// Transitions between OK and warning severities are not sent to subscriptions ignoring warnings
+	Warning bool
+	// Color is used by senders to highlight state, e.g. #cc0032
+	Color string
+	// Emoji is used by messenger senders to mark state
+	Emoji string
+}
+
+// SeverityModel is a list of severities ordered from the least to the most critical one.
+// The least critical severity must be OK, WARN, ERROR and NODATA are required because checker sets them itself
+type SeverityModel struct {
+	severities []Severity
+	levels     map[string]int
+}
+
+// Special states are not severities: they can not be evaluated by expressions and subscriptions never ignore their transitions
+var specialStates = map[string]Severity{
+	"EXCEPTION": {Name: "EXCEPTION", Score: 100000, Color: "#e14f4f"},
+	"DEL":       {Name: "DEL"},
+	"TEST":      {Name: "TEST", Emoji: "\xf0\x9f\x98\x8a"},
+}
+
+var requiredSeverities = []string{"OK", "WARN", "ERROR", "NODATA"}
+
+// Names used by trigger expressions for other values can not be used as severity names
+var reservedSeverityNames = map[string]bool{
+	"WARNING":     true,
+	"WARN_VALUE":  true,
+	"ERROR_VALUE": true,
+	"PREV_STATE":  true,
+}
+
+var severityNameRegexp = regexp.MustCompile("^[A-Z][A-Z0-9_]*$")
+
+// DefaultSeverities are OK, WARN, ERROR and NODATA states
+var DefaultSeverities = []Severity{
+	{Name: "OK", Score: 0, Color: "#33cc99", Emoji: "\xe2\x9c\x85"},
+	{Name: "WARN", Score: 1, Warning: true, Color: "#cccc32", Emoji: "\xe2\x9a\xa0"},
+	{Name: "ERROR", Score: 100, Color: "#cc0032", Emoji: "\xe2\xad\x95"},
+	{Name: "NODATA", Score: 1000, Color: "#d3d3d3", Emoji: "\xf0\x9f\x92\xa3"},
+}
+
+var severityModel, _ = NewSeverityModel(DefaultSeverities)
+
+// NewSeverityModel validates ordered severities and creates severity model
+func NewSeverityModel(severities []Severity) (*SeverityModel, error) {
+	model := &SeverityModel{
+		severities: make([]Severity, 0, len(severities)),
+		levels:     make(map[string]int, len(severities)),
+	}
+	for level, severity := range severities {
+		if !severityNameRegexp.MatchString(severity.Name) {
+			return nil, fmt.Errorf("severity name '%s' must consist of uppercase letters, digits and underscores", severity.Name)
+		}
+		if _, ok := specialStates[severity.Name]; ok || reservedSeverityNames[severity.Name] {
+			return nil, fmt.Errorf("severity name '%s' is reserved", severity.Name)
+		}
+		if _, ok := model.levels[severity.Name]; ok {
+			return nil, fmt.Errorf("severity '%s' is defined twice", severity.Name)
+		}
+		model.levels[severity.Name] = level
+		model.severities = append(model.severities, severity)
+	}
+	for _, name := range requiredSeverities {
+		if _, ok := model.levels[name]; !ok {
+			return nil, fmt.Errorf("severity '%s' is required", name)
+		}
+	}
+	if model.levels["OK"] != 0 {
+		return nil, fmt.Errorf("severity 'OK' must be the least critical one")
+	}
+	return model, nil
+}
+
+// SetSeverityModel sets severity model used by all moira services, it must be called before services start
+func SetSeverityModel(model *SeverityModel) {
+	severityModel = model
+}
+
+// GetSeverityModel returns severity model used by all moira services
+func GetSeverityModel() *SeverityModel {
+	return severityModel
+}
+
+// Names returns severity names ordered from the least to the most critical one
+func (model *SeverityModel) Names() []string {
+	names := make([]string, 0, len(model.severities))
+	for _, severity := range model.severities {
+		names = append(names, severity.Name)
+	}
+	return names
+}
+
+// IsSeverity checks that state is one of severities
+func (model *SeverityModel) IsSeverity(state string) bool {
+	_, ok := model.levels[state]
+	return ok
+}
+
+// IsKnownState checks that state is one of severities or special states: EXCEPTION, DEL or TEST
+func (model *SeverityModel) IsKnownState(state string) bool {
+	_, ok := specialStates[state]
+	return ok || model.IsSeverity(state)
+}
+
+// Compare returns positive number if first severity is more critical than second one, negative if it is less critical
+// and zero if severities are equal or any of states is not a severity
+func (model *SeverityModel) Compare(first, second string) int {
+	firstLevel, ok := model.levels[first]
+	if !ok {
+		return 0
+	}
+	secondLevel, ok := model.levels[second]
+	if !ok {
+		return 0
+	}
+	return firstLevel - secondLevel
+}
+
+// IsWarningTransition checks that states are different and each of them is either OK or warning severity
+func (model *SeverityModel) IsWarningTransition(oldState, newState string) bool {
+	if oldState == newState {
+		return false
+	}
+	return model.isOKOrWarning(oldState) && model.isOKOrWarning(newState)
+}
+
+func (model *SeverityModel) isOKOrWarning(state string) bool {
+	return model.IsOK(state) || model.IsWarning(state)
+}
+
+// IsOK checks that state is the least critical severity, which means that trigger or metric is recovered
+func (model *SeverityModel) IsOK(state string) bool {
+	level, ok := model.levels[state]
+	return ok && level == 0
+}
+
+// GetAlertRank returns position of severity, which is neither OK nor warning, among such severities counted from the most critical one,
+// and number of such severities. Rank is -1 for other states
+func (model *SeverityModel) GetAlertRank(state string) (int, int) {
+	rank, count := -1, 0
+	for level := len(model.severities) - 1; level > 0; level-- {
+		severity := model.severities[level]
+		if severity.Warning {
+			continue
+		}
+		if severity.Name == state {
+			rank = count
+		}
+		count++
+	}
+	return rank, count
+}
+
+// IsWarning checks that state is warning severity
+func (model *SeverityModel) IsWarning(state string) bool {
+	level, ok := model.levels[state]
+	return ok && model.severities[level].Warning
+}
+
+// GetScore returns score of state
+func (model *SeverityModel) GetScore(state string) int64 {
+	return model.get(state).Score
+}
+
+// GetColor returns color of state, empty string is returned if color is not set
+func (model *SeverityModel) GetColor(state string) string {
+	return model.get(state).Color
+}
+
+// GetEmoji returns emoji of state, empty string is returned if emoji is not set
+func (model *SeverityModel) GetEmoji(state string) string {
+	return model.get(state).Emoji
+}
+
+func (model *SeverityModel) get(state string) Severity {
+	if level, ok := model.levels[state]; ok {
+		return model.severities[level]
+	}
+	return specialStates[state]
+}
@@ -0,0 +1,149 @@
+package moira
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var customSeverities = []Severity{
+	{Name: "OK", Score: 0, Color: "#33cc99"},
+	{Name: "INFO", Score: 0, Warning: true},
+	{Name: "WARN", Score: 1, Warning: true},
+	{Name: "ERROR", Score: 100},
+	{Name: "CRITICAL", Score: 500, Emoji: "\xf0\x9f\x94\xa5"},
+	{Name: "NODATA", Score: 1000},
+}
+
+func TestNewSeverityModel(t *testing.T) {
+	Convey("Default severities are valid", t, func() {
+		model, err := NewSeverityModel(DefaultSeverities)
+		So(err, ShouldBeNil)
+		So(model.Names(), ShouldResemble, []string{"OK", "WARN", "ERROR", "NODATA"})
+	})
+
+	Convey("Custom severities are valid", t, func() {
+		model, err := NewSeverityModel(customSeverities)
+		So(err, ShouldBeNil)
+		So(model.Names(), ShouldResemble, []string{"OK", "INFO", "WARN", "ERROR", "CRITICAL", "NODATA"})
+	})
+
+	Convey("Required severity is missing", t, func() {
+		_, err := NewSeverityModel([]Severity{{Name: "OK"}, {Name: "WARN"}, {Name: "NODATA"}})
+		So(err, ShouldResemble, fmt.Errorf("severity 'ERROR' is required"))
+	})
+
+	Convey("OK is not the least critical severity", t, func() {
+		_, err := NewSeverityModel([]Severity{{Name: "INFO"}, {Name: "OK"}, {Name: "WARN"}, {Name: "ERROR"}, {Name: "NODATA"}})
+		So(err, ShouldResemble, fmt.Errorf("severity 'OK' must be the least critical one"))
+	})
+
+	Convey("Severity is defined twice", t, func() {
+		_, err := NewSeverityModel([]Severity{{Name: "OK"}, {Name: "WARN"}, {Name: "WARN"}, {Name: "ERROR"}, {Name: "NODATA"}})
+		So(err, ShouldResemble, fmt.Errorf("severity 'WARN' is defined twice"))
+	})
+
+	Convey("Severity name is reserved", t, func() {
+		for _, name := range []string{"EXCEPTION", "DEL", "TEST", "WARNING", "PREV_STATE"} {
+			_, err := NewSeverityModel([]Severity{{Name: "OK"}, {Name: name}, {Name: "WARN"}, {Name: "ERROR"}, {Name: "NODATA"}})
+			So(err, ShouldResemble, fmt.Errorf("severity name '%s' is reserved", name))
+		}
+	})
+
+	Convey("Severity name is invalid", t, func() {
+		for _, name := range []string{"", "info", "1INFO", "IN-FO"} {
+			_, err := NewSeverityModel([]Severity{{Name: "OK"}, {Name: name}, {Name: "WARN"}, {Name: "ERROR"}, {Name: "NODATA"}})
+			So(err, ShouldResemble, fmt.Errorf("severity name '%s' must consist of uppercase letters, digits and underscores", name))
+		}
+	})
+}
+
+func TestSeverityModel(t *testing.T) {
+	model, _ := NewSeverityModel(customSeverities)
+
+	Convey("Check states", t, func() {
+		So(model.IsSeverity("CRITICAL"), ShouldBeTrue)
+		So(model.IsSeverity("EXCEPTION"), ShouldBeFalse)
+		So(model.IsKnownState("EXCEPTION"), ShouldBeTrue)
+		So(model.IsKnownState("DEL"), ShouldBeTrue)
+		So(model.IsKnownState("FATAL"), ShouldBeFalse)
+	})
+
+	Convey("Compare severities", t, func() {
+		So(model.Compare("CRITICAL", "ERROR"), ShouldBeGreaterThan, 0)
+		So(model.Compare("INFO", "WARN"), ShouldBeLessThan, 0)
+		So(model.Compare("ERROR", "ERROR"), ShouldEqual, 0)
+		So(model.Compare("EXCEPTION", "OK"), ShouldEqual, 0)
+	})
+
+	Convey("Check warning transitions", t, func() {
+		So(model.IsWarningTransition("OK", "INFO"), ShouldBeTrue)
+		So(model.IsWarningTransition("WARN", "INFO"), ShouldBeTrue)
+		So(model.IsWarningTransition("INFO", "INFO"), ShouldBeFalse)
+		So(model.IsWarningTransition("INFO", "CRITICAL"), ShouldBeFalse)
+		So(model.IsWarningTransition("OK", "EXCEPTION"), ShouldBeFalse)
+		So(model.IsWarning("INFO"), ShouldBeTrue)
+		So(model.IsWarning("OK"), ShouldBeFalse)
+		So(model.IsOK("OK"), ShouldBeTrue)
+		So(model.IsOK("INFO"), ShouldBeFalse)
+		So(model.IsOK("EXCEPTION"), ShouldBeFalse)
+	})
+
+	Convey("Get alert rank", t, func() {
+		rank, count := model.GetAlertRank("NODATA")
+		So(rank, ShouldEqual, 0)
+		So(count, ShouldEqual, 3)
+		rank, _ = model.GetAlertRank("ERROR")
+		So(rank, ShouldEqual, 2)
+		rank, _ = model.GetAlertRank("WARN")
+		So(rank, ShouldEqual, -1)
+		rank, _ = model.GetAlertRank("OK")
+		So(rank, ShouldEqual, -1)
+		rank, _ = model.GetAlertRank("EXCEPTION")
+		So(rank, ShouldEqual, -1)
+	})
+
+	Convey("Get severity settings", t, func() {
+		So(model.GetScore("CRITICAL"), ShouldEqual, 500)
+		So(model.GetScore("EXCEPTION"), ShouldEqual, 100000)
+		So(model.GetScore("UNKNOWN"), ShouldEqual, 0)
+		So(model.GetColor("OK"), ShouldEqual, "#33cc99")
+		So(model.GetColor("INFO"), ShouldBeEmpty)
+		So(model.GetEmoji("CRITICAL"), ShouldEqual, "\xf0\x9f\x94\xa5")
+	})
+}
+
+func TestCustomSeverityModel(t *testing.T) {
+	model, _ := NewSeverityModel(customSeverities)
+	SetSeverityModel(model)
+	defer func() {
+		defaultModel, _ := NewSeverityModel(DefaultSeverities)
+		SetSeverityModel(defaultModel)
+	}()
+
+	Convey("Get subject state", t, func() {
+		So(NotificationEvents{{State: "ERROR"}, {State: "CRITICAL"}, {State: "INFO"}}.GetSubjectState(), ShouldEqual, "CRITICAL")
+		So(NotificationEvents{{State: "OK"}, {State: "INFO"}}.GetSubjectState(), ShouldEqual, "INFO")
+	})
+
+	Convey("Update score", t, func() {
+		checkData := CheckData{
+			State: "CRITICAL",
+			Metrics: map[string]MetricState{
+				"metric1": {State: "INFO"},
+				"metric2": {State: "CRITICAL"},
+			},
+		}
+		So(checkData.UpdateScore(), ShouldEqual, 1000)
+	})
+
+	Convey("Ignore transitions", t, func() {
+		subscription := SubscriptionData{IgnoreRecoverings: true, IgnoreWarnings: true}
+		So(subscription.MustIgnore(&NotificationEvent{OldState: "OK", State: "INFO"}), ShouldBeTrue)
+		So(subscription.MustIgnore(&NotificationEvent{OldState: "INFO", State: "WARN"}), ShouldBeTrue)
+		So(subscription.MustIgnore(&NotificationEvent{OldState: "ERROR", State: "CRITICAL"}), ShouldBeFalse)
+		So(subscription.MustIgnore(&NotificationEvent{OldState: "CRITICAL", State: "ERROR"}), ShouldBeTrue)
+		So(subscription.MustIgnore(&NotificationEvent{OldState: "INFO", State: "EXCEPTION"}), ShouldBeFalse)
+	})
+}